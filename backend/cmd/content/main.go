@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/config"
+	"eight-gu-learning-platform/internal/database"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/service"
+)
+
+const usage = `Usage: content [-env dev] <command> [flags]
+
+Commands:
+  export [-format yaml|json] [-o FILE]   导出全部内容（默认输出到标准输出）
+  import [-dry-run] FILE                 按 slug 幂等导入内容（.yaml/.yml/.json）
+
+内容格式说明见 docs/content-format.md
+`
+
+func main() {
+	env := flag.String("env", "dev", "Environment (dev, prod)")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 加载配置
+	cfg, err := config.LoadConfig(*env)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// 连接数据库
+	db, err := database.NewDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.CloseDB(db)
+
+	// 连接 Redis（用于导入后清除缓存，不可用时仅提示）
+	var redisClient *cache.Cache
+	if cfg.Cache.Enabled {
+		redisClient, err = cache.NewCache(&cfg.Redis)
+		if err != nil {
+			log.Printf("Warning: redis unavailable, cache will not be invalidated: %v", err)
+			redisClient = nil
+		} else {
+			defer redisClient.Close()
+		}
+	}
+
+	transferService := service.NewContentTransferService(
+		repository.NewContentRepository(db), cache.NewReadThrough(redisClient))
+
+	args := flag.Args()[1:]
+	switch flag.Arg(0) {
+	case "export":
+		runExport(transferService, args)
+	case "import":
+		runImport(transferService, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// runExport 导出内容
+func runExport(transferService *service.ContentTransferService, args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "Output format (yaml, json); inferred from -o when empty")
+	output := fs.String("o", "", "Output file (default stdout)")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = formatFromPath(*output)
+	}
+
+	bundle, err := transferService.Export()
+	if err != nil {
+		log.Fatalf("Failed to export content: %v", err)
+	}
+	data, err := service.EncodeContentBundle(bundle, *format)
+	if err != nil {
+		log.Fatalf("Failed to encode content: %v", err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d categories, %d knowledge points, %d relations, %d exercises to %s\n",
+		len(bundle.Categories), len(bundle.Knowledge), len(bundle.Relations), len(bundle.Exercises), *output)
+}
+
+// runImport 导入内容
+func runImport(transferService *service.ContentTransferService, args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Show changes without writing")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatalf("import requires exactly one file")
+	}
+	path := fs.Arg(0)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	bundle, err := service.DecodeContentBundle(data, formatFromPath(path))
+	if err == nil {
+		var result *service.ImportResult
+		result, err = transferService.Import(bundle, *dryRun)
+		if err == nil {
+			printResult(result)
+			return
+		}
+	}
+
+	var validationErr *service.ContentValidationError
+	if errors.As(err, &validationErr) {
+		fmt.Fprintln(os.Stderr, "Validation failed:")
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		os.Exit(1)
+	}
+	log.Fatalf("Failed to import content: %v", err)
+}
+
+// printResult 输出导入变更
+func printResult(result *service.ImportResult) {
+	for _, change := range result.Changes {
+		line := fmt.Sprintf("%-8s %-10s %s", change.Action, change.Kind, change.Key)
+		if len(change.Fields) > 0 {
+			line += " (" + strings.Join(change.Fields, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+
+	mode := "Imported"
+	if result.DryRun {
+		mode = "Dry run, nothing written"
+	}
+	fmt.Printf("%s: %d created, %d updated, %d restored, %d unchanged\n",
+		mode, result.Created, result.Updated, result.Restored, result.Unchanged)
+}
+
+// formatFromPath 根据文件扩展名判断格式
+func formatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return service.FormatJSON
+	}
+	return service.FormatYAML
+}
@@ -1,102 +1,144 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"strconv"
 
 	"eight-gu-learning-platform/internal/config"
-	"eight-gu-learning-platform/internal/models"
-
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"eight-gu-learning-platform/internal/database"
+	"eight-gu-learning-platform/internal/migrate"
 )
 
+const usage = `Usage: migrate [-env dev] [-dir migrations] <command> [arg]
+
+Commands:
+  up [N]          执行未执行的迁移（默认全部）
+  down [N]        回滚最近执行的 N 个迁移（默认 1 个）
+  status          查看迁移状态
+  force VERSION   将数据库标记为指定版本，不执行脚本（接管已有数据库或修复校验和漂移）
+`
+
 func main() {
 	// 解析命令行参数
 	env := flag.String("env", "dev", "Environment (dev, prod)")
-	action := flag.String("action", "up", "Migration action (up, down)")
+	dir := flag.String("dir", "migrations", "Migrations directory")
+	action := flag.String("action", "", "Deprecated: use the positional command instead")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
 	flag.Parse()
 
+	command := flag.Arg(0)
+	if command == "" {
+		command = *action
+	}
+	if command == "" {
+		command = "up"
+	}
+
 	// 加载配置
 	cfg, err := config.LoadConfig(*env)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// 加载迁移文件
+	migrations, err := migrate.Load(os.DirFS(*dir))
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
 	// 连接数据库
-	db, err := gorm.Open(postgres.Open(cfg.Database.GetDSN()), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	db, err := database.NewDB(&cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
+	defer database.CloseDB(db)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database: %v", err)
+	}
+
+	migrator := migrate.New(sqlDB, migrations)
+	ctx := context.Background()
 
 	// 执行迁移
-	switch *action {
+	switch command {
 	case "up":
-		if err := migrateUp(db); err != nil {
+		done, err := migrator.Up(ctx, intArg(1, 0))
+		report("up", done)
+		if err != nil {
 			log.Fatalf("Failed to migrate up: %v", err)
 		}
-		fmt.Println("Migration up completed successfully")
 	case "down":
-		if err := migrateDown(db); err != nil {
+		done, err := migrator.Down(ctx, intArg(1, 1))
+		report("down", done)
+		if err != nil {
 			log.Fatalf("Failed to migrate down: %v", err)
 		}
-		fmt.Println("Migration down completed successfully")
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		printStatus(statuses)
+	case "force":
+		if flag.NArg() < 2 {
+			log.Fatalf("force requires a version")
+		}
+		version := int64(intArg(1, 0))
+		if err := migrator.Force(ctx, version); err != nil {
+			log.Fatalf("Failed to force version: %v", err)
+		}
+		fmt.Printf("Forced migration version to %d\n", version)
 	default:
-		log.Fatalf("Invalid action: %s. Use 'up' or 'down'", *action)
+		flag.Usage()
+		os.Exit(2)
 	}
 }
 
-// migrateUp 执行向上迁移
-func migrateUp(db *gorm.DB) error {
-	// 注册所有模型
-	models := []interface{}{
-		&models.User{},
-		&models.Category{},
-		&models.KnowledgePoint{},
-		&models.KnowledgeRelation{},
-		&models.LearningProgress{},
-		&models.Exercise{},
-		&models.ExerciseRecord{},
+// intArg 读取位置参数中的整数，缺省时返回默认值
+func intArg(i, def int) int {
+	if flag.NArg() <= i {
+		return def
 	}
-
-	// 自动迁移
-	for _, model := range models {
-		if err := db.AutoMigrate(model); err != nil {
-			return fmt.Errorf("failed to migrate %T: %w", model, err)
-		}
+	n, err := strconv.Atoi(flag.Arg(i))
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid number: %s", flag.Arg(i))
 	}
-
-	return nil
+	return n
 }
 
-// migrateDown 执行向下迁移
-func migrateDown(db *gorm.DB) error {
-	// 使用 SQL 文件回滚
-	migrationsDir := "migrations"
-
-	// 找到 down 迁移文件
-	downFile := filepath.Join(migrationsDir, "001_init_schema.down.sql")
-	sql, err := os.ReadFile(downFile)
-	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
+// report 输出已执行的迁移
+func report(direction string, done []migrate.Migration) {
+	if len(done) == 0 {
+		fmt.Println("No migrations to run")
+		return
 	}
-
-	// 获取底层 SQL DB
-	sqlDB, err := db.DB()
-	if err != nil {
-		return fmt.Errorf("failed to get database: %w", err)
+	for _, m := range done {
+		fmt.Printf("%s: %03d_%s\n", direction, m.Version, m.Name)
 	}
+	fmt.Printf("Migration %s completed successfully (%d applied)\n", direction, len(done))
+}
 
-	// 执行 SQL
-	if _, err := sqlDB.Exec(string(sql)); err != nil {
-		return fmt.Errorf("failed to execute migration SQL: %w", err)
+// printStatus 输出迁移状态表
+func printStatus(statuses []migrate.Status) {
+	fmt.Printf("%-8s %-32s %-8s %-20s %s\n", "VERSION", "NAME", "APPLIED", "APPLIED AT", "DRIFT")
+	for _, s := range statuses {
+		applied, appliedAt, drift := "no", "-", ""
+		if s.Applied {
+			applied = "yes"
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if s.Drift {
+			drift = "CHANGED"
+		}
+		if s.Missing {
+			drift = "MISSING"
+		}
+		fmt.Printf("%03d      %-32s %-8s %-20s %s\n", s.Version, s.Name, applied, appliedAt, drift)
 	}
-
-	return nil
 }
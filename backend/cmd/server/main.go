@@ -14,8 +14,12 @@ import (
 	"eight-gu-learning-platform/internal/config"
 	"eight-gu-learning-platform/internal/database"
 	"eight-gu-learning-platform/internal/handler"
+	"eight-gu-learning-platform/internal/mailer"
 	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/oauth"
 	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/sandbox"
 	"eight-gu-learning-platform/internal/service"
 	"eight-gu-learning-platform/internal/utils"
 
@@ -59,39 +63,152 @@ func main() {
 	exerciseRepo := repository.NewExerciseRepository(db)
 	recordRepo := repository.NewRecordRepository(db)
 	relationRepo := repository.NewRelationRepository(db)
+	contentRepo := repository.NewContentRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	examRepo := repository.NewExamRepository(db)
+	dashboardRepo := repository.NewDashboardRepository(db)
+	studyRepo := repository.NewStudyRepository(db)
+	loginAuditRepo := repository.NewLoginAuditRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	personalTokenRepo := repository.NewPersonalTokenRepository(db)
+
+	// 初始化读穿透缓存（关闭时直接回源）
+	cacheBackend := redisClient
+	if !cfg.Cache.Enabled {
+		cacheBackend = nil
+	}
+	readThrough := cache.NewReadThrough(cacheBackend)
+	cachedCategoryRepo := repository.NewCachedCategoryRepository(categoryRepo, readThrough, cfg.Cache.TTL)
+
+	// 初始化代码题沙箱，当前环境不支持时禁用代码题而不是阻止启动
+	var codeRunner *sandbox.Runner
+	if cfg.Sandbox.Enabled {
+		codeRunner, err = sandbox.NewRunner(&cfg.Sandbox)
+		if err != nil {
+			log.Printf("Warning: code sandbox unavailable, code exercises disabled: %v", err)
+			codeRunner = nil
+		}
+	}
+
+	// 初始化邮件发送器
+	mail, err := mailer.New(&cfg.Mail)
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
+
+	// 初始化第三方登录提供方
+	oauthProviders, err := oauth.New(&cfg.OAuth)
+	if err != nil {
+		log.Fatalf("Failed to initialize oauth providers: %v", err)
+	}
+
+	// 初始化两步验证密钥加密
+	mfaBox, err := utils.NewSecretBox(cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize mfa encryption: %v", err)
+	}
 
 	// 初始化 Service
-	authService := service.NewAuthService(userRepo, jwtMgr)
-	userService := service.NewUserService(userRepo)
-	knowledgeService := service.NewKnowledgeService(knowledgeRepo, categoryRepo, relationRepo)
+	tokenService := service.NewTokenService(redisClient, cfg.JWT.ExpireTime, cfg.JWT.RefreshExpireTime)
+	personalTokenService := service.NewPersonalTokenService(personalTokenRepo, userRepo)
+	accountService := service.NewAccountService(userRepo, redisClient, mail, tokenService, personalTokenService,
+		cfg.Mail.BaseURL, cfg.Account.VerifyTokenTTL, cfg.Account.ResetTokenTTL)
+	var loginLimiter *cache.LoginGuard
+	if cfg.LoginProtection.Enabled {
+		loginLimiter = cache.NewLoginGuard(redisClient)
+	}
+	loginGuard := service.NewLoginGuard(loginLimiter,
+		cache.LoginLockPolicy{
+			Threshold:   cfg.LoginProtection.AccountThreshold,
+			Window:      cfg.LoginProtection.Window,
+			BaseLockout: cfg.LoginProtection.BaseLockout,
+			MaxLockout:  cfg.LoginProtection.MaxLockout,
+		},
+		cache.LoginLockPolicy{
+			Threshold:   cfg.LoginProtection.IPThreshold,
+			Window:      cfg.LoginProtection.Window,
+			BaseLockout: cfg.LoginProtection.BaseLockout,
+			MaxLockout:  cfg.LoginProtection.MaxLockout,
+		},
+	)
+	mfaService := service.NewMFAService(mfaRepo, userRepo, redisClient, mfaBox,
+		cfg.MFA.Issuer, cfg.MFA.RequiredRoles, cfg.MFA.ChallengeTTL)
+	authService := service.NewAuthService(userRepo, loginAuditRepo, jwtMgr, tokenService, personalTokenService,
+		accountService, loginGuard, mfaService, cfg.Account.RequireEmailVerification)
+	oauthService := service.NewOAuthService(oauthProviders, identityRepo, userRepo, authService, tokenService,
+		personalTokenService, redisClient, cfg.OAuth.FrontendURL, cfg.OAuth.StateTTL)
+	userService := service.NewUserService(userRepo, tokenService)
+	knowledgeService := service.NewCachedKnowledgeService(
+		service.NewKnowledgeService(knowledgeRepo, categoryRepo, relationRepo, progressRepo), readThrough, cfg.Cache.TTL)
+	categoryService := service.NewCategoryService(categoryRepo)
 	progressService := service.NewProgressService(progressRepo)
-	exerciseService := service.NewExerciseService(exerciseRepo, recordRepo)
+	exerciseService := service.NewExerciseService(exerciseRepo, recordRepo, progressService, codeRunner, cfg.Exercise.RevealAfterAttempts)
+	contentService := service.NewContentService(categoryRepo, knowledgeRepo, relationRepo, exerciseRepo, readThrough)
+	transferService := service.NewContentTransferService(contentRepo, readThrough)
+	searchService := service.NewSearchService(searchRepo)
+	examService := service.NewExamService(examRepo, knowledgeRepo, progressService)
+	learningPathService := service.NewLearningPathService(knowledgeRepo, relationRepo, progressRepo)
+	dashboardService := service.NewDashboardService(dashboardRepo, progressRepo, learningPathService)
+	studyService := service.NewStudyService(studyRepo, knowledgeRepo, cfg.Study.IdleTimeout)
 
 	// 初始化 Handler
 	authHandler := handler.NewAuthHandler(authService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	personalTokenHandler := handler.NewPersonalTokenHandler(personalTokenService)
 	userHandler := handler.NewUserHandler(userService)
-	categoryHandler := handler.NewCategoryHandler(categoryRepo)
+	categoryHandler := handler.NewCategoryHandler(cachedCategoryRepo, categoryService)
 	knowledgeHandler := handler.NewKnowledgeHandler(knowledgeService)
 	progressHandler := handler.NewProgressHandler(progressService)
 	exerciseHandler := handler.NewExerciseHandler(exerciseService)
-	healthHandler := handler.NewHealthHandler()
+	adminHandler := handler.NewAdminHandler(contentService, transferService, readThrough)
+	searchHandler := handler.NewSearchHandler(searchService)
+	examHandler := handler.NewExamHandler(examService)
+	learningPathHandler := handler.NewLearningPathHandler(learningPathService)
+	dashboardHandler := handler.NewDashboardHandler(dashboardService)
+	studyHandler := handler.NewStudyHandler(studyService)
+	healthHandler := handler.NewHealthHandler(db, redisClient)
 
 	// 设置 Gin 模式
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// 认证中间件
+	authMiddleware := middleware.AuthMiddleware(jwtMgr, tokenService, personalTokenService)
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(jwtMgr, tokenService, personalTokenService)
+	// 账号安全相关接口只允许登录会话访问，不接受个人访问令牌
+	sessionOnly := middleware.RequireSession()
+
+	// 限流中间件（Redis 滑动窗口，多实例共享计数）
+	var limiter middleware.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter = cache.NewSlidingWindowLimiter(redisClient)
+	}
+	groupLimit := func(group string) gin.HandlerFunc {
+		return middleware.RateLimitMiddleware(limiter, "group:"+group, cfg.RateLimit.GroupPolicy(group), middleware.UserKey)
+	}
+
 	// 创建路由
 	r := gin.New()
+	// 只信任配置的反向代理转发的客户端地址，否则 X-Forwarded-For 可被客户端伪造以绕过按 IP 的限流
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
 
 	// 全局中间件
 	r.Use(middleware.LoggerMiddleware())
 	r.Use(middleware.RecoveryMiddleware())
 	r.Use(middleware.CORSMiddleware([]string{"*"}))
-	r.Use(middleware.RateLimitMiddleware(middleware.NewRateLimiter(time.Minute, 100)))
 
-	// 健康检查
+	// 健康检查（注册在限流之前，探针请求不计入限流）
 	r.GET("/health", healthHandler.Health)
+	r.GET("/health/live", healthHandler.Live)
+	r.GET("/health/ready", healthHandler.Ready)
+
+	r.Use(middleware.RateLimitMiddleware(limiter, "global", cfg.RateLimit.Default, middleware.ClientIPKey))
 
 	// API v1
 	v1 := r.Group("/api/v1")
@@ -99,53 +216,154 @@ func main() {
 		// 认证路由（无需认证）
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.GET("/me", middleware.AuthMiddleware(jwtMgr), authHandler.GetMe)
+			auth.POST("/register", middleware.RateLimitMiddleware(limiter, "register", cfg.RateLimit.Register, middleware.ClientIPKey), authHandler.Register)
+			loginLimit := middleware.RateLimitMiddleware(limiter, "login", cfg.RateLimit.Login, middleware.ClientIPKey)
+			auth.POST("/login", loginLimit, authHandler.Login)
+			auth.POST("/login/mfa", loginLimit, authHandler.LoginMFA)
+			auth.POST("/login/mfa/enroll", loginLimit, authHandler.BeginLoginEnrollment)
+			auth.POST("/login/mfa/enable", loginLimit, authHandler.EnableLoginMFA)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authMiddleware, sessionOnly, authHandler.Logout)
+			auth.GET("/me", authMiddleware, authHandler.GetMe)
+
+			mailLimit := middleware.RateLimitMiddleware(limiter, "mail", cfg.RateLimit.Mail, middleware.ClientIPKey)
+			auth.POST("/email/verify", accountHandler.VerifyEmail)
+			auth.POST("/email/resend", mailLimit, accountHandler.ResendVerification)
+			auth.POST("/password/forgot", mailLimit, accountHandler.ForgotPassword)
+			auth.POST("/password/reset", mailLimit, accountHandler.ResetPassword)
+
+			auth.GET("/oauth/providers", oauthHandler.Providers)
+			auth.GET("/oauth/:provider", loginLimit, oauthHandler.Begin)
+			auth.GET("/oauth/:provider/callback", oauthHandler.Callback)
+			auth.POST("/oauth/exchange", loginLimit, oauthHandler.Exchange)
 		}
 
 		// 用户路由（需要认证）
 		users := v1.Group("/users")
-		users.Use(middleware.AuthMiddleware(jwtMgr))
+		users.Use(authMiddleware, groupLimit("users"))
 		{
+			users.PUT("/me/password", sessionOnly, authHandler.ChangePassword)
+			users.GET("/me/logins", sessionOnly, authHandler.ListLogins)
+			users.GET("/me/identities", sessionOnly, oauthHandler.ListIdentities)
+			users.POST("/me/identities/:provider", sessionOnly, oauthHandler.Link)
+			users.DELETE("/me/identities/:provider", sessionOnly, oauthHandler.Unlink)
+			users.GET("/me/mfa", sessionOnly, mfaHandler.Status)
+			users.POST("/me/mfa/enroll", sessionOnly, mfaHandler.Enroll)
+			users.POST("/me/mfa/enable", sessionOnly, mfaHandler.Enable)
+			users.POST("/me/mfa/disable", sessionOnly, mfaHandler.Disable)
+			users.POST("/me/mfa/recovery-codes", sessionOnly, mfaHandler.RegenerateRecoveryCodes)
+			users.GET("/me/tokens", sessionOnly, personalTokenHandler.List)
+			users.POST("/me/tokens", sessionOnly, personalTokenHandler.Create)
+			users.DELETE("/me/tokens/:id", sessionOnly, personalTokenHandler.Revoke)
 			users.GET("/:id", userHandler.GetByID)
 			users.PUT("/:id", userHandler.Update)
 		}
 
 		// 分类路由（可选认证）
 		categories := v1.Group("/categories")
-		categories.Use(middleware.OptionalAuthMiddleware(jwtMgr))
+		categories.Use(optionalAuthMiddleware, groupLimit("categories"))
 		{
 			categories.GET("", categoryHandler.List)
+			categories.GET("/tree", categoryHandler.GetTree)
 			categories.GET("/:id", categoryHandler.GetByID)
 		}
 
 		// 知识点路由（需要认证）
 		knowledge := v1.Group("/knowledge")
-		knowledge.Use(middleware.AuthMiddleware(jwtMgr))
+		knowledge.Use(authMiddleware, groupLimit("knowledge"))
 		{
 			knowledge.GET("", knowledgeHandler.List)
 			knowledge.GET("/:id", knowledgeHandler.GetByID)
 			knowledge.GET("/graph", knowledgeHandler.GetGraph)
+			knowledge.GET("/path", knowledgeHandler.ShortestPath)
+			knowledge.GET("/:id/neighbors", knowledgeHandler.GetNeighbors)
 		}
 
+		// 全文检索（需要认证）
+		v1.GET("/search", authMiddleware, groupLimit("search"), searchHandler.Search)
+
 		// 学习进度路由（需要认证）
 		learning := v1.Group("/learning")
-		learning.Use(middleware.AuthMiddleware(jwtMgr))
+		learning.Use(authMiddleware, groupLimit("learning"))
 		{
 			learning.GET("/progress", progressHandler.GetProgress)
 			learning.POST("/progress", progressHandler.UpdateProgress)
 			learning.GET("/stats", progressHandler.GetStats)
+			learning.GET("/dashboard", dashboardHandler.Get)
+			learning.POST("/sessions", studyHandler.Start)
+			learning.POST("/sessions/:id/heartbeat", studyHandler.Heartbeat)
+			learning.POST("/sessions/:id/end", studyHandler.End)
+			learning.GET("/reviews/due", progressHandler.GetDueReviews)
+			learning.GET("/path", learningPathHandler.GetPath)
+			learning.GET("/recommendations", learningPathHandler.GetNextTopics)
 		}
 
 		// 练习题路由（需要认证）
 		exercises := v1.Group("/exercises")
-		exercises.Use(middleware.AuthMiddleware(jwtMgr))
+		exercises.Use(authMiddleware, groupLimit("exercises"))
 		{
 			exercises.GET("", exerciseHandler.List)
 			exercises.GET("/:id", exerciseHandler.GetByID)
 			exercises.POST("/:id/submit", exerciseHandler.SubmitAnswer)
 			exercises.GET("/wrong", exerciseHandler.GetWrongList)
+			exercises.PUT("/wrong/:id", exerciseHandler.MarkWrong)
+			exercises.POST("/wrong/practice", exerciseHandler.PracticeWrong)
+		}
+
+		// 模拟面试路由（需要认证）
+		exams := v1.Group("/exams")
+		exams.Use(authMiddleware, groupLimit("exams"))
+		{
+			exams.POST("", examHandler.Start)
+			exams.GET("", examHandler.List)
+			exams.GET("/:id", examHandler.Get)
+			exams.PUT("/:id/answers/:exercise_id", examHandler.SaveAnswer)
+			exams.POST("/:id/submit", examHandler.Submit)
+			exams.GET("/:id/report", examHandler.Report)
+		}
+
+		// 后台路由（内容管理需要编辑或管理员权限）
+		admin := v1.Group("/admin")
+		admin.Use(authMiddleware, groupLimit("admin"), middleware.RequireRole(models.RoleEditor, models.RoleAdmin),
+			middleware.RequireScope(models.TokenScopeAdmin))
+		{
+			admin.POST("/categories", adminHandler.CreateCategory)
+			admin.PUT("/categories/order", adminHandler.ReorderCategories)
+			admin.PUT("/categories/:id", adminHandler.UpdateCategory)
+			admin.DELETE("/categories/:id", adminHandler.DeleteCategory)
+			admin.POST("/categories/:id/restore", adminHandler.RestoreCategory)
+
+			admin.GET("/knowledge/:id", adminHandler.GetKnowledge)
+			admin.POST("/knowledge", adminHandler.CreateKnowledge)
+			admin.PUT("/knowledge/:id", adminHandler.UpdateKnowledge)
+			admin.DELETE("/knowledge/:id", adminHandler.DeleteKnowledge)
+			admin.POST("/knowledge/:id/restore", adminHandler.RestoreKnowledge)
+
+			admin.GET("/relations", adminHandler.ListRelations)
+			admin.POST("/relations", adminHandler.CreateRelation)
+			admin.PUT("/relations/:id", adminHandler.UpdateRelation)
+			admin.DELETE("/relations/:id", adminHandler.DeleteRelation)
+			admin.POST("/relations/:id/restore", adminHandler.RestoreRelation)
+
+			admin.GET("/exercises", adminHandler.ListExercises)
+			admin.GET("/exercises/:id", adminHandler.GetExercise)
+			admin.POST("/exercises", adminHandler.CreateExercise)
+			admin.PUT("/exercises/:id", adminHandler.UpdateExercise)
+			admin.DELETE("/exercises/:id", adminHandler.DeleteExercise)
+			admin.POST("/exercises/:id/restore", adminHandler.RestoreExercise)
+
+			admin.GET("/content/export", adminHandler.ExportContent)
+			admin.POST("/content/import", adminHandler.ImportContent)
+
+			// 用户管理（仅管理员）
+			adminUsers := admin.Group("/users")
+			adminUsers.Use(middleware.RequireRole(models.RoleAdmin))
+			{
+				adminUsers.GET("", userHandler.List)
+				adminUsers.PUT("/:id/role", userHandler.UpdateRole)
+			}
+
+			admin.GET("/cache/stats", middleware.RequireRole(models.RoleAdmin), adminHandler.CacheStats)
 		}
 	}
 
@@ -165,12 +383,17 @@ func main() {
 		}
 	}()
 
+	// 后台结束空闲超时的学习会话
+	expirerCtx, stopExpirer := context.WithCancel(context.Background())
+	go studyService.RunExpirer(expirerCtx, cfg.Study.SweepInterval)
+
 	// 优雅关闭
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopExpirer()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
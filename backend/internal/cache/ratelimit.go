@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript 滑动窗口限流脚本（有序集合记录窗口内每次请求的时间戳）。
+// 使用 Redis 服务器时间，避免多实例之间的时钟偏差。
+// 返回 {是否允许, 剩余次数, 窗口重置剩余毫秒}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', key, now, member .. ':' .. now)
+  redis.call('PEXPIRE', key, window)
+  count = count + 1
+  allowed = 1
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+  reset = window - (now - tonumber(oldest[2]))
+end
+
+return {allowed, limit - count, reset}
+`)
+
+// rateLimitPrefix 限流键前缀
+const rateLimitPrefix = "ratelimit:"
+
+// RateLimitResult 限流检查结果
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration // 窗口内最早的请求过期（释放一个名额）的剩余时间
+}
+
+// SlidingWindowLimiter 基于 Redis 的滑动窗口限流器，多实例共享计数
+type SlidingWindowLimiter struct {
+	cache *Cache
+}
+
+// NewSlidingWindowLimiter 创建滑动窗口限流器
+func NewSlidingWindowLimiter(c *Cache) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{cache: c}
+}
+
+// Allow 记录一次请求并判断是否超出窗口内的请求上限
+func (l *SlidingWindowLimiter) Allow(key string, limit int, window time.Duration) (RateLimitResult, error) {
+	res, err := slidingWindowScript.Run(l.cache.ctx, l.cache.client,
+		[]string{rateLimitPrefix + key},
+		window.Milliseconds(), limit, uuid.New().String(),
+	).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	remaining := int(res[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+	return RateLimitResult{
+		Allowed:   res[0] == 1,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
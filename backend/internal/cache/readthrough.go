@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// KeyVersion 缓存键版本，缓存数据结构变化时递增，旧版本的键自然过期
+const KeyVersion = "v1"
+
+// keyPrefix 缓存键前缀
+const keyPrefix = "eightgu:" + KeyVersion + ":"
+
+// Key 生成带版本前缀的缓存键
+func Key(parts ...interface{}) string {
+	segments := make([]string, 0, len(parts))
+	for _, p := range parts {
+		segments = append(segments, fmt.Sprint(p))
+	}
+	return keyPrefix + strings.Join(segments, ":")
+}
+
+// Stats 缓存命中统计
+type Stats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	Errors  int64   `json:"errors"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// ReadThrough 读穿透缓存：未命中时加载数据并回填，同一键的并发加载合并为一次
+type ReadThrough struct {
+	cache  *Cache
+	group  singleflight.Group
+	hits   atomic.Int64
+	misses atomic.Int64
+	errors atomic.Int64
+}
+
+// NewReadThrough 创建读穿透缓存，cache 为 nil 时直接回源
+func NewReadThrough(c *Cache) *ReadThrough {
+	return &ReadThrough{cache: c}
+}
+
+// Fetch 读取缓存到 dest，未命中时调用 load 加载并写入缓存；
+// Redis 异常时降级为直接回源，不影响业务
+func (r *ReadThrough) Fetch(key string, ttl time.Duration, dest interface{}, load func() (interface{}, error)) error {
+	if r.cache != nil {
+		raw, err := r.cache.Get(key)
+		if err == nil && json.Unmarshal([]byte(raw), dest) == nil {
+			r.hits.Add(1)
+			return nil
+		}
+		if err != nil && !IsNil(err) {
+			r.errors.Add(1)
+		}
+	}
+	r.misses.Add(1)
+
+	data, err, _ := r.group.Do(key, func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.cache != nil {
+			if err := r.cache.Set(key, payload, ttl); err != nil {
+				r.errors.Add(1)
+			}
+		}
+		return payload, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+// Invalidate 删除匹配模式的缓存键（模式不含版本前缀）
+func (r *ReadThrough) Invalidate(patterns ...string) error {
+	if r == nil || r.cache == nil {
+		return nil
+	}
+	for _, pattern := range patterns {
+		if err := r.cache.DeletePattern(keyPrefix + pattern); err != nil {
+			r.errors.Add(1)
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats 获取命中统计
+func (r *ReadThrough) Stats() Stats {
+	stats := Stats{
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+		Errors: r.errors.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
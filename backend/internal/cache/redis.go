@@ -49,11 +49,30 @@ func (c *Cache) Get(key string) (string, error) {
 	return c.client.Get(c.ctx, key).Result()
 }
 
+// GetDel 获取并删除缓存（原子操作）
+func (c *Cache) GetDel(key string) (string, error) {
+	return c.client.GetDel(c.ctx, key).Result()
+}
+
 // Delete 删除缓存
 func (c *Cache) Delete(key string) error {
 	return c.client.Del(c.ctx, key).Err()
 }
 
+// SetAdd 向集合添加成员并刷新过期时间
+func (c *Cache) SetAdd(key string, member interface{}, ttl time.Duration) error {
+	pipe := c.client.TxPipeline()
+	pipe.SAdd(c.ctx, key, member)
+	pipe.Expire(c.ctx, key, ttl)
+	_, err := pipe.Exec(c.ctx)
+	return err
+}
+
+// SetMembers 获取集合所有成员
+func (c *Cache) SetMembers(key string) ([]string, error) {
+	return c.client.SMembers(c.ctx, key).Result()
+}
+
 // Exists 检查键是否存在
 func (c *Cache) Exists(key string) (bool, error) {
 	n, err := c.client.Exists(c.ctx, key).Result()
@@ -84,6 +103,21 @@ func (c *Cache) DeletePattern(pattern string) error {
 	return iter.Err()
 }
 
+// IsNil 判断是否为键不存在错误
+func IsNil(err error) bool {
+	return err == redis.Nil
+}
+
+// Ping 检查 Redis 连接
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// PoolStats 获取连接池统计
+func (c *Cache) PoolStats() *redis.PoolStats {
+	return c.client.PoolStats()
+}
+
 // Close 关闭连接
 func (c *Cache) Close() error {
 	return c.client.Close()
@@ -9,10 +9,20 @@ import (
 
 // Config 应用配置
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Database        DatabaseConfig        `mapstructure:"database"`
+	Redis           RedisConfig           `mapstructure:"redis"`
+	JWT             JWTConfig             `mapstructure:"jwt"`
+	Cache           CacheConfig           `mapstructure:"cache"`
+	RateLimit       RateLimitConfig       `mapstructure:"rate_limit"`
+	Sandbox         SandboxConfig         `mapstructure:"sandbox"`
+	Exercise        ExerciseConfig        `mapstructure:"exercise"`
+	Study           StudyConfig           `mapstructure:"study"`
+	Mail            MailConfig            `mapstructure:"mail"`
+	Account         AccountConfig         `mapstructure:"account"`
+	LoginProtection LoginProtectionConfig `mapstructure:"login_protection"`
+	OAuth           OAuthConfig           `mapstructure:"oauth"`
+	MFA             MFAConfig             `mapstructure:"mfa"`
 }
 
 // ServerConfig 服务器配置
@@ -22,6 +32,9 @@ type ServerConfig struct {
 	Mode         string        `mapstructure:"mode"` // debug, release
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	// TrustedProxies 反向代理的地址或网段，只有来自这些地址的请求才读取 X-Forwarded-For / X-Real-IP；
+	// 为空时直接使用连接的对端地址
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig 数据库配置
@@ -45,11 +58,133 @@ type RedisConfig struct {
 	PoolSize int    `mapstructure:"pool_size"`
 }
 
+// CacheConfig 业务缓存配置
+type CacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// RateLimitPolicy 限流策略：窗口内最多允许 Limit 次请求
+type RateLimitPolicy struct {
+	Limit  int           `mapstructure:"limit"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Enabled  bool                       `mapstructure:"enabled"`
+	Default  RateLimitPolicy            `mapstructure:"default"`  // 全局，按 IP
+	User     RateLimitPolicy            `mapstructure:"user"`     // 已认证接口的默认策略，按用户
+	Login    RateLimitPolicy            `mapstructure:"login"`    // 登录，按 IP
+	Register RateLimitPolicy            `mapstructure:"register"` // 注册，按 IP
+	Mail     RateLimitPolicy            `mapstructure:"mail"`     // 找回密码、重发验证邮件，按 IP
+	Groups   map[string]RateLimitPolicy `mapstructure:"groups"`   // 按路由分组覆盖 User 策略
+}
+
+// GroupPolicy 获取路由分组的限流策略，未配置时使用用户默认策略
+func (c *RateLimitConfig) GroupPolicy(group string) RateLimitPolicy {
+	if policy, ok := c.Groups[group]; ok {
+		return policy
+	}
+	return c.User
+}
+
+// SandboxConfig 代码题沙箱配置
+type SandboxConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	GoBinary        string        `mapstructure:"go_binary"`         // go 命令，需要本地安装 Go 工具链
+	CacheDir        string        `mapstructure:"cache_dir"`         // 编译缓存目录，默认为用户缓存目录下的 eightgu-sandbox
+	CompileTimeout  time.Duration `mapstructure:"compile_timeout"`   // 编译超时
+	CompileMemoryMB int           `mapstructure:"compile_memory_mb"` // 编译内存上限
+	RunTimeout      time.Duration `mapstructure:"run_timeout"`       // 测试运行墙钟时间上限
+	CPUTime         time.Duration `mapstructure:"cpu_time"`          // 测试运行 CPU 时间上限
+	MemoryMB        int           `mapstructure:"memory_mb"`         // 测试运行内存上限
+	MaxSourceBytes  int           `mapstructure:"max_source_bytes"`  // 提交代码大小上限
+	MaxOutputBytes  int           `mapstructure:"max_output_bytes"`  // 保留的输出大小上限
+	MaxConcurrent   int           `mapstructure:"max_concurrent"`    // 同时运行的提交数
+}
+
+// ExerciseConfig 练习题配置
+type ExerciseConfig struct {
+	RevealAfterAttempts int `mapstructure:"reveal_after_attempts"` // 作答达到该次数后在详情中公开答案，0 表示只在提交后返回
+}
+
+// StudyConfig 学习时长记录配置
+type StudyConfig struct {
+	IdleTimeout   time.Duration `mapstructure:"idle_timeout"`   // 超过该时间没有心跳的会话自动结束
+	SweepInterval time.Duration `mapstructure:"sweep_interval"` // 后台关闭空闲会话的间隔
+}
+
+// MailConfig 邮件配置
+type MailConfig struct {
+	Driver  string     `mapstructure:"driver"`   // smtp、file 或 log
+	From    string     `mapstructure:"from"`     // 发件人，如 "八股学习平台 <no-reply@example.com>"
+	BaseURL string     `mapstructure:"base_url"` // 邮件中链接指向的前端地址
+	Dir     string     `mapstructure:"dir"`      // file 驱动保存邮件的目录
+	SMTP    SMTPConfig `mapstructure:"smtp"`
+}
+
+// SMTPConfig SMTP 服务器配置
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// AccountConfig 账号验证与找回密码配置
+type AccountConfig struct {
+	RequireEmailVerification bool          `mapstructure:"require_email_verification"` // 邮箱验证后才能登录
+	VerifyTokenTTL           time.Duration `mapstructure:"verify_token_ttl"`           // 邮箱验证链接有效期
+	ResetTokenTTL            time.Duration `mapstructure:"reset_token_ttl"`            // 重置密码链接有效期
+}
+
+// LoginProtectionConfig 登录失败锁定配置：按账号与按 IP 分别计数，锁定时长指数增长
+type LoginProtectionConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	Window           time.Duration `mapstructure:"window"`            // 失败计数窗口
+	AccountThreshold int           `mapstructure:"account_threshold"` // 同一账号失败次数达到后锁定该账号
+	IPThreshold      int           `mapstructure:"ip_threshold"`      // 同一 IP 失败次数达到后锁定该 IP
+	BaseLockout      time.Duration `mapstructure:"base_lockout"`      // 首次锁定时长，之后每次失败翻倍
+	MaxLockout       time.Duration `mapstructure:"max_lockout"`       // 最长锁定时长
+}
+
+// OAuthConfig 第三方登录配置
+type OAuthConfig struct {
+	CallbackBaseURL string                         `mapstructure:"callback_base_url"` // 后端对外地址，回调地址为 {callback_base_url}/api/v1/auth/oauth/{provider}/callback
+	FrontendURL     string                         `mapstructure:"frontend_url"`      // 登录完成后跳转的前端地址
+	StateTTL        time.Duration                  `mapstructure:"state_ttl"`         // 授权请求有效期
+	Providers       map[string]OAuthProviderConfig `mapstructure:"providers"`         // 键为提供方名称，出现在登录地址中
+}
+
+// OAuthProviderConfig 第三方登录提供方配置
+type OAuthProviderConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Type         string   `mapstructure:"type"`         // github 或 oidc
+	DisplayName  string   `mapstructure:"display_name"` // 登录按钮上显示的名称
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Issuer       string   `mapstructure:"issuer"`    // oidc：签发者地址，端点通过 /.well-known/openid-configuration 发现
+	Scopes       []string `mapstructure:"scopes"`    // 为空时 github 使用 read:user user:email，oidc 使用 openid email profile
+	AuthURL      string   `mapstructure:"auth_url"`  // github：覆盖默认端点，用于 GitHub Enterprise
+	TokenURL     string   `mapstructure:"token_url"` // github：同上
+	APIURL       string   `mapstructure:"api_url"`   // github：同上
+}
+
+// MFAConfig 两步验证配置
+type MFAConfig struct {
+	Issuer        string        `mapstructure:"issuer"`         // 验证器应用中显示的名称
+	EncryptionKey string        `mapstructure:"encryption_key"` // 加密保存 TOTP 密钥，修改后已绑定的用户需要重新绑定
+	RequiredRoles []string      `mapstructure:"required_roles"` // 必须启用两步验证的角色
+	ChallengeTTL  time.Duration `mapstructure:"challenge_ttl"`  // 登录第二步的有效期
+}
+
 // JWTConfig JWT 配置
 type JWTConfig struct {
-	Secret     string        `mapstructure:"secret"`
-	ExpireTime time.Duration `mapstructure:"expire_time"`
-	Issuer     string        `mapstructure:"issuer"`
+	Secret            string        `mapstructure:"secret"`
+	ExpireTime        time.Duration `mapstructure:"expire_time"`
+	RefreshExpireTime time.Duration `mapstructure:"refresh_expire_time"`
+	Issuer            string        `mapstructure:"issuer"`
 }
 
 // LoadConfig 加载配置
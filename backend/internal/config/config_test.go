@@ -21,6 +21,27 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("Expected dbname eightgu, got %s", cfg.Database.DBName)
 	}
 
+	// 验证学习会话配置
+	if cfg.Study.IdleTimeout != 5*time.Minute {
+		t.Errorf("Expected idle timeout 5m, got %v", cfg.Study.IdleTimeout)
+	}
+
+	// 验证第三方登录配置
+	if cfg.OAuth.StateTTL != 10*time.Minute {
+		t.Errorf("Expected oauth state ttl 10m, got %v", cfg.OAuth.StateTTL)
+	}
+	if p, ok := cfg.OAuth.Providers["sso"]; !ok || p.Type != "oidc" {
+		t.Errorf("Expected sso oidc provider, got %+v", cfg.OAuth.Providers)
+	}
+
+	// 验证两步验证配置
+	if cfg.MFA.ChallengeTTL != 5*time.Minute {
+		t.Errorf("Expected mfa challenge ttl 5m, got %v", cfg.MFA.ChallengeTTL)
+	}
+	if len(cfg.MFA.RequiredRoles) != 2 || cfg.MFA.RequiredRoles[0] != "admin" {
+		t.Errorf("Expected mfa required roles [admin editor], got %v", cfg.MFA.RequiredRoles)
+	}
+
 	// 验证 JWT 配置
 	if cfg.JWT.ExpireTime != 168*time.Hour {
 		t.Errorf("Expected expire time 168h, got %v", cfg.JWT.ExpireTime)
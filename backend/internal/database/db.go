@@ -21,6 +21,8 @@ func NewDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 
 	db, err := gorm.Open(postgres.Open(cfg.GetDSN()), &gorm.Config{
 		Logger: logger.Default.LogMode(logLevel),
+		// 把唯一约束等数据库错误转换为 gorm.ErrDuplicatedKey 等通用错误
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler 邮箱验证与找回密码处理器
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler 创建邮箱验证与找回密码处理器
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// VerifyEmail 验证邮箱
+// @Summary 使用邮件中的令牌验证邮箱（令牌只能使用一次）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.VerifyEmailRequest true "验证令牌"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/email/verify [post]
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req service.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "邮箱已验证", user)
+}
+
+// ResendVerification 重发验证邮件
+// @Summary 重发邮箱验证邮件（邮箱不存在或已验证时同样返回成功）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.EmailRequest true "邮箱"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/email/resend [post]
+func (h *AccountHandler) ResendVerification(c *gin.Context) {
+	var req service.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.accountService.ResendVerification(&req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "如果该邮箱已注册且尚未验证，验证邮件已发送", nil)
+}
+
+// ForgotPassword 找回密码
+// @Summary 发送重置密码邮件（邮箱不存在时同样返回成功）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.EmailRequest true "邮箱"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/password/forgot [post]
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req service.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(&req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "如果该邮箱已注册，重置密码邮件已发送", nil)
+}
+
+// ResetPassword 重置密码
+// @Summary 使用邮件中的令牌设置新密码（令牌只能使用一次），并注销全部会话
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.ResetPasswordRequest true "令牌与新密码"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/password/reset [post]
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req service.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.accountService.ResetPassword(&req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "密码已重置，请重新登录", nil)
+}
@@ -0,0 +1,699 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler 内容管理处理器
+type AdminHandler struct {
+	contentService  *service.ContentService
+	transferService *service.ContentTransferService
+	readThrough     *cache.ReadThrough
+}
+
+// maxImportSize 导入内容的最大字节数
+const maxImportSize = 10 << 20
+
+// NewAdminHandler 创建内容管理处理器
+func NewAdminHandler(
+	contentService *service.ContentService,
+	transferService *service.ContentTransferService,
+	readThrough *cache.ReadThrough,
+) *AdminHandler {
+	return &AdminHandler{
+		contentService:  contentService,
+		transferService: transferService,
+		readThrough:     readThrough,
+	}
+}
+
+// bindID 绑定路径中的 ID 参数
+func bindID(c *gin.Context) (uint, bool) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return 0, false
+	}
+	return uri.ID, true
+}
+
+// ==================== 分类 ====================
+
+// CreateCategory 创建分类
+// @Summary 创建分类
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.CategoryRequest true "分类信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/categories [post]
+func (h *AdminHandler) CreateCategory(c *gin.Context) {
+	var req service.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	category, err := h.contentService.CreateCategory(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", category)
+}
+
+// UpdateCategory 更新分类
+// @Summary 更新分类
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "分类ID"
+// @Param request body service.CategoryRequest true "分类信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/categories/:id [put]
+func (h *AdminHandler) UpdateCategory(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	var req service.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	category, err := h.contentService.UpdateCategory(id, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", category)
+}
+
+// ReorderCategories 批量移动/排序分类
+// @Summary 批量修改分类的父分类与排序（拒绝产生环的移动）
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.CategoryOrderRequest true "分类顺序"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/categories/order [put]
+func (h *AdminHandler) ReorderCategories(c *gin.Context) {
+	var req service.CategoryOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.contentService.ReorderCategories(&req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", nil)
+}
+
+// DeleteCategory 删除分类
+// @Summary 删除分类
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "分类ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/categories/:id [delete]
+func (h *AdminHandler) DeleteCategory(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.contentService.DeleteCategory(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// RestoreCategory 恢复分类
+// @Summary 恢复已删除的分类
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "分类ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/categories/:id/restore [post]
+func (h *AdminHandler) RestoreCategory(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.contentService.RestoreCategory(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "恢复成功", category)
+}
+
+// ==================== 知识点 ====================
+
+// GetKnowledge 获取知识点详情
+// @Summary 获取知识点详情
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "知识点ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/knowledge/:id [get]
+func (h *AdminHandler) GetKnowledge(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	knowledge, err := h.contentService.GetKnowledge(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, knowledge)
+}
+
+// CreateKnowledge 创建知识点
+// @Summary 创建知识点
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.KnowledgeRequest true "知识点信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/knowledge [post]
+func (h *AdminHandler) CreateKnowledge(c *gin.Context) {
+	var req service.KnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	knowledge, err := h.contentService.CreateKnowledge(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", knowledge)
+}
+
+// UpdateKnowledge 更新知识点
+// @Summary 更新知识点
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "知识点ID"
+// @Param request body service.KnowledgeRequest true "知识点信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/knowledge/:id [put]
+func (h *AdminHandler) UpdateKnowledge(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	var req service.KnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	knowledge, err := h.contentService.UpdateKnowledge(id, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", knowledge)
+}
+
+// DeleteKnowledge 删除知识点
+// @Summary 删除知识点
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "知识点ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/knowledge/:id [delete]
+func (h *AdminHandler) DeleteKnowledge(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.contentService.DeleteKnowledge(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// RestoreKnowledge 恢复知识点
+// @Summary 恢复已删除的知识点
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "知识点ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/knowledge/:id/restore [post]
+func (h *AdminHandler) RestoreKnowledge(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	knowledge, err := h.contentService.RestoreKnowledge(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "恢复成功", knowledge)
+}
+
+// ==================== 知识关联 ====================
+
+// ListRelations 获取知识关联列表
+// @Summary 获取知识关联列表
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param from_point_id query int false "起点知识点ID"
+// @Param to_point_id query int false "终点知识点ID"
+// @Param relation_type query string false "关联类型"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/relations [get]
+func (h *AdminHandler) ListRelations(c *gin.Context) {
+	var req service.RelationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	relations, err := h.contentService.ListRelations(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, relations)
+}
+
+// CreateRelation 创建知识关联
+// @Summary 创建知识关联
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.RelationRequest true "关联信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/relations [post]
+func (h *AdminHandler) CreateRelation(c *gin.Context) {
+	var req service.RelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	relation, err := h.contentService.CreateRelation(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", relation)
+}
+
+// UpdateRelation 更新知识关联
+// @Summary 更新知识关联
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "关联ID"
+// @Param request body service.RelationRequest true "关联信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/relations/:id [put]
+func (h *AdminHandler) UpdateRelation(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	var req service.RelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	relation, err := h.contentService.UpdateRelation(id, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", relation)
+}
+
+// DeleteRelation 删除知识关联
+// @Summary 删除知识关联
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "关联ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/relations/:id [delete]
+func (h *AdminHandler) DeleteRelation(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.contentService.DeleteRelation(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// RestoreRelation 恢复知识关联
+// @Summary 恢复已删除的知识关联
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "关联ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/relations/:id/restore [post]
+func (h *AdminHandler) RestoreRelation(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	relation, err := h.contentService.RestoreRelation(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "恢复成功", relation)
+}
+
+// ==================== 练习题 ====================
+
+// ListExercises 获取练习题列表（包含答案）
+// @Summary 获取练习题列表（包含答案）
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param knowledge_id query int false "知识点ID"
+// @Param difficulty query string false "难度"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/exercises [get]
+func (h *AdminHandler) ListExercises(c *gin.Context) {
+	var req service.ExerciseListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	items, total, err := h.contentService.ListExercises(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, int(total), req.Page, req.PageSize, items)
+}
+
+// GetExercise 获取练习题详情（包含答案）
+// @Summary 获取练习题详情（包含答案）
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "练习题ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/exercises/:id [get]
+func (h *AdminHandler) GetExercise(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	exercise, err := h.contentService.GetExercise(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, exercise)
+}
+
+// CreateExercise 创建练习题
+// @Summary 创建练习题
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.ExerciseRequest true "练习题信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/exercises [post]
+func (h *AdminHandler) CreateExercise(c *gin.Context) {
+	var req service.ExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	exercise, err := h.contentService.CreateExercise(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", exercise)
+}
+
+// UpdateExercise 更新练习题
+// @Summary 更新练习题
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "练习题ID"
+// @Param request body service.ExerciseRequest true "练习题信息"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/exercises/:id [put]
+func (h *AdminHandler) UpdateExercise(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	exercise, err := h.contentService.UpdateExercise(id, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", exercise)
+}
+
+// DeleteExercise 删除练习题
+// @Summary 删除练习题
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "练习题ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/exercises/:id [delete]
+func (h *AdminHandler) DeleteExercise(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.contentService.DeleteExercise(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// RestoreExercise 恢复练习题
+// @Summary 恢复已删除的练习题
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "练习题ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/exercises/:id/restore [post]
+func (h *AdminHandler) RestoreExercise(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	exercise, err := h.contentService.RestoreExercise(id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "恢复成功", exercise)
+}
+
+// ==================== 导入导出 ====================
+
+// ExportContent 导出全部内容
+// @Summary 导出内容（分类、知识点、知识关联、练习题）
+// @Tags Admin
+// @Produce json
+// @Produce x-yaml
+// @Security Bearer
+// @Param format query string false "导出格式 yaml/json，默认 yaml"
+// @Success 200 {file} file
+// @Router /api/v1/admin/content/export [get]
+func (h *AdminHandler) ExportContent(c *gin.Context) {
+	format := c.DefaultQuery("format", service.FormatYAML)
+	if format != service.FormatYAML && format != service.FormatJSON {
+		utils.ParamError(c, "format 必须是 yaml 或 json")
+		return
+	}
+
+	bundle, err := h.transferService.Export()
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+	data, err := service.EncodeContentBundle(bundle, format)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	contentType := "application/x-yaml; charset=utf-8"
+	if format == service.FormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Header("Content-Disposition", "attachment; filename=content."+format)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportContent 导入内容
+// @Summary 导入内容（按 slug 幂等导入，支持试运行）
+// @Tags Admin
+// @Accept json
+// @Accept x-yaml
+// @Produce json
+// @Security Bearer
+// @Param format query string false "内容格式 yaml/json，默认根据 Content-Type 判断"
+// @Param dry_run query bool false "试运行，只返回变更不写入"
+// @Success 200 {object} utils.Response{data=service.ImportResult}
+// @Router /api/v1/admin/content/import [post]
+func (h *AdminHandler) ImportContent(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = service.FormatYAML
+		if strings.Contains(c.ContentType(), "json") {
+			format = service.FormatJSON
+		}
+	}
+	if format != service.FormatYAML && format != service.FormatJSON {
+		utils.ParamError(c, "format 必须是 yaml 或 json")
+		return
+	}
+	dryRun := c.Query("dry_run") == "true" || c.Query("dry_run") == "1"
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	if err != nil {
+		utils.ParamError(c, "读取导入内容失败: "+err.Error())
+		return
+	}
+
+	result, err := h.importBundle(data, format, dryRun)
+	var validationErr *service.ContentValidationError
+	if errors.As(err, &validationErr) {
+		utils.ErrorWithData(c, utils.CodeErrorParam, "导入内容校验失败", validationErr.Problems)
+		return
+	}
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	message := "导入成功"
+	if dryRun {
+		message = "试运行完成，未写入任何数据"
+	}
+	utils.SuccessWithMessage(c, message, result)
+}
+
+func (h *AdminHandler) importBundle(data []byte, format string, dryRun bool) (*service.ImportResult, error) {
+	bundle, err := service.DecodeContentBundle(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return h.transferService.Import(bundle, dryRun)
+}
+
+// ==================== 缓存 ====================
+
+// CacheStats 获取缓存命中统计
+// @Summary 获取缓存命中统计
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/cache/stats [get]
+func (h *AdminHandler) CacheStats(c *gin.Context) {
+	utils.Success(c, h.readThrough.Stats())
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"math"
+	"strconv"
+
 	"eight-gu-learning-platform/internal/middleware"
 	"eight-gu-learning-platform/internal/service"
 	"eight-gu-learning-platform/internal/utils"
@@ -45,12 +49,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 }
 
 // Login 用户登录
-// @Summary 用户登录
+// @Summary 用户登录（邮箱不存在与密码错误返回相同错误，失败次数过多时按账号和 IP 锁定；需要两步验证时返回 mfa_token）
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body service.LoginRequest true "登录信息"
-// @Success 200 {object} utils.Response
+// @Success 200 {object} utils.Response{data=service.AuthResponse}
+// @Failure 401 {object} utils.Response "邮箱或密码错误"
+// @Failure 429 {object} utils.Response "已锁定，Retry-After 为剩余秒数"
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req service.LoginRequest
@@ -59,15 +65,181 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authService.Login(&req)
+	result, err := h.authService.Login(&req, loginClient(c))
 	if err != nil {
-		utils.Error(c, utils.CodeErrorUnauthorized, err.Error())
+		loginError(c, err)
 		return
 	}
 
 	utils.SuccessWithMessage(c, "登录成功", result)
 }
 
+// LoginMFA 登录第二步
+// @Summary 使用登录返回的 mfa_token 与验证器应用中的 6 位验证码（或恢复码）完成登录
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.LoginMFARequest true "挑战令牌与验证码"
+// @Success 200 {object} utils.Response{data=service.AuthResponse}
+// @Failure 401 {object} utils.Response "验证码错误或挑战已过期"
+// @Failure 429 {object} utils.Response "已锁定，Retry-After 为剩余秒数"
+// @Router /api/v1/auth/login/mfa [post]
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req service.LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.authService.LoginMFA(&req, loginClient(c))
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "登录成功", result)
+}
+
+// BeginLoginEnrollment 登录时绑定两步验证
+// @Summary 角色要求两步验证但尚未绑定时，使用 mfa_token 获取绑定密钥与二维码地址
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.MFATokenRequest true "挑战令牌"
+// @Success 200 {object} utils.Response{data=service.MFAEnrollment}
+// @Router /api/v1/auth/login/mfa/enroll [post]
+func (h *AuthHandler) BeginLoginEnrollment(c *gin.Context) {
+	var req service.MFATokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	enrollment, err := h.authService.BeginLoginEnrollment(&req)
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	utils.Success(c, enrollment)
+}
+
+// EnableLoginMFA 登录时确认绑定
+// @Summary 输入验证码确认绑定，启用两步验证并完成登录，返回恢复码（只展示一次）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.LoginMFARequest true "挑战令牌与验证码"
+// @Success 200 {object} utils.Response{data=service.AuthResponse}
+// @Router /api/v1/auth/login/mfa/enable [post]
+func (h *AuthHandler) EnableLoginMFA(c *gin.Context) {
+	var req service.LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.authService.EnableLoginMFA(&req, loginClient(c))
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "登录成功", result)
+}
+
+// loginClient 登录请求的客户端信息。ClientIP 只在对端属于 server.trusted_proxies 时才读取
+// X-Forwarded-For，按 IP 的登录锁定与登录审计因此不会被伪造的请求头绕过或污染
+func loginClient(c *gin.Context) service.LoginClient {
+	return service.LoginClient{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// loginError 登录错误响应：锁定返回 429 与 Retry-After，凭据或验证码错误返回 401
+func loginError(c *gin.Context, err error) {
+	var locked *service.LoginLockedError
+	switch {
+	case errors.As(err, &locked):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		utils.TooManyRequestsError(c, err.Error())
+	case errors.Is(err, utils.ErrInvalidCredentials),
+		errors.Is(err, utils.ErrMFACodeInvalid),
+		errors.Is(err, utils.ErrMFAChallengeInvalid):
+		utils.Error(c, utils.CodeErrorUnauthorized, err.Error())
+	default:
+		utils.HandleError(c, err)
+	}
+}
+
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.RefreshRequest true "刷新令牌"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req service.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.authService.Refresh(&req)
+	if err != nil {
+		if errors.Is(err, utils.ErrRefreshTokenInvalid) || errors.Is(err, utils.ErrRefreshTokenReused) {
+			utils.Error(c, utils.CodeErrorUnauthorized, err.Error())
+			return
+		}
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// Logout 用户登出
+// @Summary 用户登出
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.LogoutRequest false "刷新令牌"
+// @Param all query bool false "是否登出所有设备"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims := middleware.GetClaims(c)
+	if claims == nil {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ParamError(c, err.Error())
+			return
+		}
+	}
+
+	var err error
+	if c.Query("all") == "true" {
+		err = h.authService.LogoutAll(claims.UserID)
+	} else {
+		err = h.authService.Logout(claims, &req)
+	}
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.SuccessWithMessage(c, "登出成功", nil)
+}
+
 // GetMe 获取当前用户信息
 // @Summary 获取当前用户信息
 // @Tags Auth
@@ -90,3 +262,73 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 
 	utils.Success(c, user)
 }
+
+// ChangePassword 修改密码
+// @Summary 修改密码（需要原密码），注销全部会话并返回新令牌
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.ChangePasswordRequest true "原密码与新密码"
+// @Success 200 {object} utils.Response{data=service.AuthResponse}
+// @Router /api/v1/users/me/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.authService.ChangePassword(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "密码已修改", result)
+}
+
+// ListLogins 获取登录记录
+// @Summary 获取当前用户的登录记录（成功与失败），最新的在前
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=utils.PageResponse}
+// @Router /api/v1/users/me/logins [get]
+func (h *AuthHandler) ListLogins(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.LoginHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	items, total, err := h.authService.ListLogins(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, int(total), req.Page, req.PageSize, items)
+}
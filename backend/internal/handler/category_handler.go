@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"eight-gu-learning-platform/internal/middleware"
 	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/service"
 	"eight-gu-learning-platform/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -9,13 +11,15 @@ import (
 
 // CategoryHandler 分类处理器
 type CategoryHandler struct {
-	categoryRepo *repository.CategoryRepository
+	categoryRepo    repository.CategoryReader
+	categoryService *service.CategoryService
 }
 
 // NewCategoryHandler 创建分类处理器
-func NewCategoryHandler(categoryRepo *repository.CategoryRepository) *CategoryHandler {
+func NewCategoryHandler(categoryRepo repository.CategoryReader, categoryService *service.CategoryService) *CategoryHandler {
 	return &CategoryHandler{
-		categoryRepo: categoryRepo,
+		categoryRepo:    categoryRepo,
+		categoryService: categoryService,
 	}
 }
 
@@ -59,3 +63,35 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 
 	utils.Success(c, category)
 }
+
+// GetTree 获取分类树
+// @Summary 获取分类树（包含知识点数量，可选当前用户的完成度）
+// @Tags Category
+// @Produce json
+// @Param root query int false "子树根分类ID"
+// @Param progress query bool false "统计当前用户的完成度（需要登录）"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/categories/tree [get]
+func (h *CategoryHandler) GetTree(c *gin.Context) {
+	var req service.CategoryTreeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	var userID uint
+	if req.Progress {
+		if userID = middleware.GetUserID(c); userID == 0 {
+			utils.UnauthorizedError(c)
+			return
+		}
+	}
+
+	tree, err := h.categoryService.GetTree(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, tree)
+}
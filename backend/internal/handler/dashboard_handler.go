@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardHandler 学习看板处理器
+type DashboardHandler struct {
+	dashboardService *service.DashboardService
+}
+
+// NewDashboardHandler 创建学习看板处理器
+func NewDashboardHandler(dashboardService *service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+	}
+}
+
+// Get 获取学习看板
+// @Summary 获取学习看板（每日活动、连续学习、正确率、薄弱知识点与高频覆盖率）
+// @Tags Learning
+// @Produce json
+// @Security Bearer
+// @Param days query int false "活动时间序列的天数（7~365）" default(30)
+// @Success 200 {object} utils.Response
+// @Router /api/v1/learning/dashboard [get]
+func (h *DashboardHandler) Get(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.DashboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	dashboard, err := h.dashboardService.Get(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, dashboard)
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExamHandler 模拟面试考试处理器
+type ExamHandler struct {
+	examService *service.ExamService
+}
+
+// NewExamHandler 创建考试处理器
+func NewExamHandler(examService *service.ExamService) *ExamHandler {
+	return &ExamHandler{
+		examService: examService,
+	}
+}
+
+// examURI 考试路径参数
+type examURI struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// Start 开始考试
+// @Summary 开始模拟面试考试
+// @Tags Exam
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.StartExamRequest true "组卷条件"
+// @Success 200 {object} utils.Response{data=service.ExamDetail}
+// @Router /api/v1/exams [post]
+func (h *ExamHandler) Start(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.StartExamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	detail, err := h.examService.Start(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "考试已开始", detail)
+}
+
+// List 获取考试列表
+// @Summary 获取我的考试列表
+// @Tags Exam
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} utils.Response
+// @Router /api/v1/exams [get]
+func (h *ExamHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.ExamListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	items, total, err := h.examService.List(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.PageSuccess(c, int(total), req.Page, req.PageSize, items)
+}
+
+// Get 获取考试详情
+// @Summary 获取考试详情（交卷前不包含答案）
+// @Tags Exam
+// @Produce json
+// @Security Bearer
+// @Param id path int true "考试ID"
+// @Success 200 {object} utils.Response{data=service.ExamDetail}
+// @Router /api/v1/exams/:id [get]
+func (h *ExamHandler) Get(c *gin.Context) {
+	var uri examURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	detail, err := h.examService.Get(userID, uri.ID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, detail)
+}
+
+// SaveAnswer 保存答案
+// @Summary 保存单题答案（截止时间前可重复修改）
+// @Tags Exam
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "考试ID"
+// @Param exercise_id path int true "练习题ID"
+// @Param request body service.SaveExamAnswerRequest true "答案"
+// @Success 200 {object} utils.Response{data=service.ExamQuestion}
+// @Router /api/v1/exams/:id/answers/:exercise_id [put]
+func (h *ExamHandler) SaveAnswer(c *gin.Context) {
+	var uri struct {
+		ID         uint `uri:"id" binding:"required"`
+		ExerciseID uint `uri:"exercise_id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.SaveExamAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	question, err := h.examService.SaveAnswer(userID, uri.ID, uri.ExerciseID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "保存成功", question)
+}
+
+// Submit 交卷
+// @Summary 交卷并返回考试报告
+// @Tags Exam
+// @Produce json
+// @Security Bearer
+// @Param id path int true "考试ID"
+// @Success 200 {object} utils.Response{data=service.ExamReport}
+// @Router /api/v1/exams/:id/submit [post]
+func (h *ExamHandler) Submit(c *gin.Context) {
+	var uri examURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	report, err := h.examService.Submit(userID, uri.ID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "交卷成功", report)
+}
+
+// Report 获取考试报告
+// @Summary 获取考试报告（按知识点统计正确率）
+// @Tags Exam
+// @Produce json
+// @Security Bearer
+// @Param id path int true "考试ID"
+// @Success 200 {object} utils.Response{data=service.ExamReport}
+// @Router /api/v1/exams/:id/report [get]
+func (h *ExamHandler) Report(c *gin.Context) {
+	var uri examURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	report, err := h.examService.Report(userID, uri.ID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, report)
+}
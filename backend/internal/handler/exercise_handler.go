@@ -2,6 +2,7 @@ package handler
 
 import (
 	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/service"
 	"eight-gu-learning-platform/internal/utils"
 
@@ -56,7 +57,7 @@ func (h *ExerciseHandler) List(c *gin.Context) {
 }
 
 // GetByID 获取练习题详情
-// @Summary 获取练习题详情
+// @Summary 获取练习题详情（作答达到公开次数后或编辑/管理员可查看答案和解析）
 // @Tags Exercise
 // @Produce json
 // @Security Bearer
@@ -72,9 +73,16 @@ func (h *ExerciseHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	exercise, err := h.exerciseService.GetByID(uri.ID)
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	privileged := middleware.HasRole(c, models.RoleEditor, models.RoleAdmin)
+	exercise, err := h.exerciseService.GetByID(userID, uri.ID, privileged)
 	if err != nil {
-		utils.NotFoundError(c, err.Error())
+		utils.HandleError(c, err)
 		return
 	}
 
@@ -112,40 +120,39 @@ func (h *ExerciseHandler) SubmitAnswer(c *gin.Context) {
 		return
 	}
 
-	result, err := h.exerciseService.SubmitAnswer(userID, uri.ID, &req)
+	result, err := h.exerciseService.SubmitAnswer(c.Request.Context(), userID, uri.ID, &req)
 	if err != nil {
-		utils.InternalError(c, err.Error())
+		utils.HandleError(c, err)
 		return
 	}
 
 	utils.SuccessWithMessage(c, "提交成功", result)
 }
 
-// GetWrongList 获取错题列表
-// @Summary 获取错题列表
+// GetWrongList 获取错题本
+// @Summary 获取错题本（按练习题汇总）
 // @Tags Exercise
 // @Produce json
 // @Security Bearer
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param status query string false "状态 outstanding/mastered/dismissed/all" default(outstanding)
+// @Param knowledge_id query int false "知识点ID"
 // @Success 200 {object} utils.Response
 // @Router /api/v1/exercises/wrong [get]
 func (h *ExerciseHandler) GetWrongList(c *gin.Context) {
-	var query struct {
-		Page     int `form:"page" binding:"min=1"`
-		PageSize int `form:"page_size" binding:"min=1,max=100"`
-	}
-	if err := c.ShouldBindQuery(&query); err != nil {
+	var req service.WrongListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
 		utils.ParamError(c, err.Error())
 		return
 	}
 
 	// 设置默认值
-	if query.Page == 0 {
-		query.Page = 1
+	if req.Page == 0 {
+		req.Page = 1
 	}
-	if query.PageSize == 0 {
-		query.PageSize = 10
+	if req.PageSize == 0 {
+		req.PageSize = 10
 	}
 
 	userID := middleware.GetUserID(c)
@@ -154,11 +161,83 @@ func (h *ExerciseHandler) GetWrongList(c *gin.Context) {
 		return
 	}
 
-	items, total, err := h.exerciseService.GetWrongList(userID, query.Page, query.PageSize)
+	items, total, err := h.exerciseService.GetWrongList(userID, &req)
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
 	}
 
-	utils.PageSuccess(c, int(total), query.Page, query.PageSize, items)
+	utils.PageSuccess(c, int(total), req.Page, req.PageSize, items)
+}
+
+// MarkWrong 标记错题
+// @Summary 标记错题为已掌握、移出错题本或恢复为待巩固
+// @Tags Exercise
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "练习题ID"
+// @Param request body service.MarkWrongRequest true "标记状态"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/exercises/wrong/:id [put]
+func (h *ExerciseHandler) MarkWrong(c *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.MarkWrongRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.exerciseService.MarkWrong(userID, uri.ID, &req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "标记成功", nil)
+}
+
+// PracticeWrong 错题再练习
+// @Summary 从待巩固的错题中生成再练习题组
+// @Tags Exercise
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.WrongPracticeRequest false "题数与知识点"
+// @Success 200 {object} utils.Response{data=service.WrongPracticeSet}
+// @Router /api/v1/exercises/wrong/practice [post]
+func (h *ExerciseHandler) PracticeWrong(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.WrongPracticeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ParamError(c, err.Error())
+			return
+		}
+	}
+
+	set, err := h.exerciseService.PracticeWrong(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, set)
 }
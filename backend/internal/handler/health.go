@@ -1,36 +1,103 @@
 package handler
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/version"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// healthCheckTimeout 单个依赖检查的超时时间
+const healthCheckTimeout = 2 * time.Second
+
+// DependencyStatus 依赖检查结果；错误详情只记录在服务端日志中，不返回给调用方
+type DependencyStatus struct {
+	Status    string      `json:"status"`
+	LatencyMs float64     `json:"latency_ms"`
+	Pool      interface{} `json:"pool,omitempty"`
+}
+
+// DBPoolStats 数据库连接池统计
+type DBPoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
+
+// RedisPoolStats Redis 连接池统计
+type RedisPoolStats struct {
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+}
+
 // HealthHandler 健康检查处理器
 type HealthHandler struct {
-	dbStatus bool
-	redisStatus bool
+	db    *gorm.DB
+	cache *cache.Cache
 }
 
 // NewHealthHandler 创建健康检查处理器
-func NewHealthHandler() *HealthHandler {
+func NewHealthHandler(db *gorm.DB, c *cache.Cache) *HealthHandler {
 	return &HealthHandler{
-		dbStatus: true,
-		redisStatus: true,
+		db:    db,
+		cache: c,
 	}
 }
 
-// SetDBStatus 设置数据库状态
-func (h *HealthHandler) SetDBStatus(status bool) {
-	h.dbStatus = status
+// Live 存活检查（进程可响应即视为存活，不检查依赖）
+// @Summary 存活检查
+// @Tags System
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"version": version.Info(),
+	})
 }
 
-// SetRedisStatus 设置 Redis 状态
-func (h *HealthHandler) SetRedisStatus(status bool) {
-	h.redisStatus = status
+// Ready 就绪检查（数据库与 Redis 均可用才可接收流量）
+// @Summary 就绪检查
+// @Tags System
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx := c.Request.Context()
+	database := h.checkDatabase(ctx)
+	redis := h.checkCache(ctx)
+
+	status := http.StatusOK
+	overall := "ok"
+	if database.Status != "healthy" || redis.Status != "healthy" {
+		status = http.StatusServiceUnavailable
+		overall = "unavailable"
+	}
+
+	c.JSON(status, gin.H{
+		"status":   overall,
+		"database": database,
+		"cache":    redis,
+		"version":  version.Info(),
+	})
 }
 
-// Health 健康检查
+// Health 健康检查（兼容旧接口，等同于就绪检查）
 // @Summary 健康检查
 // @Tags System
 // @Accept json
@@ -38,21 +105,75 @@ func (h *HealthHandler) SetRedisStatus(status bool) {
 // @Success 200 {object} map[string]interface{}
 // @Router /health [get]
 func (h *HealthHandler) Health(c *gin.Context) {
-	status := http.StatusOK
-	if !h.dbStatus || !h.redisStatus {
-		status = http.StatusServiceUnavailable
+	h.Ready(c)
+}
+
+// checkDatabase 检查数据库连接
+func (h *HealthHandler) checkDatabase(ctx context.Context) DependencyStatus {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return unhealthy("database", 0, err)
 	}
 
-	c.JSON(status, gin.H{
-		"status":    "ok",
-		"database":  h.getStatus(h.dbStatus),
-		"cache":     h.getStatus(h.redisStatus),
-	})
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	latency := time.Since(start)
+
+	stats := sqlDB.Stats()
+	result := healthy(latency)
+	if err != nil {
+		result = unhealthy("database", latency, err)
+	}
+	result.Pool = DBPoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+	}
+	return result
+}
+
+// checkCache 检查 Redis 连接
+func (h *HealthHandler) checkCache(ctx context.Context) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := h.cache.Ping(ctx)
+	latency := time.Since(start)
+
+	stats := h.cache.PoolStats()
+	result := healthy(latency)
+	if err != nil {
+		result = unhealthy("redis", latency, err)
+	}
+	result.Pool = RedisPoolStats{
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+	}
+	return result
+}
+
+func healthy(latency time.Duration) DependencyStatus {
+	return DependencyStatus{
+		Status:    "healthy",
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
 }
 
-func (h *HealthHandler) getStatus(healthy bool) string {
-	if healthy {
-		return "healthy"
+func unhealthy(dependency string, latency time.Duration, err error) DependencyStatus {
+	log.Printf("Health check failed for %s: %v", dependency, err)
+	return DependencyStatus{
+		Status:    "unhealthy",
+		LatencyMs: float64(latency.Microseconds()) / 1000,
 	}
-	return "unhealthy"
 }
@@ -10,11 +10,11 @@ import (
 
 // KnowledgeHandler 知识点处理器
 type KnowledgeHandler struct {
-	knowledgeService *service.KnowledgeService
+	knowledgeService service.KnowledgeReader
 }
 
 // NewKnowledgeHandler 创建知识点处理器
-func NewKnowledgeHandler(knowledgeService *service.KnowledgeService) *KnowledgeHandler {
+func NewKnowledgeHandler(knowledgeService service.KnowledgeReader) *KnowledgeHandler {
 	return &KnowledgeHandler{
 		knowledgeService: knowledgeService,
 	}
@@ -84,7 +84,7 @@ func (h *KnowledgeHandler) GetByID(c *gin.Context) {
 }
 
 // GetGraph 获取知识图谱数据
-// @Summary 获取知识图谱数据
+// @Summary 获取知识图谱数据（节点包含当前用户的学习状态）
 // @Tags Knowledge
 // @Produce json
 // @Security Bearer
@@ -103,7 +103,7 @@ func (h *KnowledgeHandler) GetGraph(c *gin.Context) {
 	}
 	c.ShouldBindQuery(&query)
 
-	graph, err := h.knowledgeService.GetGraph(query.CategoryID)
+	graph, err := h.knowledgeService.GetUserGraph(userID, query.CategoryID)
 	if err != nil {
 		utils.InternalError(c, err.Error())
 		return
@@ -111,3 +111,76 @@ func (h *KnowledgeHandler) GetGraph(c *gin.Context) {
 
 	utils.Success(c, graph)
 }
+
+// GetNeighbors 获取知识点邻域子图
+// @Summary 获取知识点 depth 层以内的邻域子图
+// @Tags Knowledge
+// @Produce json
+// @Security Bearer
+// @Param id path int true "知识点ID"
+// @Param depth query int false "深度（1~3）" default(1)
+// @Param type query string false "关联类型 prerequisite/related/extended"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/knowledge/:id/neighbors [get]
+func (h *KnowledgeHandler) GetNeighbors(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	var req service.NeighborRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	graph, err := h.knowledgeService.GetNeighbors(userID, uri.ID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, graph)
+}
+
+// ShortestPath 获取两个知识点之间的最短路径
+// @Summary 获取两个知识点之间的最短路径
+// @Tags Knowledge
+// @Produce json
+// @Security Bearer
+// @Param from query int true "起点知识点ID"
+// @Param to query int true "终点知识点ID"
+// @Param type query string false "关联类型 prerequisite/related/extended"
+// @Param directed query bool false "只沿关联方向查找"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/knowledge/path [get]
+func (h *KnowledgeHandler) ShortestPath(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.GraphPathRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	path, err := h.knowledgeService.ShortestPath(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, path)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LearningPathHandler 学习路径处理器
+type LearningPathHandler struct {
+	pathService *service.LearningPathService
+}
+
+// NewLearningPathHandler 创建学习路径处理器
+func NewLearningPathHandler(pathService *service.LearningPathService) *LearningPathHandler {
+	return &LearningPathHandler{
+		pathService: pathService,
+	}
+}
+
+// GetPath 获取学习路径
+// @Summary 获取到达目标知识点的学习路径（按前置关系排序的未掌握知识点）
+// @Tags Learning
+// @Produce json
+// @Security Bearer
+// @Param target query int true "目标知识点ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response "前置关系存在环"
+// @Router /api/v1/learning/path [get]
+func (h *LearningPathHandler) GetPath(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.LearningPathRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	path, err := h.pathService.GetPath(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, path)
+}
+
+// GetNextTopics 获取推荐学习的知识点
+// @Summary 获取推荐学习的知识点（前置均已掌握）
+// @Tags Learning
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "数量" default(5)
+// @Success 200 {object} utils.Response
+// @Router /api/v1/learning/recommendations [get]
+func (h *LearningPathHandler) GetNextTopics(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.NextTopicsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	topics, err := h.pathService.NextTopics(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, topics)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler 两步验证处理器
+type MFAHandler struct {
+	mfaService *service.MFAService
+}
+
+// NewMFAHandler 创建两步验证处理器
+func NewMFAHandler(mfaService *service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// Status 获取两步验证状态
+// @Summary 获取当前用户的两步验证状态
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.Response{data=service.MFAStatus}
+// @Router /api/v1/users/me/mfa [get]
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	status, err := h.mfaService.Status(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, status)
+}
+
+// Enroll 开始绑定两步验证
+// @Summary 生成 TOTP 密钥与二维码地址，使用验证器应用扫描后调用启用接口确认
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.Response{data=service.MFAEnrollment}
+// @Router /api/v1/users/me/mfa/enroll [post]
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, enrollment)
+}
+
+// Enable 启用两步验证
+// @Summary 输入验证码确认绑定并启用两步验证，返回恢复码（只展示一次）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.MFACodeRequest true "验证码"
+// @Success 200 {object} utils.Response{data=service.RecoveryCodesResponse}
+// @Router /api/v1/users/me/mfa/enable [post]
+func (h *MFAHandler) Enable(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	codes, err := h.mfaService.Enable(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已启用两步验证", codes)
+}
+
+// Disable 关闭两步验证
+// @Summary 校验密码与验证码（或恢复码）后关闭两步验证，角色要求两步验证时不能关闭
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.DisableMFARequest true "密码与验证码"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/users/me/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.mfaService.Disable(userID, &req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已关闭两步验证", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 校验验证码后重新生成恢复码，旧的恢复码全部作废
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.MFACodeRequest true "验证码"
+// @Success 200 {object} utils.Response{data=service.RecoveryCodesResponse}
+// @Router /api/v1/users/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, codes)
+}
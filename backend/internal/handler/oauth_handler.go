@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie 保存登录请求 state 的 Cookie，回调时与 state 参数比对
+const (
+	oauthStateCookie = "oauth_state"
+	oauthCookiePath  = "/api/v1/auth/oauth"
+)
+
+// OAuthHandler 第三方登录处理器
+type OAuthHandler struct {
+	oauthService *service.OAuthService
+}
+
+// NewOAuthHandler 创建第三方登录处理器
+func NewOAuthHandler(oauthService *service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// Providers 获取可用的第三方登录方式
+// @Summary 获取已启用的第三方登录方式
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]oauth.ProviderInfo}
+// @Router /api/v1/auth/oauth/providers [get]
+func (h *OAuthHandler) Providers(c *gin.Context) {
+	utils.Success(c, h.oauthService.Providers())
+}
+
+// Begin 发起第三方登录
+// @Summary 跳转到第三方登录页面（授权码 + PKCE），完成后回调跳转到前端 /oauth/callback
+// @Tags Auth
+// @Param provider path string true "提供方名称"
+// @Success 302
+// @Router /api/v1/auth/oauth/{provider} [get]
+func (h *OAuthHandler) Begin(c *gin.Context) {
+	redirect, err := h.oauthService.Begin(c.Request.Context(), c.Param("provider"), 0)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, redirect.State, 0, oauthCookiePath, "", isSecure(c), true)
+	c.Redirect(http.StatusFound, redirect.URL)
+}
+
+// Callback 第三方登录回调
+// @Summary 提供方回调：登录成功跳转到前端并带上一次性登录码 code，绑定成功带 linked，失败带 error
+// @Tags Auth
+// @Param provider path string true "提供方名称"
+// @Param code query string false "授权码"
+// @Param state query string true "授权请求标识"
+// @Param error query string false "提供方返回的错误"
+// @Success 302
+// @Router /api/v1/auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	var req service.OAuthCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	browserState, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, oauthCookiePath, "", isSecure(c), true)
+
+	c.Redirect(http.StatusFound, h.oauthService.Callback(c.Request.Context(), c.Param("provider"), &req, browserState))
+}
+
+// Exchange 使用一次性登录码换取令牌
+// @Summary 使用回调中的一次性登录码换取访问令牌和刷新令牌（登录码 1 分钟内有效，只能使用一次）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.OAuthExchangeRequest true "登录码"
+// @Success 200 {object} utils.Response{data=service.AuthResponse}
+// @Router /api/v1/auth/oauth/exchange [post]
+func (h *OAuthHandler) Exchange(c *gin.Context) {
+	var req service.OAuthExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.oauthService.Exchange(&req, loginClient(c))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "登录成功", result)
+}
+
+// ListIdentities 获取已绑定的第三方账号
+// @Summary 获取当前用户绑定的第三方账号
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.Response{data=[]models.UserIdentity}
+// @Router /api/v1/users/me/identities [get]
+func (h *OAuthHandler) ListIdentities(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	identities, err := h.oauthService.ListIdentities(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, identities)
+}
+
+// Link 绑定第三方账号
+// @Summary 发起绑定第三方账号，返回授权地址，完成后回调跳转到前端 /oauth/callback?linked={provider}
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} utils.Response{data=service.OAuthRedirect}
+// @Router /api/v1/users/me/identities/{provider} [post]
+func (h *OAuthHandler) Link(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	redirect, err := h.oauthService.Begin(c.Request.Context(), c.Param("provider"), userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	// 与登录相同，回调时校验授权是在发起绑定的浏览器中完成的
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, redirect.State, 0, oauthCookiePath, "", isSecure(c), true)
+	utils.Success(c, redirect)
+}
+
+// Unlink 解绑第三方账号
+// @Summary 解绑第三方账号
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/users/me/identities/{provider} [delete]
+func (h *OAuthHandler) Unlink(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	if err := h.oauthService.Unlink(userID, c.Param("provider")); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已解绑", nil)
+}
+
+// isSecure 请求是否经由 HTTPS（包括反向代理终止 TLS 的情况）
+func isSecure(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
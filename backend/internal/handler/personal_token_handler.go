@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalTokenHandler 个人访问令牌处理器
+type PersonalTokenHandler struct {
+	tokenService *service.PersonalTokenService
+}
+
+// NewPersonalTokenHandler 创建个人访问令牌处理器
+func NewPersonalTokenHandler(tokenService *service.PersonalTokenService) *PersonalTokenHandler {
+	return &PersonalTokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// List 获取个人访问令牌
+// @Summary 获取当前用户的个人访问令牌（不含令牌明文）
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.Response{data=[]models.PersonalAccessToken}
+// @Router /api/v1/users/me/tokens [get]
+func (h *PersonalTokenHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	tokens, err := h.tokenService.List(userID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, tokens)
+}
+
+// Create 创建个人访问令牌
+// @Summary 创建个人访问令牌，令牌明文只在创建时返回一次；权限范围 read（GET 请求）、write（修改请求）、admin（管理后台）
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.CreatePersonalTokenRequest true "名称、权限范围与有效期"
+// @Success 200 {object} utils.Response{data=service.CreatedPersonalToken}
+// @Router /api/v1/users/me/tokens [post]
+func (h *PersonalTokenHandler) Create(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	token, err := h.tokenService.Create(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "创建成功", token)
+}
+
+// Revoke 吊销个人访问令牌
+// @Summary 吊销个人访问令牌，立即失效
+// @Tags Auth
+// @Produce json
+// @Security Bearer
+// @Param id path int true "令牌ID"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/users/me/tokens/{id} [delete]
+func (h *PersonalTokenHandler) Revoke(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.tokenService.Revoke(userID, uri.ID); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "已吊销", nil)
+}
@@ -37,9 +37,14 @@ func (h *ProgressHandler) GetProgress(c *gin.Context) {
 		return
 	}
 
-	// 如果没有指定用户ID，使用当前用户
+	// 如果没有指定用户ID，使用当前用户；查看他人进度需要管理员权限
+	userID := middleware.GetUserID(c)
 	if req.UserID == 0 {
-		req.UserID = middleware.GetUserID(c)
+		req.UserID = userID
+	}
+	if req.UserID != userID && !middleware.IsAdmin(c) {
+		utils.ForbiddenError(c)
+		return
 	}
 
 	progresses, err := h.progressService.GetByUser(&req)
@@ -104,3 +109,42 @@ func (h *ProgressHandler) GetStats(c *gin.Context) {
 
 	utils.Success(c, stats)
 }
+
+// GetDueReviews 获取待复习队列
+// @Summary 获取待复习队列
+// @Tags Progress
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response
+// @Router /api/v1/learning/reviews/due [get]
+func (h *ProgressHandler) GetDueReviews(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.DueReviewRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	items, total, err := h.progressService.GetDueReviews(userID, &req)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.PageSuccess(c, int(total), req.Page, req.PageSize, items)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 全文检索处理器
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+// NewSearchHandler 创建全文检索处理器
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search 检索知识点和练习题
+// @Summary 全文检索知识点和练习题
+// @Tags Search
+// @Produce json
+// @Security Bearer
+// @Param q query string true "搜索关键词（支持 \"短语\"、OR、-排除）"
+// @Param type query string false "结果类型 knowledge/exercise"
+// @Param category_id query int false "分类ID"
+// @Param difficulty query string false "难度"
+// @Param frequency query string false "频率"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} utils.Response{data=service.SearchResponse}
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	var req service.SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	result, err := h.searchService.Search(&req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"eight-gu-learning-platform/internal/middleware"
+	"eight-gu-learning-platform/internal/service"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StudyHandler 学习时长记录处理器
+type StudyHandler struct {
+	studyService *service.StudyService
+}
+
+// NewStudyHandler 创建学习时长记录处理器
+func NewStudyHandler(studyService *service.StudyService) *StudyHandler {
+	return &StudyHandler{
+		studyService: studyService,
+	}
+}
+
+// Start 开始学习会话
+// @Summary 开始学习知识点（结束用户其它进行中的会话），之后需在 idle_timeout_seconds 内发送心跳
+// @Tags Learning
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body service.StartStudyRequest true "知识点"
+// @Success 200 {object} utils.Response{data=service.StudySessionView}
+// @Router /api/v1/learning/sessions [post]
+func (h *StudyHandler) Start(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	var req service.StartStudyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	session, err := h.studyService.Start(userID, &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, session)
+}
+
+// Heartbeat 学习会话心跳
+// @Summary 学习会话心跳，会话已结束或空闲超时返回 409，需重新开始
+// @Tags Learning
+// @Produce json
+// @Security Bearer
+// @Param id path int true "会话ID"
+// @Success 200 {object} utils.Response{data=service.StudySessionView}
+// @Failure 409 {object} utils.Response "会话已结束"
+// @Router /api/v1/learning/sessions/:id/heartbeat [post]
+func (h *StudyHandler) Heartbeat(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	session, err := h.studyService.Heartbeat(userID, id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, session)
+}
+
+// End 结束学习会话
+// @Summary 结束学习会话（已结束的会话直接返回）
+// @Tags Learning
+// @Produce json
+// @Security Bearer
+// @Param id path int true "会话ID"
+// @Success 200 {object} utils.Response{data=service.StudySessionView}
+// @Router /api/v1/learning/sessions/:id/end [post]
+func (h *StudyHandler) End(c *gin.Context) {
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		utils.UnauthorizedError(c)
+		return
+	}
+
+	session, err := h.studyService.End(userID, id)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, session)
+}
@@ -64,9 +64,9 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// 检查权限
+	// 检查权限：仅本人或管理员可修改
 	userID := middleware.GetUserID(c)
-	if userID != uri.ID {
+	if userID != uri.ID && !middleware.IsAdmin(c) {
 		utils.ForbiddenError(c)
 		return
 	}
@@ -85,3 +85,72 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 	utils.SuccessWithMessage(c, "更新成功", user)
 }
+
+// List 获取用户列表
+// @Summary 获取用户列表
+// @Tags Admin
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param role query string false "角色"
+// @Param search query string false "邮箱或用户名关键词"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/users [get]
+func (h *UserHandler) List(c *gin.Context) {
+	var req service.UserListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+
+	users, total, err := h.userService.List(&req)
+	if err != nil {
+		utils.InternalError(c, err.Error())
+		return
+	}
+
+	utils.PageSuccess(c, int(total), req.Page, req.PageSize, users)
+}
+
+// UpdateRole 更新用户角色
+// @Summary 更新用户角色
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Param request body service.UpdateRoleRequest true "角色"
+// @Success 200 {object} utils.Response
+// @Router /api/v1/admin/users/:id/role [put]
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	var uri struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	var req service.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ParamError(c, err.Error())
+		return
+	}
+
+	user, err := h.userService.UpdateRole(middleware.GetUserID(c), uri.ID, req.Role)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "更新成功", user)
+}
@@ -0,0 +1,97 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+)
+
+// Message 邮件内容（纯文本）
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New 根据配置创建邮件发送器：smtp 通过 SMTP 服务器发送，file 把邮件写入目录，log 只打印到日志
+func New(cfg *config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg)
+	case "file":
+		return NewFileMailer(cfg.From, cfg.Dir)
+	case "", "log":
+		return NewFileMailer(cfg.From, "")
+	}
+	return nil, fmt.Errorf("unknown mail driver: %s", cfg.Driver)
+}
+
+// FileMailer 开发与测试用的邮件发送器：邮件保存为目录下的 .eml 文件，目录为空时只打印日志
+type FileMailer struct {
+	from string
+	dir  string
+	seq  atomic.Uint64
+}
+
+// NewFileMailer 创建保存到本地目录的邮件发送器
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create mail dir: %w", err)
+		}
+	}
+	return &FileMailer{from: from, dir: dir}, nil
+}
+
+// Send 保存或打印邮件
+func (m *FileMailer) Send(msg Message) error {
+	if m.dir == "" {
+		log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102-150405"), m.seq.Add(1))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, compose(m.from, msg, time.Now()), 0o600); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s saved to %s", msg.To, path)
+	return nil
+}
+
+// compose 生成 RFC 5322 邮件，主题按 RFC 2047 编码
+func compose(from string, msg Message, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", encodeAddress(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// encodeAddress 编码包含非 ASCII 显示名的地址
+func encodeAddress(address string) string {
+	addr, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return addr.String()
+}
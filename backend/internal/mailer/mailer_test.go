@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"eight-gu-learning-platform/internal/config"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m, err := New(&config.MailConfig{Driver: "file", From: "八股学习平台 <no-reply@example.com>", Dir: dir})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	body := "请打开以下链接：\nhttp://localhost:3000/reset-password?token=abc\n"
+	for range 2 {
+		if err := m.Send(Message{To: "user@example.com", Subject: "重置你的密码", Body: body}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 mails, got %v", files)
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	header := mail.Header(msg.Header)
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject")); subject != "重置你的密码" {
+		t.Errorf("Unexpected subject: %q", header.Get("Subject"))
+	}
+	if from, err := header.AddressList("From"); err != nil || from[0].Name != "八股学习平台" || from[0].Address != "no-reply@example.com" {
+		t.Errorf("Unexpected from: %q (%v)", header.Get("From"), err)
+	}
+	content, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(content), "token=abc\r\n") {
+		t.Errorf("Body not preserved: %q", content)
+	}
+}
+
+func TestNewRejectsUnknownDriver(t *testing.T) {
+	if _, err := New(&config.MailConfig{Driver: "carrier-pigeon"}); err == nil {
+		t.Error("Expected error for unknown driver")
+	}
+	if _, err := New(&config.MailConfig{Driver: "smtp", From: "no-reply@example.com"}); err == nil {
+		t.Error("Expected error without smtp host")
+	}
+}
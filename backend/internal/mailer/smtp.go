@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+)
+
+// SMTPMailer 通过 SMTP 服务器发送邮件，服务器支持时自动使用 STARTTLS
+type SMTPMailer struct {
+	addr   string
+	from   string
+	sender string // 信封发件人
+	auth   smtp.Auth
+}
+
+// NewSMTPMailer 创建 SMTP 邮件发送器
+func NewSMTPMailer(cfg *config.MailConfig) (*SMTPMailer, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mail from address: %w", err)
+	}
+	if cfg.SMTP.Host == "" {
+		return nil, fmt.Errorf("smtp host is required")
+	}
+
+	m := &SMTPMailer{
+		addr:   net.JoinHostPort(cfg.SMTP.Host, strconv.Itoa(cfg.SMTP.Port)),
+		from:   cfg.From,
+		sender: from.Address,
+	}
+	if cfg.SMTP.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Host)
+	}
+	return m, nil
+}
+
+// Send 发送邮件
+func (m *SMTPMailer) Send(msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+	return smtp.SendMail(m.addr, m.auth, m.sender, []string{to.Address}, compose(m.from, msg, time.Now()))
+}
@@ -1,15 +1,29 @@
 package middleware
 
 import (
+	"net/http"
+	"slices"
 	"strings"
 
+	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware JWT 认证中间件
-func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+// RevocationChecker 访问令牌吊销检查
+type RevocationChecker interface {
+	IsRevoked(claims *utils.Claims) (bool, error)
+}
+
+// PersonalTokenVerifier 个人访问令牌校验，返回令牌所属用户的身份与权限范围
+type PersonalTokenVerifier interface {
+	VerifyPersonalToken(token string) (*utils.Claims, []string, error)
+}
+
+// AuthMiddleware 认证中间件，接受 JWT 访问令牌与个人访问令牌；
+// 个人访问令牌按请求方法校验 read / write 权限
+func AuthMiddleware(jwtManager *utils.JWTManager, revocation RevocationChecker, tokens PersonalTokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取 Authorization Header
 		authHeader := c.GetHeader("Authorization")
@@ -27,25 +41,30 @@ func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
 			return
 		}
 
-		// 验证 Token
-		claims, err := jwtManager.ParseToken(parts[1])
-		if err != nil {
+		claims, scopes, ok := authenticate(parts[1], jwtManager, revocation, tokens)
+		if !ok {
 			utils.UnauthorizedError(c)
 			c.Abort()
 			return
 		}
+		if scopes != nil && !slices.Contains(scopes, methodScope(c.Request.Method)) {
+			utils.Error(c, utils.CodeErrorForbidden, "访问令牌缺少 "+methodScope(c.Request.Method)+" 权限")
+			c.Abort()
+			return
+		}
 
 		// 将用户信息存入 Context
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("username", claims.Username)
+		setClaims(c, claims)
+		if scopes != nil {
+			c.Set("token_scopes", scopes)
+		}
 
 		c.Next()
 	}
 }
 
 // OptionalAuthMiddleware 可选认证中间件（允许匿名访问）
-func OptionalAuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+func OptionalAuthMiddleware(jwtManager *utils.JWTManager, revocation RevocationChecker, tokens PersonalTokenVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -59,20 +78,72 @@ func OptionalAuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
 			return
 		}
 
-		claims, err := jwtManager.ParseToken(parts[1])
-		if err != nil {
+		claims, scopes, ok := authenticate(parts[1], jwtManager, revocation, tokens)
+		if !ok || (scopes != nil && !slices.Contains(scopes, methodScope(c.Request.Method))) {
 			c.Next()
 			return
 		}
 
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("username", claims.Username)
+		setClaims(c, claims)
+		if scopes != nil {
+			c.Set("token_scopes", scopes)
+		}
 
 		c.Next()
 	}
 }
 
+// authenticate 校验令牌：带个人访问令牌前缀的按个人访问令牌校验（scopes 不为 nil），
+// 否则按 JWT 校验并检查是否已被吊销（登出、修改密码等）
+func authenticate(token string, jwtManager *utils.JWTManager, revocation RevocationChecker, tokens PersonalTokenVerifier) (*utils.Claims, []string, bool) {
+	if strings.HasPrefix(token, models.PersonalTokenPrefix) {
+		claims, scopes, err := tokens.VerifyPersonalToken(token)
+		if err != nil {
+			return nil, nil, false
+		}
+		if scopes == nil {
+			scopes = []string{}
+		}
+		return claims, scopes, true
+	}
+
+	claims, err := jwtManager.ParseToken(token)
+	if err != nil {
+		return nil, nil, false
+	}
+	if revoked, err := revocation.IsRevoked(claims); err != nil || revoked {
+		return nil, nil, false
+	}
+	return claims, nil, true
+}
+
+// methodScope 请求方法需要的个人访问令牌权限
+func methodScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.TokenScopeRead
+	}
+	return models.TokenScopeWrite
+}
+
+// setClaims 将 Token 中的用户信息存入 Context
+func setClaims(c *gin.Context, claims *utils.Claims) {
+	c.Set("claims", claims)
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("username", claims.Username)
+	c.Set("role", claims.Role)
+}
+
+// GetClaims 从 Context 获取 Token Claims
+func GetClaims(c *gin.Context) *utils.Claims {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return nil
+	}
+	return claims.(*utils.Claims)
+}
+
 // GetUserID 从 Context 获取用户 ID
 func GetUserID(c *gin.Context) uint {
 	userID, exists := c.Get("user_id")
@@ -81,3 +152,76 @@ func GetUserID(c *gin.Context) uint {
 	}
 	return userID.(uint)
 }
+
+// GetTokenScopes 从 Context 获取个人访问令牌的权限范围，使用 JWT 认证时返回 nil
+func GetTokenScopes(c *gin.Context) []string {
+	scopes, exists := c.Get("token_scopes")
+	if !exists {
+		return nil
+	}
+	return scopes.([]string)
+}
+
+// GetRole 从 Context 获取用户角色
+func GetRole(c *gin.Context) string {
+	role, exists := c.Get("role")
+	if !exists {
+		return ""
+	}
+	return role.(string)
+}
+
+// HasRole 检查当前用户是否拥有任一指定角色
+func HasRole(c *gin.Context, roles ...string) bool {
+	role := GetRole(c)
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin 检查当前用户是否为管理员
+func IsAdmin(c *gin.Context) bool {
+	return HasRole(c, models.RoleAdmin)
+}
+
+// RequireRole 角色校验中间件，需在 AuthMiddleware 之后使用
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasRole(c, roles...) {
+			utils.ForbiddenError(c)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireScope 个人访问令牌需要拥有指定权限，使用 JWT 认证时不限制；需在 AuthMiddleware 之后使用
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes := GetTokenScopes(c); scopes != nil && !slices.Contains(scopes, scope) {
+			utils.Error(c, utils.CodeErrorForbidden, "访问令牌缺少 "+scope+" 权限")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession 只允许登录会话（JWT）访问，用于令牌管理、修改密码等账号安全相关接口；需在 AuthMiddleware 之后使用
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetTokenScopes(c) != nil {
+			utils.Error(c, utils.CodeErrorForbidden, "该接口不支持个人访问令牌")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeTokens 只认识 egp_read 与 egp_write 两个个人访问令牌
+type fakeTokens struct{}
+
+func (fakeTokens) VerifyPersonalToken(token string) (*utils.Claims, []string, error) {
+	switch token {
+	case "egp_read":
+		return &utils.Claims{UserID: 7, Role: models.RoleLearner}, []string{models.TokenScopeRead}, nil
+	case "egp_write":
+		return &utils.Claims{UserID: 7, Role: models.RoleLearner}, []string{models.TokenScopeRead, models.TokenScopeWrite}, nil
+	}
+	return nil, nil, utils.ErrPersonalTokenInvalid
+}
+
+// notRevoked JWT 均未吊销
+type notRevoked struct{}
+
+func (notRevoked) IsRevoked(*utils.Claims) (bool, error) { return false, nil }
+
+func TestAuthMiddlewarePersonalTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtMgr := utils.NewJWTManager("test-secret", time.Hour, "test")
+	r := gin.New()
+	r.Use(AuthMiddleware(jwtMgr, notRevoked{}, fakeTokens{}))
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "%d", GetUserID(c)) }
+	r.GET("/progress", ok)
+	r.POST("/progress", ok)
+	r.GET("/me/tokens", RequireSession(), ok)
+	r.GET("/admin", RequireScope(models.TokenScopeAdmin), ok)
+
+	jwt, err := jwtMgr.GenerateToken(7, "ada@example.com", "ada", models.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, path, token string
+		want                int
+	}{
+		{http.MethodGet, "/progress", "egp_read", http.StatusOK},
+		{http.MethodPost, "/progress", "egp_read", http.StatusForbidden},
+		{http.MethodPost, "/progress", "egp_write", http.StatusOK},
+		{http.MethodGet, "/progress", "egp_unknown", http.StatusUnauthorized},
+		{http.MethodGet, "/me/tokens", "egp_write", http.StatusForbidden},
+		{http.MethodGet, "/admin", "egp_write", http.StatusForbidden},
+		{http.MethodGet, "/me/tokens", jwt, http.StatusOK},
+		{http.MethodGet, "/admin", jwt, http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s with %.12s: got %d, want %d", tt.method, tt.path, tt.token, w.Code, tt.want)
+		}
+	}
+}
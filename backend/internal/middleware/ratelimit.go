@@ -1,93 +1,67 @@
 package middleware
 
 import (
-	"sync"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
 	"time"
 
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/config"
 	"eight-gu-learning-platform/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimiter 限流器
-type RateLimiter struct {
-	mu       sync.Mutex
-	clients  map[string]*ClientInfo
-	interval time.Duration
-	limit    int
+// Limiter 限流器
+type Limiter interface {
+	Allow(key string, limit int, window time.Duration) (cache.RateLimitResult, error)
 }
 
-// ClientInfo 客户端信息
-type ClientInfo struct {
-	Requests int
-	LastSeen time.Time
-}
-
-// NewRateLimiter 创建限流器
-func NewRateLimiter(interval time.Duration, limit int) *RateLimiter {
-	rl := &RateLimiter{
-		clients:  make(map[string]*ClientInfo),
-		interval: interval,
-		limit:    limit,
-	}
+// KeyFunc 从请求中提取限流主体
+type KeyFunc func(c *gin.Context) string
 
-	// 定期清理过期客户端
-	go rl.cleanup()
-
-	return rl
+// ClientIPKey 按客户端 IP 限流
+func ClientIPKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
 }
 
-// Allow 检查是否允许请求
-func (rl *RateLimiter) Allow(clientID string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	now := time.Now()
-	client, exists := rl.clients[clientID]
-
-	// 如果不存在或者已过期，重置
-	if !exists || now.Sub(client.LastSeen) >= rl.interval {
-		rl.clients[clientID] = &ClientInfo{
-			Requests: 1,
-			LastSeen: now,
-		}
-		return true
+// UserKey 按认证用户限流，匿名请求回退到客户端 IP
+func UserKey(c *gin.Context) string {
+	if userID := GetUserID(c); userID != 0 {
+		return fmt.Sprintf("user:%d", userID)
 	}
+	return ClientIPKey(c)
+}
 
-	// 检查是否超过限制
-	if client.Requests >= rl.limit {
-		return false
+// RateLimitMiddleware 限流中间件，name 区分不同策略的计数；
+// 超限返回 429 并附带 RateLimit-* 与 Retry-After 响应头。
+// Redis 异常时放行请求，避免限流故障导致服务不可用
+func RateLimitMiddleware(limiter Limiter, name string, policy config.RateLimitPolicy, keyFunc KeyFunc) gin.HandlerFunc {
+	if limiter == nil || policy.Limit <= 0 || policy.Window <= 0 {
+		return func(c *gin.Context) { c.Next() }
 	}
 
-	client.Requests++
-	client.LastSeen = now
-	return true
-}
-
-// cleanup 清理过期客户端
-func (rl *RateLimiter) cleanup() {
-	ticker := time.NewTicker(time.Minute)
-	defer ticker.Stop()
+	policyHeader := fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Window.Seconds()))
 
-	for range ticker.C {
-		rl.mu.Lock()
-		now := time.Now()
-		for id, client := range rl.clients {
-			if now.Sub(client.LastSeen) >= rl.interval {
-				delete(rl.clients, id)
-			}
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(name+":"+keyFunc(c), policy.Limit, policy.Window)
+		if err != nil {
+			log.Printf("rate limit %s: %v", name, err)
+			c.Next()
+			return
 		}
-		rl.mu.Unlock()
-	}
-}
 
-// RateLimitMiddleware 限流中间件
-func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		clientID := c.ClientIP()
+		reset := strconv.Itoa(ceilSeconds(result.Reset))
+		c.Header("RateLimit-Policy", policyHeader)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", reset)
 
-		if !rl.Allow(clientID) {
-			utils.Error(c, utils.CodeErrorInternal, "请求过于频繁，请稍后再试")
+		if !result.Allowed {
+			c.Header("Retry-After", reset)
+			utils.TooManyRequestsError(c, "")
 			c.Abort()
 			return
 		}
@@ -95,3 +69,12 @@ func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ceilSeconds 将时长向上取整为秒（至少 1 秒）
+func ceilSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientIPKeyIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		want           string
+	}{
+		{"no proxy configured", nil, "203.0.113.5:40000", "198.51.100.7", "ip:203.0.113.5"},
+		{"untrusted peer", []string{"172.28.0.10"}, "203.0.113.5:40000", "198.51.100.7", "ip:203.0.113.5"},
+		// 直接访问发布的端口时对端是同一网段内的 Docker 网关
+		{"docker gateway", []string{"172.28.0.10"}, "172.28.0.1:40000", "198.51.100.7", "ip:172.28.0.1"},
+		{"trusted proxy", []string{"172.28.0.10"}, "172.28.0.10:40000", "198.51.100.7", "ip:198.51.100.7"},
+		// nginx 把客户端自带的 X-Forwarded-For 保留在前面，只有最右侧的不可信地址是真实客户端
+		{"client prefix through proxy", []string{"172.28.0.10"}, "172.28.0.10:40000", "1.2.3.4, 198.51.100.7", "ip:198.51.100.7"},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		if err := r.SetTrustedProxies(tt.trustedProxies); err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		r.GET("/", func(c *gin.Context) { got = ClientIPKey(c) })
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if got != tt.want {
+			t.Errorf("%s: ClientIPKey = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// advisoryLockID 迁移使用的 PostgreSQL 会话级咨询锁，防止多个实例同时迁移
+const advisoryLockID int64 = 0x65696768746775 // "eightgu"
+
+// filePattern 迁移文件名格式：NNN_name.up.sql / NNN_name.down.sql
+var filePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	// ErrChecksumDrift 已执行的迁移文件被修改
+	ErrChecksumDrift = errors.New("migration checksum drift")
+	// ErrMissingMigration 数据库中记录的迁移在迁移目录中不存在
+	ErrMissingMigration = errors.New("applied migration not found")
+	// ErrUnknownVersion 指定的版本不存在
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // Up 脚本的 SHA-256
+}
+
+// Status 迁移状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Drift     bool // 已执行但文件内容已变更
+	Missing   bool // 已执行但迁移文件不存在
+}
+
+// applied 已执行的迁移记录
+type applied struct {
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Load 从目录加载迁移文件，按版本号升序排列
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := filePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		m.Checksum = checksum(m.Up)
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator 版本化迁移执行器
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New 创建迁移执行器
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up 按顺序执行未执行的迁移，n <= 0 时执行全部
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(records); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if n > 0 && len(done) >= n {
+				break
+			}
+			if _, ok := records[mig.Version]; ok {
+				continue
+			}
+
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx,
+					`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					mig.Version, mig.Name, mig.Checksum)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down 按倒序回滚已执行的迁移，n <= 0 时默认回滚一个
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		n = 1
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(records); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < n; i-- {
+			mig := m.migrations[i]
+			if _, ok := records[mig.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(mig.Down) == "" {
+				return fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+			}
+
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status 获取所有迁移的执行状态
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]bool, len(m.migrations))
+		for _, mig := range m.migrations {
+			known[mig.Version] = true
+			status := Status{Version: mig.Version, Name: mig.Name}
+			if rec, ok := records[mig.Version]; ok {
+				appliedAt := rec.AppliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Drift = rec.Checksum != mig.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+
+		// 数据库中存在但目录中缺失的迁移
+		for version, rec := range records {
+			if known[version] {
+				continue
+			}
+			appliedAt := rec.AppliedAt
+			statuses = append(statuses, Status{
+				Version:   version,
+				Name:      rec.Name,
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+		return nil
+	})
+	return statuses, err
+}
+
+// Force 将数据库标记为指定版本而不执行任何脚本：
+// 不大于该版本的迁移记为已执行（并以当前文件校验和为准），大于该版本的记录被删除。
+// 用于接管已有数据库或修复校验和漂移，version 为 0 时清空记录
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !m.has(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return inTx(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version > $1`, version); err != nil {
+				return err
+			}
+			for _, mig := range m.migrations {
+				if mig.Version > version {
+					break
+				}
+				_, err := tx.ExecContext(ctx, `
+					INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)
+					ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, checksum = EXCLUDED.checksum`,
+					mig.Version, mig.Name, mig.Checksum)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// verify 检查已执行迁移的文件是否被修改或删除
+func (m *Migrator) verify(records map[int64]applied) error {
+	files := make(map[int64]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		files[mig.Version] = mig
+	}
+
+	var drifted, missing []string
+	for version, rec := range records {
+		mig, ok := files[version]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%d_%s", version, rec.Name))
+			continue
+		}
+		if mig.Checksum != rec.Checksum {
+			drifted = append(drifted, fmt.Sprintf("%d_%s", version, mig.Name))
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%w: %s", ErrMissingMigration, strings.Join(missing, ", "))
+	}
+	if len(drifted) > 0 {
+		sort.Strings(drifted)
+		return fmt.Errorf("%w: %s", ErrChecksumDrift, strings.Join(drifted, ", "))
+	}
+	return nil
+}
+
+func (m *Migrator) has(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock 在持有咨询锁的连接上执行操作（会话级锁需要固定同一连接）
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// loadApplied 读取已执行的迁移记录
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[int64]applied, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make(map[int64]applied)
+	for rows.Next() {
+		var version int64
+		var rec applied
+		if err := rows.Scan(&version, &rec.Name, &rec.Checksum, &rec.AppliedAt); err != nil {
+			return nil, err
+		}
+		records[version] = rec
+	}
+	return records, rows.Err()
+}
+
+// inTx 在事务中执行，出错时回滚
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// checksum 计算脚本校验和
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadOrdersAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_tenth.up.sql":       {Data: []byte("SELECT 10;")},
+		"002_second.up.sql":      {Data: []byte("SELECT 2;")},
+		"002_second.down.sql":    {Data: []byte("SELECT -2;")},
+		"001_first.up.sql":       {Data: []byte("SELECT 1;")},
+		"001_first.down.sql":     {Data: []byte("SELECT -1;")},
+		"README.md":              {Data: []byte("ignored")},
+		"003_not_sql.up.sql.bak": {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := []int64{1, 2, 10}
+	if len(migrations) != len(want) {
+		t.Fatalf("got %d migrations, want %d", len(migrations), len(want))
+	}
+	for i, m := range migrations {
+		if m.Version != want[i] {
+			t.Errorf("migrations[%d].Version = %d, want %d", i, m.Version, want[i])
+		}
+	}
+	if migrations[1].Name != "second" || migrations[1].Down != "SELECT -2;" {
+		t.Errorf("unexpected migration: %+v", migrations[1])
+	}
+	if migrations[2].Down != "" {
+		t.Errorf("migration without down script should have empty Down")
+	}
+	if migrations[0].Checksum != checksum("SELECT 1;") {
+		t.Errorf("checksum mismatch")
+	}
+}
+
+func TestLoadRejectsInvalidSets(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"duplicate version": {
+			"001_a.up.sql": {Data: []byte("SELECT 1;")},
+			"001_b.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"missing up": {
+			"001_a.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestVerifyDetectsDriftAndMissing(t *testing.T) {
+	m := New(nil, []Migration{
+		{Version: 1, Name: "first", Checksum: checksum("SELECT 1;")},
+		{Version: 2, Name: "second", Checksum: checksum("SELECT 2;")},
+	})
+
+	if err := m.verify(map[int64]applied{1: {Name: "first", Checksum: checksum("SELECT 1;")}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := m.verify(map[int64]applied{2: {Name: "second", Checksum: checksum("SELECT 20;")}})
+	if !errors.Is(err, ErrChecksumDrift) {
+		t.Errorf("expected ErrChecksumDrift, got %v", err)
+	}
+
+	err = m.verify(map[int64]applied{3: {Name: "third", Checksum: "x"}})
+	if !errors.Is(err, ErrMissingMigration) {
+		t.Errorf("expected ErrMissingMigration, got %v", err)
+	}
+}
+
+func TestRepositoryMigrationsLoad(t *testing.T) {
+	migrations, err := Load(os.DirFS("../../migrations"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for i, m := range migrations {
+		if m.Down == "" {
+			t.Errorf("%03d_%s has no down script", m.Version, m.Name)
+		}
+		if i > 0 && m.Version == migrations[i-1].Version {
+			t.Errorf("duplicate version %d", m.Version)
+		}
+	}
+}
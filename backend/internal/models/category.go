@@ -10,6 +10,7 @@ import (
 type Category struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Name        string         `gorm:"type:varchar(100);not null" json:"name"`
+	Slug        string         `gorm:"type:varchar(100);not null;default:''" json:"slug"`
 	Description string         `gorm:"type:text" json:"description"`
 	ParentID    *uint          `gorm:"index" json:"parent_id"`
 	Parent      *Category      `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
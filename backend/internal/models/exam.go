@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 考试状态
+const (
+	ExamStatusInProgress = "in_progress" // 答题中
+	ExamStatusSubmitted  = "submitted"   // 已交卷
+	ExamStatusExpired    = "expired"     // 超时自动交卷
+)
+
+// ExamSession 模拟面试考试
+type ExamSession struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	UserID          uint           `gorm:"not null;index" json:"user_id"`
+	CategoryID      *uint          `json:"category_id"`
+	Difficulty      string         `gorm:"type:varchar(20)" json:"difficulty"`
+	QuestionCount   int            `gorm:"not null" json:"question_count"`
+	DurationSeconds int            `gorm:"not null" json:"duration_seconds"`
+	Status          string         `gorm:"type:varchar(20);not null;default:'in_progress';check:status IN ('in_progress','submitted','expired')" json:"status"`
+	StartedAt       time.Time      `gorm:"not null" json:"started_at"`
+	Deadline        time.Time      `gorm:"not null" json:"deadline"`
+	SubmittedAt     *time.Time     `json:"submitted_at"`
+	CorrectCount    int            `gorm:"default:0" json:"correct_count"`
+	Score           float64        `gorm:"default:0" json:"score"` // 百分制
+	Answers         []ExamAnswer   `gorm:"foreignKey:SessionID" json:"answers,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (ExamSession) TableName() string {
+	return "exam_sessions"
+}
+
+// ExamAnswer 考试答题记录
+type ExamAnswer struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	SessionID  uint       `gorm:"not null;uniqueIndex:idx_exam_answers_session_exercise" json:"session_id"`
+	ExerciseID uint       `gorm:"not null;uniqueIndex:idx_exam_answers_session_exercise" json:"exercise_id"`
+	Exercise   Exercise   `gorm:"foreignKey:ExerciseID" json:"exercise,omitempty"`
+	Position   int        `gorm:"not null" json:"position"`
+	UserAnswer string     `gorm:"type:jsonb;default:'[]'" json:"user_answer"` // JSON array
+	IsCorrect  bool       `json:"is_correct"`
+	Score      float64    `gorm:"not null;default:0" json:"score"` // 0~1，部分得分
+	AnsweredAt *time.Time `json:"answered_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ExamAnswer) TableName() string {
+	return "exam_answers"
+}
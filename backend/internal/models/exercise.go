@@ -6,15 +6,28 @@ import (
 	"gorm.io/gorm"
 )
 
+// 练习题类型
+const (
+	ExerciseTypeSingleChoice   = "single_choice"   // 单选题
+	ExerciseTypeMultipleChoice = "multiple_choice" // 多选题
+	ExerciseTypeTrueFalse      = "true_false"      // 判断题
+	ExerciseTypeFillBlank      = "fill_blank"      // 填空题
+	ExerciseTypeOrdering       = "ordering"        // 排序题
+	ExerciseTypeCode           = "code"            // 代码题，由沙箱运行隐藏测试判分
+)
+
 // Exercise 练习题模型
 type Exercise struct {
 	ID              uint           `gorm:"primaryKey" json:"id"`
+	Slug             string         `gorm:"type:varchar(100);not null;default:''" json:"slug"`
 	KnowledgePointID uint           `gorm:"not null;index" json:"knowledge_point_id"`
 	Question        string         `gorm:"type:text;not null" json:"question"`
 	Options         string         `gorm:"type:jsonb;not null" json:"options"` // JSON array
 	Answer          string         `gorm:"type:jsonb;not null" json:"answer"` // JSON array
-	Type            string         `gorm:"type:varchar(20);check:type IN ('single_choice','multiple_choice')" json:"type"`
+	Type            string         `gorm:"type:varchar(20);check:type IN ('single_choice','multiple_choice','true_false','fill_blank','ordering','code')" json:"type"`
 	Explanation     string         `gorm:"type:text" json:"explanation"`
+	StarterCode     string         `gorm:"type:text" json:"starter_code"`         // 代码题的初始代码
+	TestCode        string         `gorm:"type:text" json:"test_code,omitempty"` // 代码题的隐藏测试，不对学习者返回
 	Difficulty      string         `gorm:"type:varchar(20);check:difficulty IN ('easy','medium','hard')" json:"difficulty"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
@@ -35,6 +48,8 @@ type ExerciseRecord struct {
 	Exercise   Exercise       `gorm:"foreignKey:ExerciseID" json:"exercise,omitempty"`
 	UserAnswer string         `gorm:"type:jsonb" json:"user_answer"` // JSON array
 	IsCorrect  bool           `json:"is_correct"`
+	Score      float64        `gorm:"not null;default:0" json:"score"` // 0~1，部分得分
+	TestResults string        `gorm:"type:jsonb;not null;default:'[]'" json:"test_results"` // 代码题每个测试的结果
 	CreatedAt  time.Time      `json:"created_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -43,3 +58,26 @@ type ExerciseRecord struct {
 func (ExerciseRecord) TableName() string {
 	return "exercise_records"
 }
+
+// 错题标记状态
+const (
+	WrongStatusOutstanding = "outstanding" // 待巩固（未标记，或标记后又答错）
+	WrongStatusMastered    = "mastered"    // 已掌握
+	WrongStatusDismissed   = "dismissed"   // 已移出错题本
+)
+
+// WrongExerciseMark 错题标记，标记之后再次答错时重新计入待巩固
+type WrongExerciseMark struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_wrong_exercise_marks_user_exercise" json:"user_id"`
+	ExerciseID uint      `gorm:"not null;uniqueIndex:idx_wrong_exercise_marks_user_exercise" json:"exercise_id"`
+	Status     string    `gorm:"type:varchar(20);not null;check:status IN ('mastered','dismissed')" json:"status"`
+	MarkedAt   time.Time `gorm:"not null" json:"marked_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (WrongExerciseMark) TableName() string {
+	return "wrong_exercise_marks"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// UserIdentity 绑定到用户的第三方账号，每个用户在同一提供方下只能绑定一个账号
+type UserIdentity struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;uniqueIndex:idx_user_identities_user_provider" json:"-"`
+	Provider    string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_user_identities_provider_subject;uniqueIndex:idx_user_identities_user_provider" json:"provider"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject" json:"-"`
+	Email       string     `gorm:"type:varchar(255)" json:"email"`
+	Login       string     `gorm:"type:varchar(255)" json:"login"` // 提供方的用户名或显示名称
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -10,6 +10,7 @@ import (
 type KnowledgePoint struct {
 	ID            uint           `gorm:"primaryKey" json:"id"`
 	Title         string         `gorm:"type:varchar(255);not null" json:"title"`
+	Slug          string         `gorm:"type:varchar(100);not null;default:''" json:"slug"`
 	Description   string         `gorm:"type:text" json:"description"`
 	Content       string         `gorm:"type:text" json:"content"`
 	CategoryID    uint           `gorm:"not null;index" json:"category_id"`
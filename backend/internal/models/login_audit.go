@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// 登录审计结果
+const (
+	LoginSucceeded          = "success"             // 登录成功
+	LoginInvalidCredentials = "invalid_credentials" // 邮箱或密码错误
+	LoginLocked             = "locked"              // 失败次数过多被锁定
+	LoginEmailNotVerified   = "email_not_verified"  // 密码正确但邮箱未验证
+	LoginMFARequired        = "mfa_required"        // 密码正确，等待两步验证
+	LoginMFAFailed          = "mfa_failed"          // 两步验证码错误
+)
+
+// 登录方式：密码登录，或 "oauth:" 加提供方名称
+const (
+	LoginMethodPassword = "password"
+	LoginMethodOAuth    = "oauth:"
+)
+
+// LoginAudit 登录审计记录，邮箱不存在时 UserID 为空
+type LoginAudit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"index:idx_login_audits_user_created" json:"-"`
+	Email     string    `gorm:"type:varchar(255);not null" json:"email"`
+	IP        string    `gorm:"type:varchar(45);not null" json:"ip"`
+	UserAgent string    `gorm:"type:varchar(500)" json:"user_agent"`
+	Success   bool      `gorm:"not null" json:"success"`
+	Reason    string    `gorm:"type:varchar(30);not null" json:"reason"`
+	Method    string    `gorm:"type:varchar(60);not null;default:'password'" json:"method"`
+	CreatedAt time.Time `gorm:"index:idx_login_audits_user_created" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginAudit) TableName() string {
+	return "login_audits"
+}
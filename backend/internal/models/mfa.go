@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// UserMFA 用户的 TOTP 两步验证，EnabledAt 为空表示正在绑定、尚未启用
+type UserMFA struct {
+	UserID       uint       `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Secret       string     `gorm:"type:varchar(255);not null" json:"-"` // 加密保存的 TOTP 密钥
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // 最近一次通过验证的时间步，同一验证码不能重复使用
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFARecoveryCode 两步验证恢复码，只保存哈希，每个只能使用一次
+type MFARecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"-"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// PersonalTokenPrefix 个人访问令牌前缀，用于与 JWT 区分
+const PersonalTokenPrefix = "egp_"
+
+// 个人访问令牌权限范围
+const (
+	TokenScopeRead  = "read"  // 只读请求（GET、HEAD）
+	TokenScopeWrite = "write" // 修改数据的请求
+	TokenScopeAdmin = "admin" // 管理后台接口，用户本身还需要具有相应角色
+)
+
+// PersonalAccessToken 个人访问令牌，供脚本与编辑器插件等集成使用；只保存哈希
+type PersonalAccessToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	TokenHash  string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Prefix     string     `gorm:"type:varchar(16);not null" json:"prefix"` // 令牌开头几位，便于辨认
+	Scopes     []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // 为空表示不过期
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// HasScope 是否拥有指定权限
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// Expired 是否已过期
+func (t *PersonalAccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
@@ -16,6 +16,11 @@ type LearningProgress struct {
 	Status           string         `gorm:"type:varchar(20);default:'not_started';check:status IN ('not_started','in_progress','completed')" json:"status"`
 	MasteryLevel     int            `gorm:"check:mastery_level >= 0 AND mastery_level <= 100;default:0" json:"mastery_level"`
 	LastReviewedAt   *time.Time     `json:"last_reviewed_at"`
+	EaseFactor       float64        `gorm:"default:2.5" json:"ease_factor"`
+	IntervalDays     int            `gorm:"default:0" json:"interval_days"`
+	Repetitions      int            `gorm:"default:0" json:"repetitions"`
+	Lapses           int            `gorm:"default:0" json:"lapses"`
+	DueAt            *time.Time     `gorm:"index" json:"due_at"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
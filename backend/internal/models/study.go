@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 学习会话状态
+const (
+	StudySessionActive  = "active"  // 学习中
+	StudySessionEnded   = "ended"   // 用户主动结束
+	StudySessionExpired = "expired" // 超时没有心跳，自动结束
+)
+
+// StudySession 学习会话：客户端定时发送心跳，时长从开始计到最后一次心跳或结束
+type StudySession struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	UserID           uint           `gorm:"not null;index:idx_study_sessions_user_started" json:"user_id"`
+	KnowledgePointID uint           `gorm:"not null;index" json:"knowledge_point_id"`
+	Status           string         `gorm:"type:varchar(20);not null;default:'active';check:status IN ('active','ended','expired')" json:"status"`
+	StartedAt        time.Time      `gorm:"not null;index:idx_study_sessions_user_started" json:"started_at"`
+	LastHeartbeatAt  time.Time      `gorm:"not null" json:"last_heartbeat_at"`
+	EndedAt          *time.Time     `json:"ended_at"`
+	DurationSeconds  int            `gorm:"not null;default:0" json:"duration_seconds"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (StudySession) TableName() string {
+	return "study_sessions"
+}
@@ -6,16 +6,34 @@ import (
 	"gorm.io/gorm"
 )
 
+// 用户角色
+const (
+	RoleLearner = "learner" // 学习者
+	RoleEditor  = "editor"  // 内容编辑
+	RoleAdmin   = "admin"   // 管理员
+)
+
+// IsValidRole 检查角色是否合法
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleLearner, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
-	Username  string         `gorm:"type:varchar(100)" json:"username"`
-	Avatar    string         `gorm:"type:varchar(500)" json:"avatar"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Email           string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Password        string         `gorm:"type:varchar(255);not null" json:"-"`
+	Username        string         `gorm:"type:varchar(100)" json:"username"`
+	Avatar          string         `gorm:"type:varchar(500)" json:"avatar"`
+	Role            string         `gorm:"type:varchar(20);not null;default:'learner';check:role IN ('learner','editor','admin')" json:"role"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName 指定表名
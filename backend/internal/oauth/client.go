@@ -0,0 +1,132 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxResponseBytes 提供方响应体大小上限
+const maxResponseBytes = 1 << 20
+
+// client 授权码流程中各提供方共用的部分
+type client struct {
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	http         *http.Client
+}
+
+// tokenResponse 令牌端点响应；GitHub 出错时同样返回 200，错误放在 error 字段
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// authCodeURL 拼接授权地址
+func (c *client) authCodeURL(authURL, state, verifier string, extra url.Values) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.clientID)
+	q.Set("redirect_uri", c.redirectURL)
+	q.Set("scope", strings.Join(c.scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", codeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	for k, v := range extra {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// exchange 用授权码换取令牌；basicAuth 为 true 时使用 client_secret_basic，否则 client_secret_post
+func (c *client) exchange(ctx context.Context, tokenURL, code, verifier string, basicAuth bool) (*tokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.redirectURL},
+		"code_verifier": {verifier},
+	}
+	if !basicAuth {
+		form.Set("client_id", c.clientID)
+		form.Set("client_secret", c.clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	}
+
+	var token tokenResponse
+	status, err := c.do(req, &token)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("token request: %s %s", token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("token request: unexpected status %d", status)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token request: no access_token in response")
+	}
+	return &token, nil
+}
+
+// getJSON 获取 JSON 资源，accessToken 不为空时带上 Bearer 令牌
+func (c *client) getJSON(ctx context.Context, rawURL, accessToken string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	status, err := c.do(req, dest)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", rawURL, err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", rawURL, status)
+	}
+	return nil
+}
+
+// do 发送请求并解析 JSON 响应体；非 2xx 且无法解析时只返回状态码
+func (c *client) do(req *http.Request, dest interface{}) (int, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, dest); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
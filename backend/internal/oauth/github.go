@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GitHub 默认端点
+const (
+	gitHubAuthURL  = "https://github.com/login/oauth/authorize"
+	gitHubTokenURL = "https://github.com/login/oauth/access_token"
+	gitHubAPIURL   = "https://api.github.com"
+)
+
+// gitHubProvider GitHub OAuth 应用登录；GitHub 不签发 ID Token，账号信息通过 REST API 获取
+type gitHubProvider struct {
+	client
+	name        string
+	displayName string
+	authURL     string
+	tokenURL    string
+	apiURL      string
+}
+
+// newGitHub 创建 GitHub 提供方，端点为空时使用 github.com
+func newGitHub(name, displayName string, c client, authURL, tokenURL, apiURL string) *gitHubProvider {
+	if len(c.scopes) == 0 {
+		c.scopes = []string{"read:user", "user:email"}
+	}
+	if authURL == "" {
+		authURL = gitHubAuthURL
+	}
+	if tokenURL == "" {
+		tokenURL = gitHubTokenURL
+	}
+	if apiURL == "" {
+		apiURL = gitHubAPIURL
+	}
+	return &gitHubProvider{
+		client:      c,
+		name:        name,
+		displayName: displayName,
+		authURL:     authURL,
+		tokenURL:    tokenURL,
+		apiURL:      strings.TrimRight(apiURL, "/"),
+	}
+}
+
+func (p *gitHubProvider) Name() string        { return p.name }
+func (p *gitHubProvider) DisplayName() string { return p.displayName }
+
+// AuthCodeURL 生成授权地址
+func (p *gitHubProvider) AuthCodeURL(_ context.Context, state, _, verifier string) (string, error) {
+	return p.authCodeURL(p.authURL, state, verifier, nil)
+}
+
+// Exchange 换取令牌后读取用户资料；资料中的公开邮箱未必经过验证，因此以 /user/emails 中已验证的主邮箱为准
+func (p *gitHubProvider) Exchange(ctx context.Context, code, verifier, _ string) (*Identity, error) {
+	token, err := p.exchange(ctx, p.tokenURL, code, verifier, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := p.getJSON(ctx, p.apiURL+"/user", token.AccessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("github: user has no id")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(ctx, p.apiURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Name:      user.Name,
+		Login:     user.Login,
+		AvatarURL: user.AvatarURL,
+	}
+	for _, e := range emails {
+		if e.Primary {
+			identity.Email = e.Email
+			identity.EmailVerified = e.Verified
+			break
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,130 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+)
+
+// 提供方类型
+const (
+	TypeGitHub = "github"
+	TypeOIDC   = "oidc"
+)
+
+// providerName 提供方名称出现在登录与回调地址中
+var providerName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// reservedNames 与 /auth/oauth 下的其它路由冲突的名称
+var reservedNames = map[string]bool{"providers": true, "exchange": true}
+
+// Identity 第三方账号信息
+type Identity struct {
+	Subject       string // 提供方内唯一且不变的账号标识
+	Email         string
+	EmailVerified bool // 提供方确认邮箱归该账号所有
+	Name          string
+	Login         string // 提供方的用户名，如 GitHub login
+	AvatarURL     string
+}
+
+// Provider 第三方登录提供方（授权码 + PKCE）
+type Provider interface {
+	Name() string
+	DisplayName() string
+	// AuthCodeURL 生成授权地址，verifier 为 PKCE code_verifier，nonce 只有 OIDC 使用
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	// Exchange 用授权码换取令牌并返回账号信息
+	Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error)
+}
+
+// ProviderInfo 对外展示的提供方信息
+type ProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+// Registry 已启用的提供方
+type Registry struct {
+	providers map[string]Provider
+	infos     []ProviderInfo
+}
+
+// New 根据配置创建已启用的提供方，回调地址为 {callback_base_url}/api/v1/auth/oauth/{name}/callback
+func New(cfg *config.OAuthConfig) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider)}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	for name, pc := range cfg.Providers {
+		if !pc.Enabled {
+			continue
+		}
+		if !providerName.MatchString(name) || reservedNames[name] {
+			return nil, fmt.Errorf("invalid oauth provider name: %q", name)
+		}
+		if pc.ClientID == "" {
+			return nil, fmt.Errorf("oauth provider %s: client_id is required", name)
+		}
+
+		c := client{
+			clientID:     pc.ClientID,
+			clientSecret: pc.ClientSecret,
+			redirectURL:  CallbackURL(cfg.CallbackBaseURL, name),
+			scopes:       pc.Scopes,
+			http:         httpClient,
+		}
+		displayName := pc.DisplayName
+		if displayName == "" {
+			displayName = name
+		}
+
+		var p Provider
+		switch pc.Type {
+		case TypeGitHub:
+			p = newGitHub(name, displayName, c, pc.AuthURL, pc.TokenURL, pc.APIURL)
+		case TypeOIDC:
+			if pc.Issuer == "" {
+				return nil, fmt.Errorf("oauth provider %s: issuer is required", name)
+			}
+			p = newOIDC(name, displayName, c, pc.Issuer)
+		default:
+			return nil, fmt.Errorf("oauth provider %s: unknown type %q", name, pc.Type)
+		}
+		r.providers[name] = p
+		r.infos = append(r.infos, ProviderInfo{Name: name, DisplayName: displayName, Type: pc.Type})
+	}
+
+	sort.Slice(r.infos, func(i, j int) bool { return r.infos[i].Name < r.infos[j].Name })
+	return r, nil
+}
+
+// Get 获取已启用的提供方
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// List 已启用的提供方，按名称排序
+func (r *Registry) List() []ProviderInfo {
+	return append([]ProviderInfo{}, r.infos...)
+}
+
+// CallbackURL 提供方的回调地址
+func CallbackURL(baseURL, name string) string {
+	return strings.TrimRight(baseURL, "/") + "/api/v1/auth/oauth/" + name + "/callback"
+}
+
+// codeChallenge PKCE S256 code_challenge
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
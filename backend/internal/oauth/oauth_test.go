@@ -0,0 +1,349 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockOIDC 本地 OIDC 提供方：授权时记录 PKCE challenge 与 nonce，换取令牌时校验 code_verifier
+type mockOIDC struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu      sync.Mutex
+	pending map[string]mockGrant // 授权码 → 授权请求
+
+	// 测试可以修改签发的 ID Token 与 UserInfo
+	claims   jwt.MapClaims
+	userinfo map[string]interface{}
+}
+
+type mockGrant struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockOIDC{key: key, pending: make(map[string]mockGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                m.server.URL,
+			"authorization_endpoint":                m.server.URL + "/authorize",
+			"token_endpoint":                        m.server.URL + "/token",
+			"userinfo_endpoint":                     m.server.URL + "/userinfo",
+			"jwks_uri":                              m.server.URL + "/jwks",
+			"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "client" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		code := "code-" + q.Get("state")
+		m.mu.Lock()
+		m.pending[code] = mockGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), redirectURI: q.Get("redirect_uri")}
+		m.mu.Unlock()
+
+		redirect, _ := url.Parse(q.Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{"error": "invalid_client"})
+			return
+		}
+		m.mu.Lock()
+		grant, ok := m.pending[r.PostFormValue("code")]
+		delete(m.pending, r.PostFormValue("code"))
+		m.mu.Unlock()
+		if !ok || codeChallenge(r.PostFormValue("code_verifier")) != grant.challenge ||
+			r.PostFormValue("redirect_uri") != grant.redirectURI {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   m.server.URL,
+			"sub":   "user-42",
+			"aud":   "client",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": grant.nonce,
+			"email": "ada@example.com",
+			// 部分提供方把布尔值编码为字符串
+			"email_verified": "true",
+			"name":           "Ada",
+		}
+		for k, v := range m.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		writeJSON(w, map[string]string{"access_token": "access", "token_type": "Bearer", "id_token": idToken})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, m.userinfo)
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// authorize 模拟浏览器访问授权地址，返回回调中的 code 与 state
+func authorize(t *testing.T, authURL string) (code, state string) {
+	t.Helper()
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Expected redirect from authorize endpoint, got %d", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(callback.Path, "/api/v1/auth/oauth/sso/callback") {
+		t.Fatalf("Unexpected redirect_uri: %s", callback)
+	}
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func newTestRegistry(t *testing.T, issuer string) Provider {
+	t.Helper()
+	registry, err := New(&config.OAuthConfig{
+		CallbackBaseURL: "http://localhost:8080/",
+		Providers: map[string]config.OAuthProviderConfig{
+			"sso":    {Enabled: true, Type: TypeOIDC, Issuer: issuer, ClientID: "client", ClientSecret: "secret"},
+			"github": {Enabled: false, Type: TypeGitHub},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if infos := registry.List(); len(infos) != 1 || infos[0].Name != "sso" || infos[0].DisplayName != "sso" {
+		t.Fatalf("Unexpected providers: %+v", infos)
+	}
+	p, ok := registry.Get("sso")
+	if !ok {
+		t.Fatal("Expected sso provider")
+	}
+	return p
+}
+
+func TestOIDCAuthorizationCodeFlow(t *testing.T) {
+	mock := newMockOIDC(t)
+	p := newTestRegistry(t, mock.server.URL)
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code, state := authorize(t, authURL)
+	if state != "state-1" {
+		t.Fatalf("Expected state to round-trip, got %q", state)
+	}
+
+	identity, err := p.Exchange(ctx, code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := Identity{Subject: "user-42", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+	if *identity != want {
+		t.Errorf("Expected %+v, got %+v", want, *identity)
+	}
+}
+
+func TestOIDCRejectsInvalidExchanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+		claims   jwt.MapClaims
+	}{
+		{"wrong PKCE verifier", "other-verifier", "nonce", nil},
+		{"nonce mismatch", "verifier", "other-nonce", nil},
+		{"wrong audience", "verifier", "nonce", jwt.MapClaims{"aud": "someone-else"}},
+		{"wrong issuer", "verifier", "nonce", jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{"expired", "verifier", "nonce", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockOIDC(t)
+			mock.claims = tt.claims
+			p := newTestRegistry(t, mock.server.URL)
+			ctx := context.Background()
+
+			authURL, err := p.AuthCodeURL(ctx, "state", "nonce", "verifier")
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, _ := authorize(t, authURL)
+			if identity, err := p.Exchange(ctx, code, tt.verifier, tt.nonce); err == nil {
+				t.Errorf("Expected exchange to fail, got %+v", identity)
+			}
+		})
+	}
+}
+
+func TestOIDCFallsBackToUserinfo(t *testing.T) {
+	mock := newMockOIDC(t)
+	mock.claims = jwt.MapClaims{"email": "", "email_verified": false}
+	mock.userinfo = map[string]interface{}{"sub": "user-42", "email": "ada@corp.example.com", "email_verified": true, "preferred_username": "ada"}
+	p := newTestRegistry(t, mock.server.URL)
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := authorize(t, authURL)
+	identity, err := p.Exchange(ctx, code, "verifier", "nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Email != "ada@corp.example.com" || !identity.EmailVerified || identity.Login != "ada" {
+		t.Errorf("Expected identity from userinfo, got %+v", identity)
+	}
+
+	// UserInfo 的 sub 与 ID Token 不一致时拒绝
+	mock.userinfo["sub"] = "someone-else"
+	authURL, _ = p.AuthCodeURL(ctx, "state-2", "nonce", "verifier")
+	code, _ = authorize(t, authURL)
+	if _, err := p.Exchange(ctx, code, "verifier", "nonce"); err == nil {
+		t.Error("Expected mismatched userinfo subject to be rejected")
+	}
+}
+
+func TestGitHubUsesVerifiedPrimaryEmail(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("client_secret") != "secret" || r.PostFormValue("code") != "good" || r.PostFormValue("code_verifier") != "verifier" {
+			// GitHub 出错时同样返回 200
+			writeJSON(w, map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		writeJSON(w, map[string]string{"access_token": "gh-token", "token_type": "bearer"})
+	})
+	mux.HandleFunc("/api/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"id": 1001, "login": "octocat", "name": "The Octocat", "email": "public@example.com"})
+	})
+	mux.HandleFunc("/api/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]interface{}{
+			{"email": "public@example.com", "primary": false, "verified": false},
+			{"email": "octo@example.com", "primary": true, "verified": true},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	registry, err := New(&config.OAuthConfig{
+		CallbackBaseURL: "http://localhost:8080",
+		Providers: map[string]config.OAuthProviderConfig{
+			"github": {
+				Enabled: true, Type: TypeGitHub, DisplayName: "GitHub", ClientID: "client", ClientSecret: "secret",
+				AuthURL:  server.URL + "/login/oauth/authorize",
+				TokenURL: server.URL + "/login/oauth/access_token",
+				APIURL:   server.URL + "/api",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := registry.Get("github")
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state", "", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, _ := url.Parse(authURL)
+	if got := q.Query().Get("scope"); got != "read:user user:email" {
+		t.Errorf("Unexpected scope %q", got)
+	}
+	if got := q.Query().Get("redirect_uri"); got != "http://localhost:8080/api/v1/auth/oauth/github/callback" {
+		t.Errorf("Unexpected redirect_uri %q", got)
+	}
+
+	identity, err := p.Exchange(context.Background(), "good", "verifier", "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Subject != "1001" || identity.Email != "octo@example.com" || !identity.EmailVerified || identity.Login != "octocat" {
+		t.Errorf("Unexpected identity %+v", identity)
+	}
+
+	if _, err := p.Exchange(context.Background(), "bad", "verifier", ""); err == nil {
+		t.Error("Expected error response from token endpoint to fail the exchange")
+	}
+}
+
+func TestNewRejectsInvalidProviders(t *testing.T) {
+	tests := map[string]config.OAuthProviderConfig{
+		"Bad Name": {Enabled: true, Type: TypeGitHub, ClientID: "id"},
+		"exchange": {Enabled: true, Type: TypeGitHub, ClientID: "id"},
+		"noclient": {Enabled: true, Type: TypeGitHub},
+		"noissuer": {Enabled: true, Type: TypeOIDC, ClientID: "id"},
+		"unknown":  {Enabled: true, Type: "saml", ClientID: "id"},
+	}
+	for name, pc := range tests {
+		_, err := New(&config.OAuthConfig{Providers: map[string]config.OAuthProviderConfig{name: pc}})
+		if err == nil {
+			t.Errorf("Expected provider %q to be rejected", name)
+		}
+	}
+}
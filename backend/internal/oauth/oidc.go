@@ -0,0 +1,319 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksRefreshInterval 遇到未知 kid 时重新拉取 JWKS 的最小间隔，提供方轮换密钥后可以及时生效
+const jwksRefreshInterval = time.Minute
+
+// idTokenAlgorithms 接受的 ID Token 签名算法
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// discovery OpenID Provider 元数据
+type discovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// basicAuth 令牌端点是否使用 client_secret_basic（规范默认值），只支持 client_secret_post 时改用表单
+func (d *discovery) basicAuth() bool {
+	return len(d.TokenAuthMethods) == 0 ||
+		slices.Contains(d.TokenAuthMethods, "client_secret_basic") ||
+		!slices.Contains(d.TokenAuthMethods, "client_secret_post")
+}
+
+// oidcProvider 通用 OpenID Connect 提供方：端点通过发现文档获取，ID Token 用 JWKS 中的公钥校验
+type oidcProvider struct {
+	client
+	name        string
+	displayName string
+	issuer      string
+
+	mu          sync.Mutex
+	meta        *discovery
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+// newOIDC 创建 OIDC 提供方，发现文档在首次使用时拉取
+func newOIDC(name, displayName string, c client, issuer string) *oidcProvider {
+	if len(c.scopes) == 0 {
+		c.scopes = []string{"openid", "email", "profile"}
+	} else if !slices.Contains(c.scopes, "openid") {
+		c.scopes = append([]string{"openid"}, c.scopes...)
+	}
+	return &oidcProvider{
+		client:      c,
+		name:        name,
+		displayName: displayName,
+		issuer:      strings.TrimRight(issuer, "/"),
+	}
+}
+
+func (p *oidcProvider) Name() string        { return p.name }
+func (p *oidcProvider) DisplayName() string { return p.displayName }
+
+// AuthCodeURL 生成授权地址
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.authCodeURL(meta.AuthorizationEndpoint, state, verifier, url.Values{"nonce": {nonce}})
+}
+
+// Exchange 换取令牌并校验 ID Token；ID Token 中没有邮箱时从 UserInfo 端点补充
+func (p *oidcProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := p.exchange(ctx, meta.TokenEndpoint, code, verifier, meta.basicAuth())
+	if err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oidc: no id_token in token response")
+	}
+
+	claims, err := p.verify(ctx, meta, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Email == "" && meta.UserinfoEndpoint != "" {
+		var info struct {
+			Subject string `json:"sub"`
+			userInfo
+		}
+		if err := p.getJSON(ctx, meta.UserinfoEndpoint, token.AccessToken, &info); err != nil {
+			return nil, err
+		}
+		// UserInfo 的 sub 必须与 ID Token 一致，否则可能是替换攻击
+		if info.Subject != claims.Subject {
+			return nil, errors.New("oidc: userinfo subject does not match id_token")
+		}
+		claims.userInfo = info.userInfo
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		Login:         claims.PreferredUsername,
+		AvatarURL:     claims.Picture,
+	}, nil
+}
+
+// userInfo ID Token 与 UserInfo 中的标准用户信息
+type userInfo struct {
+	Email             string   `json:"email"`
+	EmailVerified     flexBool `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Picture           string   `json:"picture"`
+}
+
+// idTokenClaims ID Token 声明
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	userInfo
+}
+
+// verify 校验 ID Token 的签名、签发者、受众、有效期与 nonce
+func (p *oidcProvider) verify(ctx context.Context, meta *discovery, raw, nonce string) (*idTokenClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims,
+		func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			return p.key(ctx, meta, kid)
+		},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id_token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("oidc: id_token has no subject")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("oidc: id_token nonce mismatch")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.clientID {
+		return nil, errors.New("oidc: id_token azp mismatch")
+	}
+	return &claims, nil
+}
+
+// discover 获取并缓存发现文档，失败时下次重试
+func (p *oidcProvider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	var meta discovery
+	if err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", "", &meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimRight(meta.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", meta.Issuer, p.issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, errors.New("oidc discovery: missing endpoints")
+	}
+	p.meta = &meta
+	return p.meta, nil
+}
+
+// key 按 kid 查找签名公钥，找不到时重新拉取 JWKS（受最小间隔限制）；kid 为空时要求只有一个公钥
+func (p *oidcProvider) key(ctx context.Context, meta *discovery, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key := lookupKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(ctx, meta.JWKSURI, "", &set); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue // 跳过不支持的密钥类型
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key := lookupKey(p.keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey 按 kid 查找公钥
+func lookupKey(keys map[string]interface{}, kid string) interface{} {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+// jwk JSON Web Key 中用到的字段
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 解析 RSA 或 EC 公钥
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// flexBool 兼容部分提供方把 email_verified 编码为字符串
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	switch string(data) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		var v bool
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*b = flexBool(v)
+	}
+	return nil
+}
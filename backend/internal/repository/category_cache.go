@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/models"
+)
+
+// CategoryCacheNS 分类缓存键命名空间，内容变更时按该前缀失效
+const CategoryCacheNS = "categories"
+
+// CategoryReader 分类查询接口
+type CategoryReader interface {
+	List() ([]models.Category, error)
+	GetByID(id uint) (*models.Category, error)
+}
+
+// CachedCategoryRepository 带读穿透缓存的分类仓库
+type CachedCategoryRepository struct {
+	*CategoryRepository
+	cache *cache.ReadThrough
+	ttl   time.Duration
+}
+
+// NewCachedCategoryRepository 创建带缓存的分类仓库
+func NewCachedCategoryRepository(categoryRepo *CategoryRepository, rt *cache.ReadThrough, ttl time.Duration) *CachedCategoryRepository {
+	return &CachedCategoryRepository{
+		CategoryRepository: categoryRepo,
+		cache:              rt,
+		ttl:                ttl,
+	}
+}
+
+// List 获取分类列表（缓存）
+func (r *CachedCategoryRepository) List() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.cache.Fetch(cache.Key(CategoryCacheNS, "list"), r.ttl, &categories, func() (interface{}, error) {
+		return r.CategoryRepository.List()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"database/sql"
+
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/utils"
 
@@ -50,17 +52,143 @@ func (r *CategoryRepository) Update(category *models.Category) error {
 	return r.db.Save(category).Error
 }
 
+// SlugExists 检查 slug 是否已被其他分类使用（包含已删除的分类）
+func (r *CategoryRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Category{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // Delete 删除分类
 func (r *CategoryRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Category{}, id).Error
 }
 
-// GetTree 获取分类树
-func (r *CategoryRepository) GetTree() ([]models.Category, error) {
-	var categories []models.Category
-	err := r.db.Order("sort_order ASC, id ASC").Find(&categories).Error
-	if err != nil {
-		return nil, err
+// Restore 恢复已删除的分类
+func (r *CategoryRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.Category{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
 	}
-	return categories, nil
+	if result.RowsAffected == 0 {
+		return utils.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// CountDependents 统计分类下的子分类和知识点数量
+func (r *CategoryRepository) CountDependents(id uint) (int64, int64, error) {
+	var children, knowledges int64
+	if err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := r.db.Model(&models.KnowledgePoint{}).Where("category_id = ?", id).Count(&knowledges).Error; err != nil {
+		return 0, 0, err
+	}
+	return children, knowledges, nil
+}
+
+// categoryTreeSQL 从根分类（无父分类或父分类已删除）递归展开分类树，按层级路径排序，
+// 附带每个分类直接包含的知识点数量以及 @user 已完成的数量（@user 为 0 时为 0）
+const categoryTreeSQL = `
+WITH RECURSIVE tree AS (
+	SELECT c.id, 0 AS depth, ARRAY[c.sort_order, c.id] AS path
+	FROM categories c
+	WHERE c.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM categories p WHERE p.id = c.parent_id AND p.deleted_at IS NULL)
+	UNION ALL
+	SELECT c.id, t.depth + 1, t.path || ARRAY[c.sort_order, c.id]
+	FROM categories c
+	JOIN tree t ON c.parent_id = t.id
+	WHERE c.deleted_at IS NULL AND t.depth < @max_depth
+),
+counts AS (
+	SELECT k.category_id,
+		count(*) AS knowledge_count,
+		count(lp.id) FILTER (WHERE lp.status = 'completed') AS completed_count
+	FROM knowledge_points k
+	LEFT JOIN learning_progress lp
+		ON lp.knowledge_point_id = k.id AND lp.user_id = @user AND lp.deleted_at IS NULL
+	WHERE k.deleted_at IS NULL
+	GROUP BY k.category_id
+)
+SELECT c.id, c.name, c.slug, c.description, c.parent_id, c.sort_order, t.depth,
+	coalesce(n.knowledge_count, 0) AS knowledge_count,
+	coalesce(n.completed_count, 0) AS completed_count
+FROM tree t
+JOIN categories c ON c.id = t.id
+LEFT JOIN counts n ON n.category_id = t.id
+ORDER BY t.path
+`
+
+// MaxCategoryDepth 分类树的最大层级
+const MaxCategoryDepth = 16
+
+// CategoryTreeRow 分类树中的一行（按先序排列）
+type CategoryTreeRow struct {
+	ID             uint
+	Name           string
+	Slug           string
+	Description    string
+	ParentID       *uint
+	SortOrder      int
+	Depth          int
+	KnowledgeCount int64 // 直接属于该分类的知识点数量
+	CompletedCount int64 // 其中用户已完成的数量
+}
+
+// GetTree 获取按先序排列的分类树，userID 不为 0 时统计该用户已完成的知识点
+func (r *CategoryRepository) GetTree(userID uint) ([]CategoryTreeRow, error) {
+	var rows []CategoryTreeRow
+	err := r.db.Raw(categoryTreeSQL,
+		sql.Named("user", userID),
+		sql.Named("max_depth", MaxCategoryDepth),
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// subtreeSQL 分类及其全部子孙分类的 ID
+const subtreeSQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id, 0 AS depth FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.id, s.depth + 1 FROM categories c
+	JOIN subtree s ON c.parent_id = s.id
+	WHERE c.deleted_at IS NULL AND s.depth < ?
+)
+SELECT id FROM subtree`
+
+// categorySubtree 分类子树 ID 的子查询，用于 category_id IN (?)
+func categorySubtree(db *gorm.DB, categoryID uint) *gorm.DB {
+	return db.Raw(subtreeSQL, categoryID, MaxCategoryDepth)
+}
+
+// CategoryOrder 分类的父分类与排序
+type CategoryOrder struct {
+	ID        uint  `json:"id" binding:"required"`
+	ParentID  *uint `json:"parent_id"`
+	SortOrder int   `json:"sort_order"`
+}
+
+// UpdateOrder 在一个事务中批量更新分类的父分类与排序
+func (r *CategoryRepository) UpdateOrder(items []CategoryOrder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			result := tx.Model(&models.Category{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+				"parent_id":  item.ParentID,
+				"sort_order": item.SortOrder,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return utils.ErrCategoryNotFound
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"eight-gu-learning-platform/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContentSnapshot 当前全部（未删除）内容
+type ContentSnapshot struct {
+	Categories []models.Category
+	Knowledge  []models.KnowledgePoint
+	Relations  []models.KnowledgeRelation
+	Exercises  []models.Exercise
+}
+
+// ContentRepository 内容批量导入导出仓库
+type ContentRepository struct {
+	db *gorm.DB
+}
+
+// NewContentRepository 创建内容仓库
+func NewContentRepository(db *gorm.DB) *ContentRepository {
+	return &ContentRepository{db: db}
+}
+
+// Transaction 在事务中执行，fn 返回错误时回滚
+func (r *ContentRepository) Transaction(fn func(repo *ContentRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&ContentRepository{db: tx})
+	})
+}
+
+// Snapshot 读取全部未删除的内容
+func (r *ContentRepository) Snapshot() (*ContentSnapshot, error) {
+	var snapshot ContentSnapshot
+	if err := r.db.Order("id ASC").Find(&snapshot.Categories).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Order("id ASC").Find(&snapshot.Knowledge).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Order("id ASC").Find(&snapshot.Relations).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Order("id ASC").Find(&snapshot.Exercises).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// CategoryBySlug 根据 slug 获取分类（包含已删除），不存在时返回 nil
+func (r *ContentRepository) CategoryBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.findBySlug(&category, slug); err != nil || category.ID == 0 {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// KnowledgeBySlug 根据 slug 获取知识点（包含已删除），不存在时返回 nil
+func (r *ContentRepository) KnowledgeBySlug(slug string) (*models.KnowledgePoint, error) {
+	var knowledge models.KnowledgePoint
+	if err := r.findBySlug(&knowledge, slug); err != nil || knowledge.ID == 0 {
+		return nil, err
+	}
+	return &knowledge, nil
+}
+
+// ExerciseBySlug 根据 slug 获取练习题（包含已删除），不存在时返回 nil
+func (r *ContentRepository) ExerciseBySlug(slug string) (*models.Exercise, error) {
+	var exercise models.Exercise
+	if err := r.findBySlug(&exercise, slug); err != nil || exercise.ID == 0 {
+		return nil, err
+	}
+	return &exercise, nil
+}
+
+// FindRelation 查找知识关联（包含已删除），不存在时返回 nil
+func (r *ContentRepository) FindRelation(fromPointID, toPointID uint, relationType string) (*models.KnowledgeRelation, error) {
+	var relation models.KnowledgeRelation
+	err := r.db.Unscoped().
+		Where("from_point_id = ? AND to_point_id = ? AND relation_type = ?", fromPointID, toPointID, relationType).
+		Limit(1).Find(&relation).Error
+	if err != nil || relation.ID == 0 {
+		return nil, err
+	}
+	return &relation, nil
+}
+
+// Save 创建或更新记录（不级联保存关联对象）；DeletedAt 为空时同时恢复已删除的记录
+func (r *ContentRepository) Save(value interface{}) error {
+	return r.db.Unscoped().Omit(clause.Associations).Save(value).Error
+}
+
+func (r *ContentRepository) findBySlug(dest interface{}, slug string) error {
+	return r.db.Unscoped().Where("slug = ?", slug).Limit(1).Find(dest).Error
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DashboardRepository 学习看板统计仓库，聚合均在 SQL 中完成
+type DashboardRepository struct {
+	db *gorm.DB
+}
+
+// NewDashboardRepository 创建学习看板统计仓库
+func NewDashboardRepository(db *gorm.DB) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// dailyActivitySQL 按天统计练习、复习与学习时长（按会话开始日期），generate_series 补齐没有活动的日期
+const dailyActivitySQL = `
+WITH days AS (
+	SELECT CAST(d AS date) AS day
+	FROM generate_series(CAST(@from AS date), CAST(@to AS date), interval '1 day') AS d
+),
+records AS (
+	SELECT CAST(created_at AS date) AS day,
+		count(*) AS exercises,
+		count(*) FILTER (WHERE is_correct) AS correct,
+		sum(score) AS score
+	FROM exercise_records
+	WHERE user_id = @user AND deleted_at IS NULL
+		AND created_at >= CAST(@from AS date) AND created_at < CAST(@to AS date) + 1
+	GROUP BY 1
+),
+reviews AS (
+	SELECT CAST(updated_at AS date) AS day, count(*) AS reviewed
+	FROM learning_progress
+	WHERE user_id = @user AND deleted_at IS NULL
+		AND updated_at >= CAST(@from AS date) AND updated_at < CAST(@to AS date) + 1
+	GROUP BY 1
+),
+studies AS (
+	SELECT CAST(started_at AS date) AS day, sum(duration_seconds) AS study_seconds
+	FROM study_sessions
+	WHERE user_id = @user AND deleted_at IS NULL
+		AND started_at >= CAST(@from AS date) AND started_at < CAST(@to AS date) + 1
+	GROUP BY 1
+)
+SELECT to_char(days.day, 'YYYY-MM-DD') AS date,
+	coalesce(r.exercises, 0) AS exercises,
+	coalesce(r.correct, 0) AS correct,
+	coalesce(r.score, 0) AS score,
+	coalesce(v.reviewed, 0) AS reviewed,
+	coalesce(st.study_seconds, 0) AS study_seconds
+FROM days
+LEFT JOIN records r ON r.day = days.day
+LEFT JOIN reviews v ON v.day = days.day
+LEFT JOIN studies st ON st.day = days.day
+ORDER BY days.day
+`
+
+// DailyActivity 每日学习活动
+type DailyActivity struct {
+	Date         string  `json:"date"`
+	Exercises    int64   `json:"exercises"` // 作答次数
+	Correct      int64   `json:"correct"`
+	Score        float64 `json:"score"`         // 得分之和（部分得分）
+	Reviewed     int64   `json:"reviewed"`      // 当天最近一次更新的学习进度数量
+	StudySeconds int64   `json:"study_seconds"` // 当天开始的学习会话时长
+}
+
+// GetDailyActivity 获取 [from, to] 每天的学习活动
+func (r *DashboardRepository) GetDailyActivity(userID uint, from, to time.Time) ([]DailyActivity, error) {
+	var activity []DailyActivity
+	err := r.db.Raw(dailyActivitySQL,
+		sql.Named("user", userID),
+		sql.Named("from", from.Format(time.DateOnly)),
+		sql.Named("to", to.Format(time.DateOnly)),
+	).Scan(&activity).Error
+	return activity, err
+}
+
+// activeRunsSQL 有练习、进度更新或学习会话的日期按连续区间分组（gaps and islands）
+const activeRunsSQL = `
+WITH days AS (
+	SELECT CAST(created_at AS date) AS day FROM exercise_records WHERE user_id = @user AND deleted_at IS NULL
+	UNION
+	SELECT CAST(updated_at AS date) FROM learning_progress WHERE user_id = @user AND deleted_at IS NULL
+	UNION
+	SELECT CAST(started_at AS date) FROM study_sessions WHERE user_id = @user AND deleted_at IS NULL
+),
+islands AS (
+	SELECT day, day - CAST(row_number() OVER (ORDER BY day) AS integer) AS grp FROM days
+)
+SELECT count(*) AS days, to_char(max(day), 'YYYY-MM-DD') AS last_day
+FROM islands
+GROUP BY grp
+ORDER BY max(day)
+`
+
+// ActiveRun 连续学习的一段日期
+type ActiveRun struct {
+	Days    int
+	LastDay string
+}
+
+// GetActiveRuns 获取用户连续学习的区间，按结束日期排序
+func (r *DashboardRepository) GetActiveRuns(userID uint) ([]ActiveRun, error) {
+	var runs []ActiveRun
+	err := r.db.Raw(activeRunsSQL, sql.Named("user", userID)).Scan(&runs).Error
+	return runs, err
+}
+
+// accuracySQL 按分类和难度统计作答正确率（GROUPING SETS 一次聚合两个维度）
+const accuracySQL = `
+SELECT CASE WHEN GROUPING(k.category_id) = 0 THEN 'category' ELSE 'difficulty' END AS dimension,
+	CASE WHEN GROUPING(k.category_id) = 0 THEN CAST(k.category_id AS text) ELSE e.difficulty END AS key,
+	CASE WHEN GROUPING(k.category_id) = 0 THEN max(c.name) ELSE e.difficulty END AS label,
+	count(*) AS attempts,
+	count(*) FILTER (WHERE r.is_correct) AS correct,
+	avg(r.score) AS avg_score
+FROM exercise_records r
+JOIN exercises e ON e.id = r.exercise_id
+JOIN knowledge_points k ON k.id = e.knowledge_point_id
+LEFT JOIN categories c ON c.id = k.category_id
+WHERE r.user_id = @user AND r.deleted_at IS NULL
+GROUP BY GROUPING SETS ((k.category_id), (e.difficulty))
+ORDER BY dimension, attempts DESC, key
+`
+
+// 正确率统计维度
+const (
+	DimensionCategory   = "category"
+	DimensionDifficulty = "difficulty"
+)
+
+// AccuracyStat 某个维度的作答正确率
+type AccuracyStat struct {
+	Dimension string  `json:"-"`
+	Key       string  `json:"key"` // 分类 ID 或难度
+	Label     string  `json:"label"`
+	Attempts  int64   `json:"attempts"`
+	Correct   int64   `json:"correct"`
+	AvgScore  float64 `json:"avg_score"` // 平均得分（0~1）
+}
+
+// GetAccuracy 获取按分类和难度的作答正确率
+func (r *DashboardRepository) GetAccuracy(userID uint) ([]AccuracyStat, error) {
+	var stats []AccuracyStat
+	err := r.db.Raw(accuracySQL, sql.Named("user", userID)).Scan(&stats).Error
+	return stats, err
+}
+
+// weakestSQL 掌握度最低、正确率最低的已学习知识点
+const weakestSQL = `
+WITH accuracy AS (
+	SELECT e.knowledge_point_id, count(*) AS attempts, avg(r.score) AS accuracy
+	FROM exercise_records r
+	JOIN exercises e ON e.id = r.exercise_id
+	WHERE r.user_id = @user AND r.deleted_at IS NULL
+	GROUP BY e.knowledge_point_id
+)
+SELECT k.id AS knowledge_point_id, k.title, k.category_id, k.frequency,
+	lp.mastery_level, lp.lapses,
+	coalesce(a.attempts, 0) AS attempts, a.accuracy
+FROM learning_progress lp
+JOIN knowledge_points k ON k.id = lp.knowledge_point_id AND k.deleted_at IS NULL
+LEFT JOIN accuracy a ON a.knowledge_point_id = k.id
+WHERE lp.user_id = @user AND lp.deleted_at IS NULL AND lp.status <> 'not_started'
+ORDER BY lp.mastery_level, coalesce(a.accuracy, 1), lp.lapses DESC, k.id
+LIMIT @limit
+`
+
+// WeakPoint 薄弱知识点
+type WeakPoint struct {
+	KnowledgePointID uint     `json:"knowledge_point_id"`
+	Title            string   `json:"title"`
+	CategoryID       uint     `json:"category_id"`
+	Frequency        string   `json:"frequency"`
+	MasteryLevel     int      `json:"mastery_level"`
+	Lapses           int      `json:"lapses"`
+	Attempts         int64    `json:"attempts"`
+	Accuracy         *float64 `json:"accuracy"` // 平均得分，没有作答时为 null
+}
+
+// GetWeakestPoints 获取最薄弱的已学习知识点
+func (r *DashboardRepository) GetWeakestPoints(userID uint, limit int) ([]WeakPoint, error) {
+	var points []WeakPoint
+	err := r.db.Raw(weakestSQL, sql.Named("user", userID), sql.Named("limit", limit)).Scan(&points).Error
+	return points, err
+}
+
+// coverageSQL 按面试频率统计知识点的学习覆盖情况
+const coverageSQL = `
+SELECT k.frequency,
+	count(*) AS total,
+	count(lp.id) FILTER (WHERE lp.status <> 'not_started') AS started,
+	count(lp.id) FILTER (WHERE lp.status = 'completed' OR lp.mastery_level >= @mastered) AS mastered
+FROM knowledge_points k
+LEFT JOIN learning_progress lp
+	ON lp.knowledge_point_id = k.id AND lp.user_id = @user AND lp.deleted_at IS NULL
+WHERE k.deleted_at IS NULL
+GROUP BY k.frequency
+ORDER BY CASE k.frequency WHEN 'high' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END
+`
+
+// FrequencyCoverage 某个面试频率的知识点覆盖情况
+type FrequencyCoverage struct {
+	Frequency string `json:"frequency"`
+	Total     int64  `json:"total"`
+	Started   int64  `json:"started"`  // 已开始学习
+	Mastered  int64  `json:"mastered"` // 已掌握
+}
+
+// GetCoverage 获取按面试频率的知识点覆盖情况，掌握度达到 masteredLevel 视为已掌握
+func (r *DashboardRepository) GetCoverage(userID uint, masteredLevel int) ([]FrequencyCoverage, error) {
+	var coverage []FrequencyCoverage
+	err := r.db.Raw(coverageSQL, sql.Named("user", userID), sql.Named("mastered", masteredLevel)).Scan(&coverage).Error
+	return coverage, err
+}
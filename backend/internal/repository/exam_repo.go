@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ExamRepository 考试仓库
+type ExamRepository struct {
+	db *gorm.DB
+}
+
+// NewExamRepository 创建考试仓库
+func NewExamRepository(db *gorm.DB) *ExamRepository {
+	return &ExamRepository{db: db}
+}
+
+// Create 创建考试（同时创建答题记录）
+func (r *ExamRepository) Create(session *models.ExamSession) error {
+	return r.db.Omit("Answers.Exercise").Create(session).Error
+}
+
+// GetByID 根据 ID 获取考试，包含按题号排序的答题记录及题目
+func (r *ExamRepository) GetByID(id uint) (*models.ExamSession, error) {
+	var session models.ExamSession
+	err := r.db.
+		Preload("Answers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Answers.Exercise").
+		First(&session, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.ErrExamNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListByUser 获取用户的考试列表
+func (r *ExamRepository) ListByUser(userID uint, offset, limit int) ([]models.ExamSession, int64, error) {
+	var sessions []models.ExamSession
+	var total int64
+
+	query := r.db.Model(&models.ExamSession{}).Where("user_id = ?", userID)
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	err := query.Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&sessions).Error
+
+	return sessions, total, err
+}
+
+// PickExercises 按条件随机抽取练习题（代码题需要沙箱判分，不参与组卷）
+func (r *ExamRepository) PickExercises(categoryID uint, difficulty string, count int) ([]models.Exercise, error) {
+	var exercises []models.Exercise
+
+	query := r.db.Model(&models.Exercise{}).Where("type <> ?", models.ExerciseTypeCode)
+	if categoryID > 0 {
+		query = query.Where("knowledge_point_id IN (?)",
+			r.db.Model(&models.KnowledgePoint{}).Select("id").Where("category_id = ?", categoryID))
+	}
+	if difficulty != "" {
+		query = query.Where("difficulty = ?", difficulty)
+	}
+
+	err := query.Order("random()").Limit(count).Find(&exercises).Error
+	return exercises, err
+}
+
+// SaveAnswer 保存答题记录
+func (r *ExamRepository) SaveAnswer(answer *models.ExamAnswer) error {
+	return r.db.Model(answer).
+		Select("user_answer", "answered_at").
+		Updates(answer).Error
+}
+
+// Finalize 保存交卷结果（考试状态、得分及每题判分），并在同一事务中写入练习记录
+func (r *ExamRepository) Finalize(session *models.ExamSession, records []models.ExerciseRecord) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(session).
+			Where("status = ?", models.ExamStatusInProgress).
+			Select("status", "submitted_at", "correct_count", "score").
+			Updates(session)
+		if result.Error != nil {
+			return result.Error
+		}
+		// 已被并发请求交卷
+		if result.RowsAffected == 0 {
+			return utils.ErrExamFinished
+		}
+
+		for i := range session.Answers {
+			answer := &session.Answers[i]
+			if err := tx.Model(answer).Select("is_correct", "score").Updates(answer).Error; err != nil {
+				return err
+			}
+		}
+		if len(records) > 0 {
+			return tx.Create(&records).Error
+		}
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// 交卷后重新读取考试，每题的部分得分应已保存（报告与超时自动交卷都从数据库读取）
+func TestExamFinalizePersistsAnswerScores(t *testing.T) {
+	db := testDB(t)
+
+	user := &models.User{Email: uniqueSlug("exam") + "@example.com", Password: "x", Username: "exam", Role: models.RoleLearner}
+	category := &models.Category{Name: "exam", Slug: uniqueSlug("exam-category")}
+	for _, v := range []interface{}{user, category} {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	point := &models.KnowledgePoint{Title: "exam", Slug: uniqueSlug("exam-point"), CategoryID: category.ID}
+	if err := db.Create(point).Error; err != nil {
+		t.Fatal(err)
+	}
+	exercise := &models.Exercise{
+		Slug:             uniqueSlug("exam-exercise"),
+		KnowledgePointID: point.ID,
+		Question:         "选出全部正确项",
+		Options:          `["a","b","c"]`,
+		Answer:           `["a","b"]`,
+		Type:             models.ExerciseTypeMultipleChoice,
+		Difficulty:       "easy",
+	}
+	if err := db.Create(exercise).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewExamRepository(db)
+	now := time.Now()
+	session := &models.ExamSession{
+		UserID:          user.ID,
+		QuestionCount:   1,
+		DurationSeconds: 600,
+		Status:          models.ExamStatusInProgress,
+		StartedAt:       now,
+		Deadline:        now.Add(10 * time.Minute),
+		Answers:         []models.ExamAnswer{{ExerciseID: exercise.ID, Position: 1, UserAnswer: `["a"]`}},
+	}
+	if err := repo.Create(session); err != nil {
+		t.Fatal(err)
+	}
+
+	session.Status = models.ExamStatusSubmitted
+	session.SubmittedAt = &now
+	session.Score = 50
+	session.Answers[0].Score = 0.5
+	record := models.ExerciseRecord{UserID: user.ID, ExerciseID: exercise.ID, UserAnswer: `["a"]`, Score: 0.5}
+	if err := repo.Finalize(session, []models.ExerciseRecord{record}); err != nil {
+		t.Fatal(err)
+	}
+	// 重复交卷不再写入练习记录
+	if err := repo.Finalize(session, []models.ExerciseRecord{record}); err != utils.ErrExamFinished {
+		t.Errorf("Expected ErrExamFinished on second finalize, got %v", err)
+	}
+
+	reloaded, err := repo.GetByID(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Status != models.ExamStatusSubmitted || reloaded.Score != 50 {
+		t.Errorf("Expected submitted session with score 50, got %s %v", reloaded.Status, reloaded.Score)
+	}
+	if len(reloaded.Answers) != 1 || reloaded.Answers[0].Score != 0.5 {
+		t.Errorf("Expected answer score 0.5 after reload, got %+v", reloaded.Answers)
+	}
+	var records int64
+	if err := db.Model(&models.ExerciseRecord{}).Where("user_id = ?", user.ID).Count(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if records != 1 {
+		t.Errorf("Expected 1 exercise record, got %d", records)
+	}
+}
@@ -69,7 +69,30 @@ func (r *ExerciseRepository) Update(exercise *models.Exercise) error {
 	return r.db.Save(exercise).Error
 }
 
+// SlugExists 检查 slug 是否已被其他练习题使用（包含已删除的练习题）
+func (r *ExerciseRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Exercise{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // Delete 删除练习题
 func (r *ExerciseRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Exercise{}, id).Error
 }
+
+// Restore 恢复已删除的练习题
+func (r *ExerciseRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.Exercise{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrExerciseNotFound
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// IdentityRepository 第三方账号绑定仓库
+type IdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository 创建第三方账号绑定仓库
+func NewIdentityRepository(db *gorm.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// GetBySubject 根据提供方与外部账号标识获取绑定
+func (r *IdentityRepository) GetBySubject(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// ListByUser 获取用户绑定的第三方账号
+func (r *IdentityRepository) ListByUser(userID uint) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("provider ASC").Find(&identities).Error
+	return identities, err
+}
+
+// Create 绑定第三方账号；外部账号已绑定其他用户或用户已绑定该提供方时返回 ErrIdentityInUse
+func (r *IdentityRepository) Create(identity *models.UserIdentity) error {
+	return translateIdentityError(r.db.Create(identity).Error)
+}
+
+// CreateWithUser 在同一事务中创建用户并绑定第三方账号
+func (r *IdentityRepository) CreateWithUser(user *models.User, identity *models.UserIdentity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return utils.ErrEmailAlreadyUsed
+			}
+			return err
+		}
+		identity.UserID = user.ID
+		return translateIdentityError(tx.Create(identity).Error)
+	})
+}
+
+// Touch 记录登录时间并同步外部账号的邮箱与用户名
+func (r *IdentityRepository) Touch(id uint, email, login string, at time.Time) error {
+	return r.db.Model(&models.UserIdentity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"login":         login,
+		"last_login_at": at,
+	}).Error
+}
+
+// Delete 解绑用户在提供方下的第三方账号
+func (r *IdentityRepository) Delete(userID uint, provider string) error {
+	result := r.db.Where("user_id = ? AND provider = ?", userID, provider).Delete(&models.UserIdentity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrIdentityNotFound
+	}
+	return nil
+}
+
+// translateIdentityError 唯一约束冲突转换为 ErrIdentityInUse
+func translateIdentityError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return utils.ErrIdentityInUse
+	}
+	return err
+}
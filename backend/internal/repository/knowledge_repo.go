@@ -42,9 +42,9 @@ func (r *KnowledgeRepository) List(offset, limit int, categoryID uint, difficult
 
 	query := r.db.Model(&models.KnowledgePoint{})
 
-	// 筛选条件
+	// 筛选条件，分类包含其子孙分类
 	if categoryID > 0 {
-		query = query.Where("category_id = ?", categoryID)
+		query = query.Where("category_id IN (?)", categorySubtree(r.db, categoryID))
 	}
 	if difficulty != "" {
 		query = query.Where("difficulty = ?", difficulty)
@@ -53,7 +53,9 @@ func (r *KnowledgeRepository) List(offset, limit int, categoryID uint, difficult
 		query = query.Where("frequency = ?", frequency)
 	}
 	if search != "" {
-		query = query.Where("title LIKE ? OR description LIKE ?", "%"+search+"%", "%"+search+"%")
+		like := "%" + escapeLike(search) + "%"
+		query = query.Where("search_vector @@ websearch_to_tsquery('"+SearchConfig+"', ?) OR title ILIKE ? OR description ILIKE ?",
+			search, like, like)
 	}
 
 	// 统计总数
@@ -71,24 +73,66 @@ func (r *KnowledgeRepository) List(offset, limit int, categoryID uint, difficult
 	return knowledges, total, err
 }
 
+// ListByIDs 根据 ID 批量获取知识点
+func (r *KnowledgeRepository) ListByIDs(ids []uint) ([]models.KnowledgePoint, error) {
+	var knowledges []models.KnowledgePoint
+	if len(ids) == 0 {
+		return knowledges, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&knowledges).Error
+	return knowledges, err
+}
+
+// ListBrief 获取全部知识点的概要（不含正文），用于学习路径推荐
+func (r *KnowledgeRepository) ListBrief() ([]models.KnowledgePoint, error) {
+	var knowledges []models.KnowledgePoint
+	err := r.db.Select("id", "title", "slug", "category_id", "difficulty", "frequency").
+		Order("id").
+		Find(&knowledges).Error
+	return knowledges, err
+}
+
 // Update 更新知识点
 func (r *KnowledgeRepository) Update(knowledge *models.KnowledgePoint) error {
 	return r.db.Save(knowledge).Error
 }
 
+// SlugExists 检查 slug 是否已被其他知识点使用（包含已删除的知识点）
+func (r *KnowledgeRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.KnowledgePoint{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // Delete 删除知识点
 func (r *KnowledgeRepository) Delete(id uint) error {
 	return r.db.Delete(&models.KnowledgePoint{}, id).Error
 }
 
-// GetGraphData 获取知识图谱数据
+// Restore 恢复已删除的知识点
+func (r *KnowledgeRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.KnowledgePoint{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrKnowledgeNotFound
+	}
+	return nil
+}
+
+// GetGraphData 获取知识图谱数据（分类包含其子孙分类）：只返回两端都在节点集合中的关系
 func (r *KnowledgeRepository) GetGraphData(categoryID uint) ([]models.KnowledgePoint, []models.KnowledgeRelation, error) {
 	var knowledges []models.KnowledgePoint
 	var relations []models.KnowledgeRelation
 
 	query := r.db.Model(&models.KnowledgePoint{})
 	if categoryID > 0 {
-		query = query.Where("category_id = ?", categoryID)
+		query = query.Where("category_id IN (?)", categorySubtree(r.db, categoryID))
 	}
 
 	if err := query.Preload("Category").Find(&knowledges).Error; err != nil {
@@ -96,7 +140,14 @@ func (r *KnowledgeRepository) GetGraphData(categoryID uint) ([]models.KnowledgeP
 	}
 
 	// 获取关联关系
-	if err := r.db.Find(&relations).Error; err != nil {
+	nodes := r.db.Model(&models.KnowledgePoint{}).Select("id")
+	if categoryID > 0 {
+		nodes = nodes.Where("category_id IN (?)", categorySubtree(r.db, categoryID))
+	}
+	err := r.db.Where("from_point_id IN (?) AND to_point_id IN (?)", nodes, nodes).
+		Order("id").
+		Find(&relations).Error
+	if err != nil {
 		return nil, nil, err
 	}
 
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"eight-gu-learning-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LoginAuditRepository 登录审计仓库
+type LoginAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAuditRepository 创建登录审计仓库
+func NewLoginAuditRepository(db *gorm.DB) *LoginAuditRepository {
+	return &LoginAuditRepository{db: db}
+}
+
+// Create 记录一次登录
+func (r *LoginAuditRepository) Create(audit *models.LoginAudit) error {
+	return r.db.Create(audit).Error
+}
+
+// ListByUser 获取用户的登录记录，最新的在前
+func (r *LoginAuditRepository) ListByUser(userID uint, offset, limit int) ([]models.LoginAudit, int64, error) {
+	var audits []models.LoginAudit
+	var total int64
+
+	query := r.db.Model(&models.LoginAudit{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&audits).Error
+	return audits, total, err
+}
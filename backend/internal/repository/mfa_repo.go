@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MFARepository 两步验证仓库
+type MFARepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository 创建两步验证仓库
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// Get 获取用户的两步验证设置，没有时返回 ErrMFANotEnabled
+func (r *MFARepository) Get(userID uint) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	err := r.db.Where("user_id = ?", userID).First(&mfa).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// SavePending 保存待确认的密钥，覆盖之前未完成的绑定；已启用时返回 ErrMFAAlreadyEnabled
+func (r *MFARepository) SavePending(userID uint, secret string) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secret":         secret,
+			"last_used_step": 0,
+			"updated_at":     time.Now(),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_mfa.enabled_at IS NULL"}}},
+	}).Create(&models.UserMFA{UserID: userID, Secret: secret})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// UseStep 记录通过验证的时间步；该时间步或更晚的已使用过时返回 false，防止验证码重放
+func (r *MFARepository) UseStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Enable 启用两步验证并生成新的恢复码
+func (r *MFARepository) Enable(userID uint, step int64, at time.Time, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserMFA{}).
+			Where("user_id = ? AND enabled_at IS NULL AND last_used_step < ?", userID, step).
+			Updates(map[string]interface{}{"enabled_at": at, "last_used_step": step})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrMFACodeInvalid
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// ReplaceRecoveryCodes 作废旧的恢复码并保存新的
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode 使用一个恢复码，不存在或已使用时返回 false
+func (r *MFARepository) UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error) {
+	result := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountRecoveryCodes 剩余可用的恢复码数量
+func (r *MFARepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Delete 关闭两步验证，删除密钥与恢复码
+func (r *MFARepository) Delete(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+}
+
+// replaceRecoveryCodes 在事务中替换用户的恢复码
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.MFARecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.MFARecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
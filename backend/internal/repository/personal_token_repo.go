@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// PersonalTokenRepository 个人访问令牌仓库
+type PersonalTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalTokenRepository 创建个人访问令牌仓库
+func NewPersonalTokenRepository(db *gorm.DB) *PersonalTokenRepository {
+	return &PersonalTokenRepository{db: db}
+}
+
+// Create 保存令牌
+func (r *PersonalTokenRepository) Create(token *models.PersonalAccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash 根据令牌哈希获取令牌
+func (r *PersonalTokenRepository) GetByHash(hash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrPersonalTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUser 获取用户的令牌，最新创建的在前
+func (r *PersonalTokenRepository) ListByUser(userID uint) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// CountByUser 用户的令牌数量
+func (r *PersonalTokenRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PersonalAccessToken{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Touch 记录最近使用时间
+func (r *PersonalTokenRepository) Touch(id uint, at time.Time) error {
+	return r.db.Model(&models.PersonalAccessToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+// Delete 吊销用户的令牌
+func (r *PersonalTokenRepository) Delete(userID, id uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrPersonalTokenNotFound
+	}
+	return nil
+}
+
+// DeleteByUser 吊销用户的全部令牌
+func (r *PersonalTokenRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.PersonalAccessToken{}).Error
+}
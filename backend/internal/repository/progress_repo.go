@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/utils"
 
@@ -70,51 +72,73 @@ func (r *ProgressRepository) GetByUser(userID uint, knowledgeID uint, categoryID
 	return progresses, err
 }
 
+// ListBrief 获取用户全部学习进度的状态与掌握度（不预加载知识点）
+func (r *ProgressRepository) ListBrief(userID uint) ([]models.LearningProgress, error) {
+	var progresses []models.LearningProgress
+	err := r.db.Select("knowledge_point_id", "status", "mastery_level").
+		Where("user_id = ?", userID).
+		Find(&progresses).Error
+	return progresses, err
+}
+
 // Update 更新学习进度
 func (r *ProgressRepository) Update(progress *models.LearningProgress) error {
 	return r.db.Save(progress).Error
 }
 
+// GetDue 获取用户到期待复习的进度，按到期时间和掌握度排序
+func (r *ProgressRepository) GetDue(userID uint, now time.Time, offset, limit int) ([]models.LearningProgress, int64, error) {
+	var progresses []models.LearningProgress
+	var total int64
+
+	query := r.db.Model(&models.LearningProgress{}).
+		Where("user_id = ? AND due_at IS NOT NULL AND due_at <= ?", userID, now)
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	err := query.Preload("KnowledgePoint.Category").
+		Order("due_at ASC, mastery_level ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&progresses).Error
+
+	return progresses, total, err
+}
+
 // Delete 删除学习进度
 func (r *ProgressRepository) Delete(id uint) error {
 	return r.db.Delete(&models.LearningProgress{}, id).Error
 }
 
-// GetStats 获取用户学习统计
-func (r *ProgressRepository) GetStats(userID uint) (map[string]interface{}, error) {
-	var stats struct {
-		Total       int64 `json:"total"`
-		Completed   int64 `json:"completed"`
-		InProgress  int64 `json:"in_progress"`
-		NotStarted  int64 `json:"not_started"`
-		MasteryAvg  float64 `json:"mastery_avg"`
-	}
+// LearningStats 用户学习统计
+type LearningStats struct {
+	Total        int64   `json:"total"`
+	Completed    int64   `json:"completed"`
+	InProgress   int64   `json:"in_progress"`
+	NotStarted   int64   `json:"not_started"`
+	MasteryAvg   float64 `json:"mastery_avg"`
+	StudySeconds int64   `json:"study_seconds"` // 学习会话累计时长
+}
 
-	// 总数
-	r.db.Model(&models.LearningProgress{}).Where("user_id = ?", userID).Count(&stats.Total)
-
-	// 各状态数量
-	r.db.Model(&models.LearningProgress{}).
-		Where("user_id = ? AND status = ?", userID, "completed").
-		Count(&stats.Completed)
-	r.db.Model(&models.LearningProgress{}).
-		Where("user_id = ? AND status = ?", userID, "in_progress").
-		Count(&stats.InProgress)
-	r.db.Model(&models.LearningProgress{}).
-		Where("user_id = ? AND status = ?", userID, "not_started").
-		Count(&stats.NotStarted)
-
-	// 平均掌握度
-	r.db.Model(&models.LearningProgress{}).
+// GetStats 获取用户学习统计（一次聚合查询）
+func (r *ProgressRepository) GetStats(userID uint) (*LearningStats, error) {
+	var stats LearningStats
+	err := r.db.Model(&models.LearningProgress{}).
+		Select(`count(*) AS total,
+			count(*) FILTER (WHERE status = 'completed') AS completed,
+			count(*) FILTER (WHERE status = 'in_progress') AS in_progress,
+			count(*) FILTER (WHERE status = 'not_started') AS not_started,
+			coalesce(avg(mastery_level), 0) AS mastery_avg,
+			(SELECT coalesce(sum(duration_seconds), 0) FROM study_sessions
+				WHERE user_id = ? AND deleted_at IS NULL) AS study_seconds`, userID).
 		Where("user_id = ?", userID).
-		Select("AVG(mastery_level)").
-		Scan(&stats.MasteryAvg)
-
-	return map[string]interface{}{
-		"total":        stats.Total,
-		"completed":    stats.Completed,
-		"in_progress":  stats.InProgress,
-		"not_started":  stats.NotStarted,
-		"mastery_avg":  stats.MasteryAvg,
-	}, nil
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
 }
@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"database/sql"
+	"time"
+
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // RecordRepository 练习记录仓库
@@ -65,27 +69,161 @@ func (r *RecordRepository) List(offset, limit int, userID, exerciseID uint) ([]m
 	return records, total, err
 }
 
-// GetWrongList 获取错题列表
-func (r *RecordRepository) GetWrongList(userID uint, offset, limit int) ([]models.ExerciseRecord, int64, error) {
-	var records []models.ExerciseRecord
-	var total int64
+// wrongStatsSQL 按练习题汇总用户的错题（已删除的练习题不计入）；
+// 标记之后再次答错的错题重新计为待巩固
+const wrongStatsSQL = `
+WITH stats AS (
+	SELECT r.exercise_id,
+		count(*) FILTER (WHERE NOT r.is_correct) AS wrong_count,
+		count(*) AS attempt_count,
+		max(r.created_at) AS last_attempt_at,
+		max(r.created_at) FILTER (WHERE NOT r.is_correct) AS last_wrong_at,
+		max(r.created_at) FILTER (WHERE r.is_correct) AS last_correct_at,
+		(array_agg(r.user_answer::text ORDER BY r.created_at DESC, r.id DESC) FILTER (WHERE NOT r.is_correct))[1] AS last_wrong_answer
+	FROM exercise_records r
+	JOIN exercises e ON e.id = r.exercise_id AND e.deleted_at IS NULL
+	WHERE r.user_id = @user_id AND r.deleted_at IS NULL
+	GROUP BY r.exercise_id
+	HAVING count(*) FILTER (WHERE NOT r.is_correct) > 0
+),
+wrong AS (
+	SELECT s.*, e.knowledge_point_id,
+		coalesce(s.last_correct_at > s.last_wrong_at, false) AS corrected,
+		CASE WHEN m.marked_at >= s.last_wrong_at THEN m.status ELSE 'outstanding' END AS status
+	FROM stats s
+	JOIN exercises e ON e.id = s.exercise_id
+	LEFT JOIN wrong_exercise_marks m ON m.user_id = @user_id AND m.exercise_id = s.exercise_id
+)
+`
+
+// WrongExerciseStat 按练习题汇总的错题
+type WrongExerciseStat struct {
+	ExerciseID       uint
+	KnowledgePointID uint
+	WrongCount       int
+	AttemptCount     int
+	LastAttemptAt    time.Time
+	LastWrongAt      time.Time
+	LastCorrectAt    *time.Time
+	LastWrongAnswer  string
+	Corrected        bool            // 最近一次答错之后是否答对过
+	Status           string          // outstanding / mastered / dismissed
+	Exercise         models.Exercise `gorm:"-"`
+}
+
+// WrongStatsFilter 错题筛选条件
+type WrongStatsFilter struct {
+	Status      string // 为空时不筛选
+	KnowledgeID uint
+}
 
-	query := r.db.Model(&models.ExerciseRecord{}).
-		Where("user_id = ? AND is_correct = ?", userID, false)
+// GetWrongStats 获取按练习题汇总的错题列表，按最近答错时间倒序
+func (r *RecordRepository) GetWrongStats(userID uint, filter WrongStatsFilter, offset, limit int) ([]WrongExerciseStat, int64, error) {
+	where, args := wrongStatsWhere(userID, filter)
 
-	// 统计总数
-	if err := query.Count(&total).Error; err != nil {
+	var total int64
+	if err := r.db.Raw(wrongStatsSQL+"SELECT count(*) FROM wrong"+where, args...).Scan(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 分页查询
-	err := query.Preload("Exercise").
-		Order("id DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&records).Error
+	var stats []WrongExerciseStat
+	args = append(args, sql.Named("limit", limit), sql.Named("offset", offset))
+	err := r.db.Raw(wrongStatsSQL+"SELECT * FROM wrong"+where+
+		" ORDER BY last_wrong_at DESC, exercise_id DESC LIMIT @limit OFFSET @offset", args...).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return records, total, err
+	return stats, total, r.attachExercises(stats)
+}
+
+// GetPracticeCandidates 获取待巩固的错题用于再练习：优先最近答错后仍未答对、
+// 答错次数多、答错时间早的题目
+func (r *RecordRepository) GetPracticeCandidates(userID, knowledgeID uint, limit int) ([]WrongExerciseStat, error) {
+	where, args := wrongStatsWhere(userID, WrongStatsFilter{Status: models.WrongStatusOutstanding, KnowledgeID: knowledgeID})
+
+	var stats []WrongExerciseStat
+	args = append(args, sql.Named("limit", limit))
+	err := r.db.Raw(wrongStatsSQL+"SELECT * FROM wrong"+where+
+		" ORDER BY corrected, wrong_count DESC, last_wrong_at, exercise_id LIMIT @limit", args...).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, r.attachExercises(stats)
+}
+
+// CountAttempts 统计用户对该练习题的作答次数
+func (r *RecordRepository) CountAttempts(userID, exerciseID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ExerciseRecord{}).
+		Where("user_id = ? AND exercise_id = ?", userID, exerciseID).
+		Count(&count).Error
+	return count, err
+}
+
+// HasWrongAnswer 用户是否答错过该练习题
+func (r *RecordRepository) HasWrongAnswer(userID, exerciseID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ExerciseRecord{}).
+		Where("user_id = ? AND exercise_id = ? AND is_correct = ?", userID, exerciseID, false).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// SaveWrongMark 保存错题标记（已存在时更新）
+func (r *RecordRepository) SaveWrongMark(mark *models.WrongExerciseMark) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "exercise_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "marked_at", "updated_at"}),
+	}).Create(mark).Error
+}
+
+// DeleteWrongMark 删除错题标记
+func (r *RecordRepository) DeleteWrongMark(userID, exerciseID uint) error {
+	return r.db.Where("user_id = ? AND exercise_id = ?", userID, exerciseID).
+		Delete(&models.WrongExerciseMark{}).Error
+}
+
+// wrongStatsWhere 构造错题筛选条件
+func wrongStatsWhere(userID uint, filter WrongStatsFilter) (string, []interface{}) {
+	where := " WHERE 1 = 1"
+	args := []interface{}{sql.Named("user_id", userID)}
+	if filter.Status != "" {
+		where += " AND status = @status"
+		args = append(args, sql.Named("status", filter.Status))
+	}
+	if filter.KnowledgeID > 0 {
+		where += " AND knowledge_point_id = @knowledge_id"
+		args = append(args, sql.Named("knowledge_id", filter.KnowledgeID))
+	}
+	return where, args
+}
+
+// attachExercises 批量加载错题对应的练习题
+func (r *RecordRepository) attachExercises(stats []WrongExerciseStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(stats))
+	for _, s := range stats {
+		ids = append(ids, s.ExerciseID)
+	}
+
+	var exercises []models.Exercise
+	if err := r.db.Where("id IN ?", ids).Find(&exercises).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]models.Exercise, len(exercises))
+	for _, e := range exercises {
+		byID[e.ID] = e
+	}
+	for i := range stats {
+		stats[i].Exercise = byID[stats[i].ExerciseID]
+	}
+	return nil
 }
 
 // Delete 删除练习记录
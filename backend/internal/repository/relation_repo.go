@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"database/sql"
+
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/utils"
 
@@ -28,7 +30,7 @@ func (r *RelationRepository) GetByID(id uint) (*models.KnowledgeRelation, error)
 	err := r.db.First(&relation, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, utils.ErrKnowledgeNotFound
+			return nil, utils.ErrRelationNotFound
 		}
 		return nil, err
 	}
@@ -56,7 +58,108 @@ func (r *RelationRepository) List(fromPointID, toPointID uint, relationType stri
 	return relations, err
 }
 
+// prerequisiteClosureSQL 目标知识点的全部直接与间接前置关系（递归 CTE，UNION 去重保证有环时也能终止）
+const prerequisiteClosureSQL = `
+WITH RECURSIVE ancestors(id) AS (
+	SELECT CAST(@target AS bigint)
+	UNION
+	SELECT r.from_point_id
+	FROM knowledge_relations r
+	JOIN ancestors a ON r.to_point_id = a.id
+	JOIN knowledge_points k ON k.id = r.from_point_id AND k.deleted_at IS NULL
+	WHERE r.relation_type = 'prerequisite' AND r.deleted_at IS NULL
+)
+SELECT r.*
+FROM knowledge_relations r
+JOIN knowledge_points k ON k.id = r.from_point_id AND k.deleted_at IS NULL
+WHERE r.relation_type = 'prerequisite' AND r.deleted_at IS NULL
+	AND r.to_point_id IN (SELECT id FROM ancestors)
+`
+
+// GetPrerequisiteClosure 获取目标知识点的前置关系闭包
+func (r *RelationRepository) GetPrerequisiteClosure(targetID uint) ([]models.KnowledgeRelation, error) {
+	var relations []models.KnowledgeRelation
+	err := r.db.Raw(prerequisiteClosureSQL, sql.Named("target", targetID)).Scan(&relations).Error
+	return relations, err
+}
+
+// neighborhoodSQL 从知识点出发沿关联关系（不区分方向）遍历 depth 层，返回每个知识点的最短距离；
+// 不经过已删除的知识点，@type 为空时包含全部关联类型
+const neighborhoodSQL = `
+WITH RECURSIVE walk(id, depth) AS (
+	SELECT CAST(@id AS bigint), 0
+	UNION
+	SELECT CASE WHEN r.from_point_id = w.id THEN r.to_point_id ELSE r.from_point_id END, w.depth + 1
+	FROM walk w
+	JOIN knowledge_relations r ON (r.from_point_id = w.id OR r.to_point_id = w.id) AND r.deleted_at IS NULL
+	JOIN knowledge_points k ON k.id = CASE WHEN r.from_point_id = w.id THEN r.to_point_id ELSE r.from_point_id END
+		AND k.deleted_at IS NULL
+	WHERE w.depth < @depth AND (@type = '' OR r.relation_type = @type)
+)
+SELECT id, MIN(depth) AS depth FROM walk GROUP BY id ORDER BY depth, id
+`
+
+// Neighbor 邻域中的知识点及其与中心的距离
+type Neighbor struct {
+	ID    uint
+	Depth int
+}
+
+// GetNeighborhood 获取知识点 depth 层以内的邻域（包含中心，距离为 0）
+func (r *RelationRepository) GetNeighborhood(id uint, depth int, relationType string) ([]Neighbor, error) {
+	var neighbors []Neighbor
+	err := r.db.Raw(neighborhoodSQL,
+		sql.Named("id", id),
+		sql.Named("depth", depth),
+		sql.Named("type", relationType),
+	).Scan(&neighbors).Error
+	return neighbors, err
+}
+
+// ListAmong 获取两端都在给定知识点集合中的关联关系
+func (r *RelationRepository) ListAmong(pointIDs []uint, relationType string) ([]models.KnowledgeRelation, error) {
+	var relations []models.KnowledgeRelation
+	if len(pointIDs) == 0 {
+		return relations, nil
+	}
+	query := r.db.Where("from_point_id IN ? AND to_point_id IN ?", pointIDs, pointIDs)
+	if relationType != "" {
+		query = query.Where("relation_type = ?", relationType)
+	}
+	err := query.Order("id").Find(&relations).Error
+	return relations, err
+}
+
+// Update 更新知识关联
+func (r *RelationRepository) Update(relation *models.KnowledgeRelation) error {
+	return r.db.Save(relation).Error
+}
+
+// Exists 检查两个知识点之间是否已存在同类型关联
+func (r *RelationRepository) Exists(fromPointID, toPointID uint, relationType string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.KnowledgeRelation{}).
+		Where("from_point_id = ? AND to_point_id = ? AND relation_type = ? AND id <> ?",
+			fromPointID, toPointID, relationType, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // Delete 删除知识关联
 func (r *RelationRepository) Delete(id uint) error {
 	return r.db.Delete(&models.KnowledgeRelation{}, id).Error
 }
+
+// Restore 恢复已删除的知识关联
+func (r *RelationRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&models.KnowledgeRelation{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrRelationNotFound
+	}
+	return nil
+}
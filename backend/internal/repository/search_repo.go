@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SearchConfig 全文检索使用的文本搜索配置（见 migrations/005_full_text_search.up.sql）
+const SearchConfig = "eightgu_zh"
+
+// 检索结果类型
+const (
+	SearchTypeKnowledge = "knowledge"
+	SearchTypeExercise  = "exercise"
+)
+
+// headlineOptions ts_headline 高亮参数
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter= … "
+
+// matchedSQL 匹配的知识点与练习题：tsvector 全文匹配，或标题/正文子串匹配（pg_trgm 索引加速，
+// 弥补未安装中文分词时的召回）；排序分为 ts_rank 与标题相似度之和。
+// 练习题只按题干匹配、摘要也只取题干，避免通过检索结果或高亮片段泄露解析
+const matchedSQL = `
+WITH q AS (SELECT websearch_to_tsquery('eightgu_zh', @q) AS query),
+matched AS (
+	SELECT 'knowledge' AS type, k.id, k.title, k.category_id, k.difficulty, k.frequency,
+		coalesce(k.description, '') || E'\n' || coalesce(k.content, '') AS body,
+		ts_rank(k.search_vector, q.query) + similarity(k.title, @q) AS rank
+	FROM knowledge_points k, q
+	WHERE k.deleted_at IS NULL
+		AND (k.search_vector @@ q.query OR k.title ILIKE @like OR k.description ILIKE @like OR k.content ILIKE @like)
+	UNION ALL
+	SELECT 'exercise' AS type, e.id, e.question AS title, k.category_id, e.difficulty, k.frequency,
+		e.question AS body,
+		ts_rank(e.search_vector, q.query) + similarity(e.question, @q) AS rank
+	FROM exercises e
+	JOIN knowledge_points k ON k.id = e.knowledge_point_id AND k.deleted_at IS NULL, q
+	WHERE e.deleted_at IS NULL
+		AND (e.search_vector @@ q.query OR e.question ILIKE @like)
+)
+`
+
+// SearchParams 检索参数
+type SearchParams struct {
+	Query      string
+	Type       string
+	CategoryID uint
+	Difficulty string
+	Frequency  string
+	Offset     int
+	Limit      int
+}
+
+// SearchHit 检索结果
+type SearchHit struct {
+	Type       string  `json:"type"`
+	ID         uint    `json:"id"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet"`
+	Rank       float64 `json:"rank"`
+	CategoryID uint    `json:"category_id"`
+	Difficulty string  `json:"difficulty"`
+	Frequency  string  `json:"frequency"`
+}
+
+// FacetCount 分面统计
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets 检索结果分面
+type SearchFacets struct {
+	Type       []FacetCount `json:"type"`
+	Category   []FacetCount `json:"category"`
+	Difficulty []FacetCount `json:"difficulty"`
+	Frequency  []FacetCount `json:"frequency"`
+}
+
+// SearchRepository 全文检索仓库
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository 创建全文检索仓库
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search 检索知识点与练习题，返回当前页结果、总数和分面统计；
+// 分面统计不受分类/难度/频率筛选影响，便于前端展示可选的筛选项
+func (r *SearchRepository) Search(params SearchParams) ([]SearchHit, int64, *SearchFacets, error) {
+	args := []interface{}{
+		sql.Named("q", params.Query),
+		sql.Named("like", "%"+escapeLike(params.Query)+"%"),
+		sql.Named("opts", headlineOptions),
+	}
+
+	// 类型筛选对分面同样生效
+	base := " FROM matched WHERE 1 = 1"
+	if params.Type != "" {
+		base += " AND type = @type"
+		args = append(args, sql.Named("type", params.Type))
+	}
+
+	filters := base
+	if params.CategoryID > 0 {
+		filters += " AND category_id = @category_id"
+		args = append(args, sql.Named("category_id", params.CategoryID))
+	}
+	if params.Difficulty != "" {
+		filters += " AND difficulty = @difficulty"
+		args = append(args, sql.Named("difficulty", params.Difficulty))
+	}
+	if params.Frequency != "" {
+		filters += " AND frequency = @frequency"
+		args = append(args, sql.Named("frequency", params.Frequency))
+	}
+
+	var total int64
+	if err := r.db.Raw(matchedSQL+"SELECT count(*)"+filters, args...).Scan(&total).Error; err != nil {
+		return nil, 0, nil, err
+	}
+
+	hits := []SearchHit{}
+	pageArgs := append(args, sql.Named("limit", params.Limit), sql.Named("offset", params.Offset))
+	err := r.db.Raw(matchedSQL+`
+		SELECT type, id, title, category_id, difficulty, frequency, rank,
+			ts_headline('eightgu_zh', body, (SELECT query FROM q), @opts) AS snippet`+filters+`
+		ORDER BY rank DESC, type, id
+		LIMIT @limit OFFSET @offset`, pageArgs...).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	facets := &SearchFacets{}
+	for _, facet := range []struct {
+		dest   *[]FacetCount
+		column string
+		base   string
+	}{
+		{&facets.Type, "type", " FROM matched WHERE 1 = 1"},
+		{&facets.Difficulty, "difficulty", base},
+		{&facets.Frequency, "frequency", base},
+	} {
+		*facet.dest = []FacetCount{}
+		err := r.db.Raw(matchedSQL+"SELECT "+facet.column+" AS value, count(*) AS count"+facet.base+
+			" GROUP BY "+facet.column+" ORDER BY count DESC, value", args...).
+			Scan(facet.dest).Error
+		if err != nil {
+			return nil, 0, nil, err
+		}
+	}
+
+	facets.Category = []FacetCount{}
+	err = r.db.Raw(matchedSQL+`
+		SELECT m.category_id::text AS value, c.name AS label, m.count
+		FROM (SELECT category_id, count(*) AS count`+base+` GROUP BY category_id) m
+		JOIN categories c ON c.id = m.category_id
+		ORDER BY m.count DESC, c.sort_order, c.id`, args...).
+		Scan(&facets.Category).Error
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return hits, total, facets, nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// expiredDurationExpr 超时会话的时长：只计到最后一次心跳
+const expiredDurationExpr = "CAST(extract(epoch FROM last_heartbeat_at - started_at) AS integer)"
+
+// StudyRepository 学习会话仓库
+type StudyRepository struct {
+	db *gorm.DB
+}
+
+// NewStudyRepository 创建学习会话仓库
+func NewStudyRepository(db *gorm.DB) *StudyRepository {
+	return &StudyRepository{db: db}
+}
+
+// Start 开始学习会话：用户其它进行中的会话先结束（空闲超时的计到最后一次心跳），同一时间只有一个会话计时
+func (r *StudyRepository) Start(session *models.StudySession, idleBefore time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := expireIdle(tx.Where("user_id = ?", session.UserID), idleBefore); err != nil {
+			return err
+		}
+		err := tx.Model(&models.StudySession{}).
+			Where("user_id = ? AND status = ?", session.UserID, models.StudySessionActive).
+			Updates(map[string]interface{}{
+				"status":           models.StudySessionEnded,
+				"ended_at":         session.StartedAt,
+				"duration_seconds": gorm.Expr("CAST(extract(epoch FROM CAST(? AS timestamp) - started_at) AS integer)", session.StartedAt),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(session).Error
+	})
+}
+
+// GetByID 根据 ID 获取学习会话
+func (r *StudyRepository) GetByID(id uint) (*models.StudySession, error) {
+	var session models.StudySession
+	if err := r.db.First(&session, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.ErrStudySessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Touch 记录心跳并更新时长，会话已结束时返回 ErrStudySessionEnded
+func (r *StudyRepository) Touch(session *models.StudySession) error {
+	return r.updateActive(session, "last_heartbeat_at", "duration_seconds")
+}
+
+// Finish 保存会话的结束状态，会话已被并发结束时返回 ErrStudySessionEnded
+func (r *StudyRepository) Finish(session *models.StudySession) error {
+	return r.updateActive(session, "status", "last_heartbeat_at", "ended_at", "duration_seconds")
+}
+
+// updateActive 只更新仍在进行中的会话
+func (r *StudyRepository) updateActive(session *models.StudySession, columns ...string) error {
+	result := r.db.Model(session).
+		Where("status = ?", models.StudySessionActive).
+		Select(columns).
+		Updates(session)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrStudySessionEnded
+	}
+	return nil
+}
+
+// ExpireIdle 结束最后一次心跳早于 idleBefore 的会话，返回结束的数量
+func (r *StudyRepository) ExpireIdle(idleBefore time.Time) (int64, error) {
+	return expireIdle(r.db, idleBefore)
+}
+
+// Expire 会话空闲超时（最后一次心跳早于 idleBefore）时将其结束
+func (r *StudyRepository) Expire(id uint, idleBefore time.Time) error {
+	_, err := expireIdle(r.db.Where("id = ?", id), idleBefore)
+	return err
+}
+
+// expireIdle 把空闲超时的进行中会话标记为 expired，时长计到最后一次心跳
+func expireIdle(db *gorm.DB, idleBefore time.Time) (int64, error) {
+	result := db.Model(&models.StudySession{}).
+		Where("status = ? AND last_heartbeat_at < ?", models.StudySessionActive, idleBefore).
+		Updates(map[string]interface{}{
+			"status":           models.StudySessionExpired,
+			"ended_at":         gorm.Expr("last_heartbeat_at"),
+			"duration_seconds": gorm.Expr(expiredDurationExpr),
+		})
+	return result.RowsAffected, result.Error
+}
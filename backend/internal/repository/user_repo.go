@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/utils"
 
@@ -54,9 +56,55 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-// Update 更新用户
+// List 获取用户列表
+func (r *UserRepository) List(offset, limit int, role, search string) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+
+	// 筛选条件
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if search != "" {
+		query = query.Where("email LIKE ? OR username LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	err := query.Order("id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error
+
+	return users, total, err
+}
+
+// UpdateRole 更新用户角色
+func (r *UserRepository) UpdateRole(id uint, role string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+// UpdatePassword 更新用户密码（已哈希）
+func (r *UserRepository) UpdatePassword(id uint, hashedPassword string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+}
+
+// MarkEmailVerified 记录邮箱验证时间，已验证的用户保持原验证时间
+func (r *UserRepository) MarkEmailVerified(id uint, at time.Time) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", at).Error
+}
+
+// Update 更新用户资料（用户名与头像）；角色、密码等由专门的方法更新，避免并发请求用旧值覆盖
 func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return r.db.Model(user).Select("username", "avatar").Updates(user).Error
 }
 
 // Delete 删除用户
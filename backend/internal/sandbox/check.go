@@ -0,0 +1,151 @@
+package sandbox
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// allowedImports 提交的代码可以导入的包（完整路径匹配，不含子包）。
+// 只收录不访问文件系统、网络和进程的纯计算包；text/template、archive/zip、
+// path/filepath 等包不需要导入 os 也能读取文件，因此不能使用黑名单
+var allowedImports = map[string]bool{
+	"bufio":           true,
+	"bytes":           true,
+	"cmp":             true,
+	"container/heap":  true,
+	"container/list":  true,
+	"container/ring":  true,
+	"context":         true,
+	"encoding/base64": true,
+	"encoding/binary": true,
+	"encoding/hex":    true,
+	"encoding/json":   true,
+	"errors":          true,
+	"fmt":             true,
+	"hash":            true,
+	"hash/crc32":      true,
+	"hash/fnv":        true,
+	"io":              true,
+	"iter":            true,
+	"maps":            true,
+	"math":            true,
+	"math/big":        true,
+	"math/bits":       true,
+	"math/cmplx":      true,
+	"math/rand":       true,
+	"math/rand/v2":    true,
+	"regexp":          true,
+	"slices":          true,
+	"sort":            true,
+	"strconv":         true,
+	"strings":         true,
+	"sync":            true,
+	"sync/atomic":     true,
+	"time":            true,
+	"unicode":         true,
+	"unicode/utf16":   true,
+	"unicode/utf8":    true,
+}
+
+// CheckSource 检查提交的代码：包名必须为 solution，且只能导入 allowedImports 中的包
+func CheckSource(source string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), PackageName+".go", source, parser.ImportsOnly)
+	if err != nil {
+		return err
+	}
+	if file.Name.Name != PackageName {
+		return fmt.Errorf("包名必须为 %s", PackageName)
+	}
+
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return err
+		}
+		if !allowedImports[path] {
+			return fmt.Errorf("不允许导入 %q", path)
+		}
+	}
+	return nil
+}
+
+// ListTests 列出测试代码中的顶层测试函数
+func ListTests(testCode string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), PackageName+"_test.go", testCode, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	if file.Name.Name != PackageName {
+		return nil, fmt.Errorf("测试代码的包名必须为 %s", PackageName)
+	}
+
+	var tests []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !isTestName(fn.Name.Name) {
+			continue
+		}
+		params := fn.Type.Params.List
+		if len(params) != 1 || !isTestingT(params[0].Type) {
+			continue
+		}
+		if fn.Name.Name == harnessTest {
+			return nil, fmt.Errorf("测试函数名 %s 为保留名称", harnessTest)
+		}
+		// 测试由 harness 依次作为子测试运行，顶层并行会导致结果在测试结束前被记录
+		if len(params[0].Names) == 1 && callsParallel(fn.Body, params[0].Names[0].Name) {
+			return nil, fmt.Errorf("%s: 顶层测试不能调用 Parallel()，可以在子测试中使用", fn.Name.Name)
+		}
+		tests = append(tests, fn.Name.Name)
+	}
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("测试代码中没有测试函数")
+	}
+	return tests, nil
+}
+
+// isTestName 与 go test 的规则一致：Test 之后不能紧跟小写字母
+func isTestName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "Test")
+	if !ok {
+		return false
+	}
+	return rest == "" || !('a' <= rest[0] && rest[0] <= 'z')
+}
+
+// isTestingT 参数类型是否为 *testing.T
+func isTestingT(expr ast.Expr) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "testing" && sel.Sel.Name == "T"
+}
+
+// callsParallel 函数体中是否直接调用了 t.Parallel()（不含子测试的闭包）
+func callsParallel(body *ast.BlockStmt, t string) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Parallel" {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == t {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
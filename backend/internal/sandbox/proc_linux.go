@@ -0,0 +1,167 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+const isolationSupported = true
+
+// selfExe 沙箱子进程先以当前程序启动，完成初始化后再执行目标程序
+const selfExe = "/proc/self/exe"
+
+// sysProcAttr 在新的 user/mount/network/pid/ipc 命名空间中运行子进程：没有可用的网络接口，
+// 看不到也无法向宿主机上的其他进程发送信号，挂载的修改不影响宿主机；父进程退出时子进程随之终止
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getgid(), Size: 1},
+		},
+		Pdeathsig: syscall.SIGKILL,
+	}
+}
+
+// cpuLimitExceeded 进程是否因超出 CPU 时间限制被终止
+func cpuLimitExceeded(state *os.ProcessState) bool {
+	if state == nil {
+		return false
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && (status.Signal() == syscall.SIGXCPU || status.Signal() == syscall.SIGKILL)
+}
+
+// peakMemoryMB 进程的内存峰值（RSS）
+func peakMemoryMB(state *os.ProcessState) int {
+	if state == nil {
+		return 0
+	}
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	return int(usage.Maxrss / 1024) // Linux 上单位为 KB
+}
+
+// init 当前进程作为沙箱子进程启动时（见 Runner.command），在 main 运行之前
+// 设置资源限制和文件系统隔离，然后替换为目标程序，不会返回
+func init() {
+	raw, ok := os.LookupEnv(initEnv)
+	if !ok {
+		return
+	}
+	if err := initChild(raw); err != nil {
+		fmt.Fprintf(os.Stderr, "sandbox: %v\n", err)
+		os.Exit(126)
+	}
+}
+
+// initChild 沙箱子进程的初始化：参数为 limits 的 JSON，目标程序及参数为 os.Args[1:]
+func initChild(raw string) error {
+	var l limits
+	if err := json.Unmarshal([]byte(raw), &l); err != nil {
+		return err
+	}
+	if l.Root != "" {
+		if err := isolateFS(l.Root); err != nil {
+			return fmt.Errorf("isolate filesystem: %w", err)
+		}
+	}
+	// 未指定程序时只检查隔离是否可用
+	if len(os.Args) < 2 || os.Args[1] == "" {
+		os.Exit(0)
+	}
+
+	cpu := uint64(max(int(l.CPU.Seconds()), 1))
+	rlimits := map[int]uint64{
+		syscall.RLIMIT_CPU:  cpu,
+		syscall.RLIMIT_DATA: uint64(l.MemoryMB) << 20,
+	}
+	if l.FileMB > 0 {
+		rlimits[syscall.RLIMIT_FSIZE] = uint64(l.FileMB) << 20
+	}
+	for resource, value := range rlimits {
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: value, Max: value}); err != nil {
+			return fmt.Errorf("setrlimit %d: %w", resource, err)
+		}
+	}
+
+	path := os.Args[1]
+	if !strings.Contains(path, "/") {
+		var err error
+		if path, err = exec.LookPath(path); err != nil {
+			return err
+		}
+	}
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, initEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+	return syscall.Exec(path, os.Args[1:], env)
+}
+
+// isolateFS 把 root 作为只读的根目录（pivot_root），宿主机的其他文件全部不可见；
+// root/tmp 挂载为可写的 tmpfs。挂载只发生在子进程的 mount 命名空间中
+func isolateFS(root string) error {
+	// 挂载不传播回宿主机
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return err
+	}
+	// pivot_root 要求新的根目录是挂载点
+	if err := syscall.Mount(root, root, "", syscall.MS_BIND, ""); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", filepath.Join(root, "tmp"), "tmpfs",
+		syscall.MS_NOSUID|syscall.MS_NODEV, "size=16m,mode=1777"); err != nil {
+		return err
+	}
+	if err := syscall.Chdir(root); err != nil {
+		return err
+	}
+	// 新旧根目录叠放在同一位置，卸载后旧的根目录（宿主机文件系统）不再可达
+	if err := syscall.PivotRoot(".", "."); err != nil {
+		return err
+	}
+	if err := syscall.Unmount(".", syscall.MNT_DETACH); err != nil {
+		return err
+	}
+	if err := syscall.Chdir("/"); err != nil {
+		return err
+	}
+
+	// 重新挂载为只读；命名空间中不能清除继承来的挂载选项，需要原样保留
+	var st syscall.Statfs_t
+	if err := syscall.Statfs("/", &st); err != nil {
+		return err
+	}
+	flags := uintptr(syscall.MS_REMOUNT | syscall.MS_BIND | syscall.MS_RDONLY | syscall.MS_NOSUID | syscall.MS_NODEV)
+	for stFlag, msFlag := range map[int64]uintptr{
+		stNoExec:     syscall.MS_NOEXEC,
+		stNoAtime:    syscall.MS_NOATIME,
+		stNoDirAtime: syscall.MS_NODIRATIME,
+		stRelAtime:   syscall.MS_RELATIME,
+	} {
+		if st.Flags&stFlag != 0 {
+			flags |= msFlag
+		}
+	}
+	return syscall.Mount("", "/", "", flags, "")
+}
+
+// statfs 返回的挂载选项（与 MS_* 取值不完全相同）
+const (
+	stNoExec     = 0x0008
+	stNoAtime    = 0x0400
+	stNoDirAtime = 0x0800
+	stRelAtime   = 0x1000
+)
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"os"
+	"syscall"
+)
+
+const isolationSupported = false
+
+const selfExe = ""
+
+func sysProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+func cpuLimitExceeded(state *os.ProcessState) bool {
+	return false
+}
+
+func peakMemoryMB(state *os.ProcessState) int {
+	return 0
+}
@@ -0,0 +1,388 @@
+// Package sandbox 在受限的本地子进程中编译并运行代码题的 Go 测试。
+//
+// 每次提交在独立的临时目录中以 go test -c 编译，测试二进制在只包含它自己的只读根目录中运行：
+// 子进程位于新的 user/mount/network/pid/ipc 命名空间（无网络，看不到宿主机的文件），并限制
+// CPU 时间、内存、写文件大小和墙钟时间。提交的代码只能导入不访问文件系统、网络和进程的标准库包。
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+)
+
+// 运行结果状态
+const (
+	StatusPassed       = "passed"        // 全部测试通过
+	StatusFailed       = "failed"        // 部分测试未通过
+	StatusCompileError = "compile_error" // 编译失败或代码未通过检查
+	StatusTimeout      = "timeout"       // 超出运行时间或 CPU 时间
+	StatusMemoryLimit  = "memory_limit"  // 超出内存限制
+	StatusRuntimeError = "runtime_error" // 测试进程异常退出
+)
+
+// PackageName 提交代码与测试代码使用的包名
+const PackageName = "solution"
+
+var (
+	// ErrUnsupported 当前平台无法隔离运行代码
+	ErrUnsupported = errors.New("sandbox: process isolation is only supported on linux")
+	// ErrSourceTooLarge 提交的代码超过大小限制
+	ErrSourceTooLarge = errors.New("提交的代码过长")
+)
+
+// Submission 一次代码提交
+type Submission struct {
+	Source   string // 提交的代码（package solution）
+	TestCode string // 隐藏的测试代码（package solution）
+}
+
+// TestResult 单个测试的结果
+type TestResult struct {
+	Name    string  `json:"name"`
+	Passed  bool    `json:"passed"`
+	Output  string  `json:"output,omitempty"`
+	Elapsed float64 `json:"elapsed"` // 秒
+}
+
+// Result 运行结果
+type Result struct {
+	Status   string       `json:"status"`
+	Passed   int          `json:"passed"`
+	Total    int          `json:"total"`
+	Tests    []TestResult `json:"tests"`
+	Output   string       `json:"output,omitempty"` // 编译错误或测试之外的输出
+	Duration float64      `json:"duration"`         // 秒，包含编译时间
+}
+
+// Score 按通过的测试比例计算得分（0~1）
+func (r *Result) Score() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Passed) / float64(r.Total)
+}
+
+// Runner 代码沙箱
+type Runner struct {
+	cfg    config.SandboxConfig
+	goBin  string
+	gopath string
+	sem    chan struct{}
+}
+
+// NewRunner 创建代码沙箱，检查 Go 工具链和进程隔离是否可用
+func NewRunner(cfg *config.SandboxConfig) (*Runner, error) {
+	if !isolationSupported {
+		return nil, ErrUnsupported
+	}
+
+	goBin, err := exec.LookPath(cfg.GoBinary)
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: go toolchain not found: %w", err)
+	}
+
+	if cfg.CacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		cfg.CacheDir = filepath.Join(dir, "eightgu-sandbox")
+	}
+	if err := os.MkdirAll(filepath.Join(cfg.CacheDir, "gocache"), 0o700); err != nil {
+		return nil, err
+	}
+
+	r := &Runner{
+		cfg:    *cfg,
+		goBin:  goBin,
+		gopath: filepath.Join(cfg.CacheDir, "gopath"),
+		sem:    make(chan struct{}, max(cfg.MaxConcurrent, 1)),
+	}
+
+	// 确认当前环境允许创建隔离的命名空间并切换根目录
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := r.command(ctx, os.TempDir(), r.compileEnv(), limits{CPU: 30 * time.Second, MemoryMB: 1024}, goBin, "version").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: isolated process failed: %w: %s", err, bytes.TrimSpace(out))
+	}
+	root, err := newRunRoot()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(root)
+	out, err = r.command(ctx, root, nil, limits{CPU: time.Second, MemoryMB: 64, Root: root}, "").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: filesystem isolation failed: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return r, nil
+}
+
+// newRunRoot 创建运行测试时的根目录，只包含挂载 tmpfs 的 /tmp
+func newRunRoot() (string, error) {
+	root, err := os.MkdirTemp("", "eightgu-sandbox-run-")
+	if err != nil {
+		return "", err
+	}
+	if err := os.Mkdir(filepath.Join(root, "tmp"), 0o755); err != nil {
+		os.RemoveAll(root)
+		return "", err
+	}
+	return root, nil
+}
+
+// Run 编译并运行测试
+func (r *Runner) Run(ctx context.Context, sub Submission) (*Result, error) {
+	if len(sub.Source) > r.cfg.MaxSourceBytes {
+		return nil, ErrSourceTooLarge
+	}
+	tests, err := ListTests(sub.TestCode)
+	if err != nil {
+		return nil, fmt.Errorf("sandbox: invalid test code: %w", err)
+	}
+
+	select {
+	case r.sem <- struct{}{}:
+		defer func() { <-r.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	start := time.Now()
+	result := &Result{Total: len(tests), Tests: []TestResult{}}
+	defer func() { result.Duration = time.Since(start).Seconds() }()
+
+	if err := CheckSource(sub.Source); err != nil {
+		result.Status = StatusCompileError
+		result.Output = err.Error()
+		return result, nil
+	}
+
+	workDir, err := os.MkdirTemp("", "eightgu-sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	srcDir := filepath.Join(workDir, "src")
+	if err := os.Mkdir(srcDir, 0o700); err != nil {
+		return nil, err
+	}
+	runDir, err := newRunRoot()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(runDir)
+	files := map[string]string{
+		"go.mod":                         "module " + PackageName + "\n\ngo 1.22\n",
+		PackageName + ".go":              sub.Source,
+		PackageName + "_hidden_test.go":  sub.TestCode,
+		PackageName + "_harness_test.go": harnessFile(tests),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	// 编译：测试二进制直接输出到运行目录
+	binary := PackageName + ".test"
+	compileCtx, cancel := context.WithTimeout(ctx, r.cfg.CompileTimeout)
+	defer cancel()
+	compileLimits := limits{CPU: r.cfg.CompileTimeout, MemoryMB: r.cfg.CompileMemoryMB}
+	out, err := r.command(compileCtx, srcDir, r.compileEnv(), compileLimits, r.goBin, "test", "-c", "-o", filepath.Join(runDir, binary), ".").CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		result.Status = StatusCompileError
+		if compileCtx.Err() != nil {
+			result.Output = "编译超时"
+		} else {
+			result.Output = r.truncate(strings.ReplaceAll(string(out), srcDir+string(filepath.Separator), ""))
+		}
+		return result, nil
+	}
+	// 源码和隐藏测试在运行前删除；运行目录作为只读根目录，测试进程看不到宿主机的任何其他文件
+	if err := os.RemoveAll(srcDir); err != nil {
+		return nil, err
+	}
+
+	// 运行：测试框架的超时略短于墙钟超时，以便输出卡住的测试
+	resultsReader, resultsWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer resultsReader.Close()
+
+	runCtx, cancelRun := context.WithTimeout(ctx, r.cfg.RunTimeout+time.Second)
+	defer cancelRun()
+	output := &limitedBuffer{limit: r.cfg.MaxOutputBytes}
+	cmd := r.command(runCtx, runDir, r.runEnv(), limits{
+		CPU:      r.cfg.CPUTime,
+		MemoryMB: r.cfg.MemoryMB,
+		FileMB:   1,
+		Root:     runDir,
+	}, "/"+binary, "-test.v", "-test.count=1", "-test.run=^"+harnessTest+"$", "-test.timeout="+r.cfg.RunTimeout.String())
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.ExtraFiles = []*os.File{resultsWriter} // 子进程中为文件描述符 3
+	if err := cmd.Start(); err != nil {
+		resultsWriter.Close()
+		return nil, err
+	}
+	resultsWriter.Close()
+
+	reported := make(chan map[string]TestResult, 1)
+	go func() {
+		data, _ := io.ReadAll(io.LimitReader(resultsReader, 1<<20))
+		reported <- parseResults(string(data))
+	}()
+	runErr := cmd.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	outputs, rest := collectOutput(output.String(), tests)
+	result.Tests = buildTestResults(tests, <-reported, outputs)
+	result.Output = rest
+	for _, t := range result.Tests {
+		if t.Passed {
+			result.Passed++
+		}
+	}
+	if output.truncated {
+		result.Output += "\n... 输出过长，已截断"
+	}
+
+	switch {
+	case runCtx.Err() != nil || cpuLimitExceeded(cmd.ProcessState) || strings.Contains(output.String(), "panic: test timed out"):
+		result.Status = StatusTimeout
+	case runErr != nil && cmd.ProcessState.ExitCode() != 1 && r.outOfMemory(output.String(), cmd.ProcessState):
+		result.Status = StatusMemoryLimit
+	case result.Passed == result.Total && result.Total > 0 && runErr == nil:
+		result.Status = StatusPassed
+	case runErr != nil && cmd.ProcessState.ExitCode() != 1: // 测试失败时退出码为 1
+		result.Status = StatusRuntimeError
+	default:
+		result.Status = StatusFailed
+	}
+	return result, nil
+}
+
+// initEnv 沙箱子进程的初始化参数（limits 的 JSON）
+const initEnv = "EIGHTGU_SANDBOX_INIT"
+
+// limits 子进程资源限制与文件系统隔离
+type limits struct {
+	CPU      time.Duration `json:"cpu"`
+	MemoryMB int           `json:"memory_mb"`
+	FileMB   int           `json:"file_mb,omitempty"` // 0 表示不限制写文件大小
+	Root     string        `json:"root,omitempty"`    // 非空时以该目录为只读根目录运行，name 为其中的路径
+}
+
+// command 创建隔离的子进程：先在新的命名空间中启动当前程序，由 init 设置资源限制、
+// 切换根目录后 exec 目标程序，目标程序不会在隔离之前运行任何代码
+func (r *Runner) command(ctx context.Context, dir string, env []string, l limits, name string, args ...string) *exec.Cmd {
+	spec, _ := json.Marshal(l)
+	cmd := exec.CommandContext(ctx, selfExe, append([]string{name}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(slices.Clip(env), initEnv+"="+string(spec))
+	cmd.SysProcAttr = sysProcAttr()
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// oomMessages Go 运行时申请内存失败时的错误信息
+var oomMessages = []string{
+	"out of memory",
+	"cannot allocate memory",
+	"errno=12", // ENOMEM，例如创建线程失败
+}
+
+// outOfMemory 异常退出的测试进程是否因超出内存限制终止：运行时的错误信息表明内存不足，
+// 或者内存峰值已接近上限（此时运行时可能在任意位置失败，输出不固定）
+func (r *Runner) outOfMemory(output string, state *os.ProcessState) bool {
+	if peakMemoryMB(state) >= r.cfg.MemoryMB*3/4 {
+		return true
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "fatal error: ") && !strings.HasPrefix(line, "runtime: ") {
+			continue
+		}
+		for _, msg := range oomMessages {
+			if strings.Contains(line, msg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compileEnv 编译环境：禁用 cgo 与模块下载
+func (r *Runner) compileEnv() []string {
+	return []string{
+		"PATH=" + filepath.Dir(r.goBin) + ":/usr/bin:/bin",
+		"HOME=" + r.cfg.CacheDir,
+		"GOCACHE=" + filepath.Join(r.cfg.CacheDir, "gocache"),
+		"GOPATH=" + r.gopath,
+		"GOPROXY=off",
+		"GOFLAGS=-mod=mod",
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+		"GOENV=off",
+		"GOTELEMETRY=off",
+		"CGO_ENABLED=0",
+	}
+}
+
+// runEnv 运行环境：只保留必要变量，GOMEMLIMIT 让 GC 在触及内存上限前回收
+func (r *Runner) runEnv() []string {
+	return []string{
+		"HOME=/tmp",
+		"TMPDIR=/tmp",
+		fmt.Sprintf("GOMEMLIMIT=%dMiB", r.cfg.MemoryMB*9/10),
+	}
+}
+
+// truncate 截断过长的输出
+func (r *Runner) truncate(s string) string {
+	if len(s) <= r.cfg.MaxOutputBytes {
+		return s
+	}
+	return s[:r.cfg.MaxOutputBytes] + "\n... 输出过长，已截断"
+}
+
+// limitedBuffer 只保留前 limit 字节的输出
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
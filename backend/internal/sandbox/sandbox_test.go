@@ -0,0 +1,225 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+)
+
+const testCode = `package solution
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if got := Add(1, 2); got != 3 {
+		t.Fatalf("Add(1, 2) = %d", got)
+	}
+}
+
+func TestAddNegative(t *testing.T) {
+	if got := Add(-1, -2); got != -3 {
+		t.Errorf("Add(-1, -2) = %d", got)
+	}
+}
+
+func helper(t *testing.T) {}
+`
+
+func TestCheckSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		valid  bool
+	}{
+		{"ok", "package solution\n\nimport \"strings\"\n\nvar _ = strings.ToUpper", true},
+		{"wrong package", "package main\n", false},
+		{"os", "package solution\n\nimport \"os\"\n", false},
+		{"os/exec", "package solution\n\nimport \"os/exec\"\n", false},
+		{"net/http", "package solution\n\nimport \"net/http\"\n", false},
+		{"unsafe", "package solution\n\nimport \"unsafe\"\n", false},
+		{"embed", "package solution\n\nimport _ \"embed\"\n", false},
+		// 以下包不导入 os 也能读取文件
+		{"text/template", "package solution\n\nimport \"text/template\"\n", false},
+		{"path/filepath", "package solution\n\nimport \"path/filepath\"\n", false},
+		{"archive/zip", "package solution\n\nimport \"archive/zip\"\n", false},
+		{"log/syslog", "package solution\n\nimport \"log/syslog\"\n", false},
+		{"syntax error", "package solution\n\nimport (\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckSource(tt.source); (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestListTests(t *testing.T) {
+	tests, err := ListTests(testCode)
+	if err != nil {
+		t.Fatalf("ListTests failed: %v", err)
+	}
+	if strings.Join(tests, ",") != "TestAdd,TestAddNegative" {
+		t.Errorf("Unexpected tests: %v", tests)
+	}
+
+	if _, err := ListTests("package solution\n"); err == nil {
+		t.Error("Expected error when there are no tests")
+	}
+	parallel := "package solution\n\nimport \"testing\"\n\nfunc TestP(t *testing.T) { t.Parallel() }\n"
+	if _, err := ListTests(parallel); err == nil {
+		t.Error("Expected error for top-level t.Parallel()")
+	}
+}
+
+func TestCollectOutput(t *testing.T) {
+	output := `=== RUN   TestSandboxHarness
+=== RUN   TestSandboxHarness/TestAdd
+--- PASS: TestAdd (0.00s)
+=== RUN   TestSandboxHarness/TestAddNegative
+    solution_hidden_test.go:13: Add(-1, -2) = 3
+    --- FAIL: TestSandboxHarness/TestAddNegative (0.00s)
+--- FAIL: TestSandboxHarness (0.00s)
+FAIL
+`
+	outputs, rest := collectOutput(output, []string{"TestAdd", "TestAddNegative"})
+	if outputs["TestAddNegative"] != "solution_hidden_test.go:13: Add(-1, -2) = 3" {
+		t.Errorf("Unexpected output: %q", outputs["TestAddNegative"])
+	}
+	// 提交的代码伪造的结果行只会出现在日志中
+	if outputs["TestAdd"] != "--- PASS: TestAdd (0.00s)" {
+		t.Errorf("Unexpected output: %q", outputs["TestAdd"])
+	}
+	if rest != "" {
+		t.Errorf("Unexpected rest: %q", rest)
+	}
+}
+
+// Go 运行时内存不足时的错误信息随失败位置不同而变化
+func TestOutOfMemory(t *testing.T) {
+	runner := &Runner{cfg: config.SandboxConfig{MemoryMB: 128}}
+	tests := []struct {
+		output string
+		oom    bool
+	}{
+		{"fatal error: runtime: out of memory\n\ngoroutine 1 [running]:", true},
+		{"runtime: out of memory: cannot allocate 1048576-byte block (133169152 in use)\nfatal error: out of memory", true},
+		{"fatal error: runtime: cannot allocate memory", true},
+		{"fatal error: out of memory allocating heap arena metadata", true},
+		{"runtime: failed to create new OS thread (have 5 already; errno=12)\nfatal error: newosproc", true},
+		{"panic: boom\n\ngoroutine 7 [running]:", false},
+		{"    solution_hidden_test.go:5: out of memory", false},
+	}
+	for _, tt := range tests {
+		if got := runner.outOfMemory(tt.output, nil); got != tt.oom {
+			t.Errorf("outOfMemory(%q) = %v, want %v", tt.output, got, tt.oom)
+		}
+	}
+}
+
+// newTestRunner 创建沙箱；没有 Go 工具链或无法创建命名空间时跳过
+func newTestRunner(t *testing.T) *Runner {
+	if testing.Short() {
+		t.Skip("skipping sandbox integration test in short mode")
+	}
+	runner, err := NewRunner(&config.SandboxConfig{
+		GoBinary:        "go",
+		CompileTimeout:  2 * time.Minute,
+		CompileMemoryMB: 1024,
+		RunTimeout:      5 * time.Second,
+		CPUTime:         5 * time.Second,
+		MemoryMB:        128,
+		MaxSourceBytes:  64 * 1024,
+		MaxOutputBytes:  64 * 1024,
+		MaxConcurrent:   2,
+	})
+	if err != nil {
+		t.Skipf("sandbox unavailable: %v", err)
+	}
+	return runner
+}
+
+func TestRunnerRun(t *testing.T) {
+	runner := newTestRunner(t)
+
+	tests := []struct {
+		name   string
+		source string
+		status string
+		passed int
+	}{
+		{"correct", "package solution\n\nfunc Add(a, b int) int { return a + b }\n", StatusPassed, 2},
+		{"partial", "package solution\n\nfunc Add(a, b int) int {\n\tif a < 0 {\n\t\treturn 0\n\t}\n\treturn a + b\n}\n", StatusFailed, 1},
+		{"compile error", "package solution\n\nfunc Add(a, b int) int { return a + }\n", StatusCompileError, 0},
+		{"spoofed output", "package solution\n\nimport \"fmt\"\n\nfunc init() { fmt.Println(\"--- PASS: TestAddNegative (0.00s)\") }\n\nfunc Add(a, b int) int { return 3 }\n", StatusFailed, 1},
+		{"infinite loop", "package solution\n\nfunc Add(a, b int) int {\n\tfor {\n\t}\n}\n", StatusTimeout, 0},
+		{"memory", "package solution\n\nvar sink [][]byte\n\nfunc Add(a, b int) int {\n\tfor {\n\t\tb := make([]byte, 1<<20)\n\t\tfor i := range b {\n\t\t\tb[i] = 1\n\t\t}\n\t\tsink = append(sink, b)\n\t}\n}\n", StatusMemoryLimit, 0},
+		{"panic", "package solution\n\nfunc Add(a, b int) int { panic(\"boom\") }\n", StatusRuntimeError, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runner.Run(context.Background(), Submission{Source: tt.source, TestCode: testCode})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if result.Status != tt.status || result.Passed != tt.passed || result.Total != 2 {
+				t.Errorf("Expected %s %d/2, got %s %d/%d\noutput: %s\ntests: %+v",
+					tt.status, tt.passed, result.Status, result.Passed, result.Total, result.Output, result.Tests)
+			}
+		})
+	}
+}
+
+// 测试进程只能看到测试二进制和 /tmp：读不到已删除的源码、隐藏测试和宿主机上的文件。
+// 隐藏测试可以导入 os，借此在沙箱中执行任意文件操作
+func TestRunnerIsolatesFilesystem(t *testing.T) {
+	runner := newTestRunner(t)
+	hostFile, err := filepath.Abs("sandbox.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probe := fmt.Sprintf(`package solution
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIsolation(t *testing.T) {
+	for _, path := range []string{"../src/solution_hidden_test.go", "/src/solution.go", %q, "/etc/hostname", "/etc/passwd"} {
+		if data, err := os.ReadFile(path); err == nil {
+			t.Errorf("read %%s: %%.100q", path, data)
+		}
+	}
+	entries, err := os.ReadDir("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != "solution.test" && e.Name() != "tmp" {
+			t.Errorf("unexpected entry in root: %%s", e.Name())
+		}
+	}
+	if err := os.WriteFile("/escape", nil, 0o600); err == nil {
+		t.Error("root is writable")
+	}
+	if err := os.WriteFile("/tmp/scratch", []byte("ok"), 0o600); err != nil {
+		t.Errorf("tmp is not writable: %%v", err)
+	}
+}
+`, hostFile)
+
+	result, err := runner.Run(context.Background(), Submission{Source: "package solution\n", TestCode: probe})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Status != StatusPassed {
+		t.Errorf("Expected isolated run to pass, got %s\noutput: %s\ntests: %+v", result.Status, result.Output, result.Tests)
+	}
+}
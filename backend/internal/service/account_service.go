@@ -0,0 +1,236 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/mailer"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// 邮件令牌用途
+const (
+	purposeVerify = "verify" // 邮箱验证
+	purposeReset  = "reset"  // 重置密码
+)
+
+// Redis 键前缀
+const (
+	accountTokenKey = "auth:%s:%s"      // 一次性邮件令牌（哈希）→ 用户 ID
+	accountUserKey  = "auth:%s_user:%d" // 用户最近一次签发的令牌哈希，新令牌签发后旧令牌失效
+)
+
+// 未配置时的默认有效期
+const (
+	defaultVerifyTokenTTL = 24 * time.Hour
+	defaultResetTokenTTL  = 30 * time.Minute
+)
+
+// AccountService 邮箱验证与找回密码服务，令牌一次性使用并保存在 Redis 中
+type AccountService struct {
+	userRepo     *repository.UserRepository
+	cache        *cache.Cache
+	mailer       mailer.Mailer
+	tokenService *TokenService
+	patService   *PersonalTokenService
+	baseURL      string
+	verifyTTL    time.Duration
+	resetTTL     time.Duration
+}
+
+// NewAccountService 创建账号服务，baseURL 为邮件中链接指向的前端地址
+func NewAccountService(
+	userRepo *repository.UserRepository,
+	c *cache.Cache,
+	m mailer.Mailer,
+	tokenService *TokenService,
+	patService *PersonalTokenService,
+	baseURL string,
+	verifyTTL, resetTTL time.Duration,
+) *AccountService {
+	if verifyTTL <= 0 {
+		verifyTTL = defaultVerifyTokenTTL
+	}
+	if resetTTL <= 0 {
+		resetTTL = defaultResetTokenTTL
+	}
+	return &AccountService{
+		userRepo:     userRepo,
+		cache:        c,
+		mailer:       m,
+		tokenService: tokenService,
+		patService:   patService,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		verifyTTL:    verifyTTL,
+		resetTTL:     resetTTL,
+	}
+}
+
+// EmailRequest 发送邮件请求（重发验证邮件、找回密码）
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// VerifyEmailRequest 邮箱验证请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// SendVerification 向用户发送邮箱验证邮件
+func (s *AccountService) SendVerification(user *models.User) error {
+	token, err := s.issue(purposeVerify, user.ID, s.verifyTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "验证你的邮箱",
+		Body: fmt.Sprintf("%s，你好：\n\n请在 %s 内打开以下链接完成邮箱验证：\n%s\n\n如果这不是你本人的操作，请忽略本邮件。\n",
+			user.Username, formatTTL(s.verifyTTL), s.link("/verify-email", token)),
+	})
+}
+
+// ResendVerification 重发验证邮件；邮箱不存在或已验证时同样返回成功，避免泄露注册信息
+func (s *AccountService) ResendVerification(req *EmailRequest) error {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	if err := s.SendVerification(user); err != nil {
+		log.Printf("Failed to send verification mail to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// VerifyEmail 使用验证令牌完成邮箱验证
+func (s *AccountService) VerifyEmail(req *VerifyEmailRequest) (*models.User, error) {
+	userID, err := s.consume(purposeVerify, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.MarkEmailVerified(userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(userID)
+}
+
+// ForgotPassword 发送重置密码邮件；邮箱不存在时同样返回成功，避免泄露注册信息
+func (s *AccountService) ForgotPassword(req *EmailRequest) error {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		return nil
+	}
+
+	token, err := s.issue(purposeReset, user.ID, s.resetTTL)
+	if err != nil {
+		return err
+	}
+	err = s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "重置你的密码",
+		Body: fmt.Sprintf("%s，你好：\n\n请在 %s 内打开以下链接设置新密码，链接只能使用一次：\n%s\n\n如果这不是你本人的操作，请忽略本邮件，你的密码不会改变。\n",
+			user.Username, formatTTL(s.resetTTL), s.link("/reset-password", token)),
+	})
+	if err != nil {
+		log.Printf("Failed to send password reset mail to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword 使用重置令牌设置新密码，并注销用户的全部会话与个人访问令牌；
+// 能收到邮件说明用户拥有该邮箱，未验证的邮箱同时标记为已验证
+func (s *AccountService) ResetPassword(req *ResetPasswordRequest) error {
+	userID, err := s.consume(purposeReset, req.Token)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword); err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerified(userID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.patService.RevokeAll(userID); err != nil {
+		return err
+	}
+	return s.tokenService.RevokeUser(userID)
+}
+
+// issue 签发一次性令牌，同一用途下用户之前的令牌失效
+func (s *AccountService) issue(purpose string, userID uint, ttl time.Duration) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	hash := hashToken(token)
+
+	userKey := fmt.Sprintf(accountUserKey, purpose, userID)
+	previous, err := s.cache.GetDel(userKey)
+	if err != nil && !cache.IsNil(err) {
+		return "", err
+	}
+	if previous != "" {
+		if err := s.cache.Delete(fmt.Sprintf(accountTokenKey, purpose, previous)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.cache.Set(fmt.Sprintf(accountTokenKey, purpose, hash), userID, ttl); err != nil {
+		return "", err
+	}
+	if err := s.cache.Set(userKey, hash, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consume 校验并作废一次性令牌，返回令牌所属的用户
+func (s *AccountService) consume(purpose, token string) (uint, error) {
+	raw, err := s.cache.GetDel(fmt.Sprintf(accountTokenKey, purpose, hashToken(token)))
+	if cache.IsNil(err) {
+		return 0, utils.ErrEmailTokenInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	userID, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, utils.ErrEmailTokenInvalid
+	}
+	if err := s.cache.Delete(fmt.Sprintf(accountUserKey, purpose, userID)); err != nil {
+		return 0, err
+	}
+	return uint(userID), nil
+}
+
+// link 邮件中的前端链接
+func (s *AccountService) link(path, token string) string {
+	return s.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// formatTTL 有效期的中文描述
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d 小时", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d 分钟", int(ttl.Minutes()))
+}
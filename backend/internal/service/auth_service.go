@@ -1,6 +1,10 @@
 package service
 
 import (
+	"errors"
+	"log"
+	"sync"
+
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/repository"
 	"eight-gu-learning-platform/internal/utils"
@@ -8,18 +12,48 @@ import (
 
 // AuthService 认证服务
 type AuthService struct {
-	userRepo *repository.UserRepository
-	jwtMgr   *utils.JWTManager
+	userRepo        *repository.UserRepository
+	auditRepo       *repository.LoginAuditRepository
+	jwtMgr          *utils.JWTManager
+	tokenService    *TokenService
+	patService      *PersonalTokenService
+	accountService  *AccountService
+	loginGuard      *LoginGuard
+	mfaService      *MFAService
+	requireVerified bool
 }
 
-// NewAuthService 创建认证服务
-func NewAuthService(userRepo *repository.UserRepository, jwtMgr *utils.JWTManager) *AuthService {
+// NewAuthService 创建认证服务，requireVerified 为 true 时邮箱验证后才能登录
+func NewAuthService(
+	userRepo *repository.UserRepository,
+	auditRepo *repository.LoginAuditRepository,
+	jwtMgr *utils.JWTManager,
+	tokenService *TokenService,
+	patService *PersonalTokenService,
+	accountService *AccountService,
+	loginGuard *LoginGuard,
+	mfaService *MFAService,
+	requireVerified bool,
+) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		jwtMgr:   jwtMgr,
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		jwtMgr:          jwtMgr,
+		tokenService:    tokenService,
+		patService:      patService,
+		accountService:  accountService,
+		loginGuard:      loginGuard,
+		mfaService:      mfaService,
+		requireVerified: requireVerified,
 	}
 }
 
+// dummyPasswordHash 邮箱不存在时用于比对的哈希，使响应时间与密码错误时一致
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := utils.HashPassword("eight-gu-dummy-password")
+	return hash
+})
+
 // RegisterRequest 注册请求
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -33,10 +67,57 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// AuthResponse 认证响应
+// LoginMFARequest 登录第二步：挑战令牌与验证码（或恢复码）
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFATokenRequest 登录时绑定两步验证的挑战令牌
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// LoginClient 登录请求的客户端信息，用于失败计数与审计
+type LoginClient struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginHistoryRequest 登录记录请求
+type LoginHistoryRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 登出请求
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// AuthResponse 认证响应；需要先验证邮箱时不签发令牌，
+// 需要两步验证时只返回 MFAToken，用它完成登录第二步
 type AuthResponse struct {
-	User  *models.User `json:"user"`
-	Token string        `json:"token"`
+	User                  *models.User `json:"user,omitempty"`
+	Token                 string       `json:"token,omitempty"`
+	RefreshToken          string       `json:"refresh_token,omitempty"`
+	ExpiresIn             int64        `json:"expires_in,omitempty"`
+	VerificationRequired  bool         `json:"verification_required,omitempty"`
+	MFARequired           bool         `json:"mfa_required,omitempty"`            // 需要输入验证码
+	MFAEnrollmentRequired bool         `json:"mfa_enrollment_required,omitempty"` // 角色要求两步验证，需要先完成绑定
+	MFAToken              string       `json:"mfa_token,omitempty"`
+	RecoveryCodes         []string     `json:"recovery_codes,omitempty"` // 登录时完成绑定生成的恢复码
 }
 
 // Register 用户注册
@@ -57,46 +138,296 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 		Email:    req.Email,
 		Password: hashedPassword,
 		Username: req.Username,
+		Role:     models.RoleLearner,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
 
-	// 生成 Token
-	token, err := s.jwtMgr.GenerateToken(user.ID, user.Email, user.Username)
+	// 邮件发送失败不影响注册，用户可以重新发送验证邮件
+	if err := s.accountService.SendVerification(user); err != nil {
+		log.Printf("Failed to send verification mail to user %d: %v", user.ID, err)
+	}
+	if s.requireVerified {
+		return &AuthResponse{User: user, VerificationRequired: true}, nil
+	}
+
+	return s.issueTokens(user)
+}
+
+// Login 用户登录。邮箱不存在与密码错误返回相同的 ErrInvalidCredentials；
+// 账号或 IP 失败次数过多时返回 LoginLockedError；启用了两步验证时返回挑战令牌。每次尝试都写入登录审计
+func (s *AuthService) Login(req *LoginRequest, client LoginClient) (*AuthResponse, error) {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil && !errors.Is(err, utils.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if err := s.loginGuard.Check(req.Email, client.IP); err != nil {
+		s.audit(user, req.Email, client, models.LoginMethodPassword, models.LoginLocked)
+		return nil, err
+	}
+
+	// 邮箱不存在时同样比对一次密码，避免通过响应时间探测账号
+	hash := dummyPasswordHash()
+	if user != nil {
+		hash = user.Password
+	}
+	if !utils.CheckPassword(req.Password, hash) || user == nil {
+		s.audit(user, req.Email, client, models.LoginMethodPassword, models.LoginInvalidCredentials)
+		if err := s.loginGuard.Fail(req.Email, client.IP); err != nil {
+			return nil, err
+		}
+		return nil, utils.ErrInvalidCredentials
+	}
+
+	if s.requireVerified && user.EmailVerifiedAt == nil {
+		s.audit(user, req.Email, client, models.LoginMethodPassword, models.LoginEmailNotVerified)
+		return nil, utils.ErrEmailNotVerified
+	}
+
+	return s.completeLogin(user, models.LoginMethodPassword, client)
+}
+
+// LoginMFA 登录第二步：校验验证码或恢复码后签发令牌。输错计入挑战的尝试次数与登录失败次数
+func (s *AuthService) LoginMFA(req *LoginMFARequest, client LoginClient) (*AuthResponse, error) {
+	var user *models.User
+	var method string
+	err := s.mfaService.consumeChallenge(req.MFAToken, false, func(challenge *mfaChallenge) error {
+		var err error
+		user, err = s.userRepo.GetByID(challenge.UserID)
+		if err != nil {
+			return err
+		}
+		method = challenge.Method
+		if err := s.loginGuard.Check(user.Email, client.IP); err != nil {
+			s.audit(user, user.Email, client, method, models.LoginLocked)
+			return err
+		}
+		if err := s.mfaService.Verify(user.ID, req.Code); err != nil {
+			if !errors.Is(err, utils.ErrMFACodeInvalid) {
+				return err
+			}
+			s.audit(user, user.Email, client, method, models.LoginMFAFailed)
+			if err := s.loginGuard.Fail(user.Email, client.IP); err != nil {
+				return err
+			}
+			return utils.ErrMFACodeInvalid
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginGuard.Succeed(user.Email); err != nil {
+		return nil, err
+	}
+	s.audit(user, user.Email, client, method, models.LoginSucceeded)
+	return s.issueTokens(user)
+}
+
+// BeginLoginEnrollment 角色要求两步验证但尚未绑定的用户在登录过程中获取绑定密钥
+func (s *AuthService) BeginLoginEnrollment(req *MFATokenRequest) (*MFAEnrollment, error) {
+	challenge, err := s.mfaService.peekChallenge(req.MFAToken, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.mfaService.BeginEnrollment(challenge.UserID)
+}
+
+// EnableLoginMFA 登录过程中确认绑定，启用两步验证后签发令牌并返回恢复码
+func (s *AuthService) EnableLoginMFA(req *LoginMFARequest, client LoginClient) (*AuthResponse, error) {
+	var user *models.User
+	var method string
+	var codes *RecoveryCodesResponse
+	err := s.mfaService.consumeChallenge(req.MFAToken, true, func(challenge *mfaChallenge) error {
+		var err error
+		user, err = s.userRepo.GetByID(challenge.UserID)
+		if err != nil {
+			return err
+		}
+		method = challenge.Method
+		codes, err = s.mfaService.Enable(user.ID, &MFACodeRequest{Code: req.Code})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginGuard.Succeed(user.Email); err != nil {
+		return nil, err
+	}
+	s.audit(user, user.Email, client, method, models.LoginSucceeded)
+	resp, err := s.issueTokens(user)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = codes.RecoveryCodes
+	return resp, nil
+}
+
+// ListLogins 获取用户自己的登录记录
+func (s *AuthService) ListLogins(userID uint, req *LoginHistoryRequest) ([]models.LoginAudit, int64, error) {
+	offset := (req.Page - 1) * req.PageSize
+	return s.auditRepo.ListByUser(userID, offset, req.PageSize)
+}
+
+// LoginExternal 第三方登录完成后写入登录审计并签发令牌，method 为登录方式
+func (s *AuthService) LoginExternal(userID uint, method string, client LoginClient) (*AuthResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.completeLogin(user, method, client)
+}
+
+// completeLogin 第一步验证通过：启用了两步验证或角色要求两步验证时签发挑战令牌，否则直接签发令牌。
+// 只有真正签发令牌时才清除账号的失败计数，否则反复“密码正确、验证码错误”永远不会触发锁定
+func (s *AuthService) completeLogin(user *models.User, method string, client LoginClient) (*AuthResponse, error) {
+	enabled, err := s.mfaService.Enabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled || s.mfaService.Required(user.Role) {
+		token, err := s.mfaService.issueChallenge(user.ID, method, !enabled)
+		if err != nil {
+			return nil, err
+		}
+		s.audit(user, user.Email, client, method, models.LoginMFARequired)
+		return &AuthResponse{
+			MFARequired:           enabled,
+			MFAEnrollmentRequired: !enabled,
+			MFAToken:              token,
+		}, nil
+	}
+
+	if err := s.loginGuard.Succeed(user.Email); err != nil {
+		return nil, err
+	}
+	s.audit(user, user.Email, client, method, models.LoginSucceeded)
+	return s.issueTokens(user)
+}
+
+// audit 写入登录审计，失败只记录日志，不影响登录
+func (s *AuthService) audit(user *models.User, email string, client LoginClient, method, reason string) {
+	record := &models.LoginAudit{
+		Email:     email,
+		IP:        client.IP,
+		UserAgent: truncateRunes(client.UserAgent, 500),
+		Success:   reason == models.LoginSucceeded,
+		Reason:    reason,
+		Method:    method,
+	}
+	if user != nil {
+		record.UserID = &user.ID
+	}
+	if err := s.auditRepo.Create(record); err != nil {
+		log.Printf("Failed to record login audit for %s: %v", email, err)
+	}
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// ChangePassword 校验原密码后修改密码，注销用户的全部会话与个人访问令牌并为当前客户端签发新令牌
+func (s *AuthService) ChangePassword(userID uint, req *ChangePasswordRequest) (*AuthResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.CheckPassword(req.OldPassword, user.Password) {
+		return nil, utils.NewParamError("原密码不正确")
+	}
+	if req.NewPassword == req.OldPassword {
+		return nil, utils.NewParamError("新密码不能与原密码相同")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return nil, err
+	}
+	if err := s.patService.RevokeAll(user.ID); err != nil {
+		return nil, err
+	}
+	if err := s.tokenService.RevokeUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user)
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+func (s *AuthService) Refresh(req *RefreshRequest) (*AuthResponse, error) {
+	userID, refreshToken, err := s.tokenService.Rotate(req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 重新读取用户，确保角色等信息是最新的
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, utils.ErrRefreshTokenInvalid
+	}
+
+	token, err := s.jwtMgr.GenerateToken(user.ID, user.Email, user.Username, user.Role)
 	if err != nil {
 		return nil, err
 	}
 
 	return &AuthResponse{
-		User:  user,
-		Token: token,
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtMgr.ExpireTime().Seconds()),
 	}, nil
 }
 
-// Login 用户登录
-func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
-	// 获取用户
-	user, err := s.userRepo.GetByEmail(req.Email)
-	if err != nil {
-		return nil, utils.ErrUserNotFound
+// Logout 登出：吊销当前访问令牌及对应的刷新令牌
+func (s *AuthService) Logout(claims *utils.Claims, req *LogoutRequest) error {
+	if err := s.tokenService.DenyAccessToken(claims); err != nil {
+		return err
 	}
+	if req.RefreshToken != "" {
+		return s.tokenService.RevokeRefreshToken(req.RefreshToken)
+	}
+	return nil
+}
 
-	// 验证密码
-	if !utils.CheckPassword(req.Password, user.Password) {
-		return nil, utils.ErrPasswordIncorrect
+// LogoutAll 注销用户的全部会话与个人访问令牌
+func (s *AuthService) LogoutAll(userID uint) error {
+	if err := s.patService.RevokeAll(userID); err != nil {
+		return err
+	}
+	return s.tokenService.RevokeUser(userID)
+}
+
+// issueTokens 签发访问令牌和刷新令牌
+func (s *AuthService) issueTokens(user *models.User) (*AuthResponse, error) {
+	token, err := s.jwtMgr.GenerateToken(user.ID, user.Email, user.Username, user.Role)
+	if err != nil {
+		return nil, err
 	}
 
-	// 生成 Token
-	token, err := s.jwtMgr.GenerateToken(user.ID, user.Email, user.Username)
+	refreshToken, err := s.tokenService.IssueRefreshToken(user.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &AuthResponse{
-		User:  user,
-		Token: token,
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtMgr.ExpireTime().Seconds()),
 	}, nil
 }
 
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/config"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/totp"
+	"eight-gu-learning-platform/internal/utils"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// integrationDeps 连接 TEST_DATABASE_DSN（已执行全部迁移）与 TEST_REDIS_ADDR，任一未设置时跳过。
+// 数据库操作在测试结束时回滚
+func integrationDeps(t *testing.T) (*gorm.DB, *cache.Cache) {
+	t.Helper()
+	dsn, redisAddr := os.Getenv("TEST_DATABASE_DSN"), os.Getenv("TEST_REDIS_ADDR")
+	if dsn == "" || redisAddr == "" {
+		t.Skip("TEST_DATABASE_DSN or TEST_REDIS_ADDR not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect test database: %v", err)
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx, testRedis(t)
+}
+
+// testRedis 连接 TEST_REDIS_ADDR（host:port），未设置时跳过
+func testRedis(t *testing.T) *cache.Cache {
+	t.Helper()
+	redisAddr := os.Getenv("TEST_REDIS_ADDR")
+	if redisAddr == "" {
+		t.Skip("TEST_REDIS_ADDR not set")
+	}
+	host, portStr, err := net.SplitHostPort(redisAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := cache.NewCache(&config.RedisConfig{Host: host, Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// 密码正确但两步验证码错误的循环不能清除失败计数，否则持有密码的攻击者可以无限次猜测验证码
+func TestLoginMFAFailuresEventuallyLockAccount(t *testing.T) {
+	db, redisClient := integrationDeps(t)
+
+	password := "correct-password"
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{
+		Email:    "mfa-lockout-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "@example.com",
+		Password: hash,
+		Username: "mfa-lockout",
+		Role:     models.RoleLearner,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	box, err := utils.NewSecretBox("test-encryption-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := box.Seal(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := db.Create(&models.UserMFA{UserID: user.ID, Secret: sealed, EnabledAt: &now}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	const threshold = 3
+	loginGuard := NewLoginGuard(cache.NewLoginGuard(redisClient),
+		cache.LoginLockPolicy{Threshold: threshold, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: time.Minute},
+		cache.LoginLockPolicy{Threshold: 1000, Window: time.Minute, BaseLockout: time.Minute, MaxLockout: time.Minute},
+	)
+	t.Cleanup(func() { loginGuard.Succeed(user.Email) })
+
+	userRepo := repository.NewUserRepository(db)
+	mfaService := NewMFAService(repository.NewMFARepository(db), userRepo, redisClient, box, "test", nil, time.Minute)
+	authService := NewAuthService(userRepo, repository.NewLoginAuditRepository(db),
+		utils.NewJWTManager("test-secret", time.Minute, "test"),
+		NewTokenService(redisClient, time.Minute, time.Hour), nil, nil, loginGuard, mfaService, false)
+	client := LoginClient{IP: "192.0.2.10", UserAgent: "test"}
+
+	var locked *LoginLockedError
+	for cycle := 1; cycle <= threshold; cycle++ {
+		resp, err := authService.Login(&LoginRequest{Email: user.Email, Password: password}, client)
+		if err != nil {
+			t.Fatalf("Cycle %d: expected password step to pass, got %v", cycle, err)
+		}
+		if !resp.MFARequired || resp.MFAToken == "" {
+			t.Fatalf("Cycle %d: expected mfa challenge, got %+v", cycle, resp)
+		}
+
+		// 恢复码格式但不存在，一定校验失败
+		_, err = authService.LoginMFA(&LoginMFARequest{MFAToken: resp.MFAToken, Code: "aaaaa-aaaaa"}, client)
+		if cycle < threshold {
+			if !errors.Is(err, utils.ErrMFACodeInvalid) {
+				t.Fatalf("Cycle %d: expected invalid code, got %v", cycle, err)
+			}
+			continue
+		}
+		if !errors.As(err, &locked) {
+			t.Fatalf("Cycle %d: expected account to be locked, got %v", cycle, err)
+		}
+	}
+
+	if _, err := authService.Login(&LoginRequest{Email: user.Email, Password: password}, client); !errors.As(err, &locked) {
+		t.Errorf("Expected locked account to reject the correct password, got %v", err)
+	}
+}
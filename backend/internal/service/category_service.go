@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// CategoryService 分类树服务
+type CategoryService struct {
+	categoryRepo *repository.CategoryRepository
+}
+
+// NewCategoryService 创建分类树服务
+func NewCategoryService(categoryRepo *repository.CategoryRepository) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CategoryTreeRequest 分类树请求
+type CategoryTreeRequest struct {
+	Root     uint `form:"root"`     // 只返回以该分类为根的子树
+	Progress bool `form:"progress"` // 统计当前用户的完成度（需要登录）
+}
+
+// CategoryNode 分类树节点
+type CategoryNode struct {
+	ID                   uint            `json:"id"`
+	Name                 string          `json:"name"`
+	Slug                 string          `json:"slug"`
+	Description          string          `json:"description"`
+	ParentID             *uint           `json:"parent_id"`
+	SortOrder            int             `json:"sort_order"`
+	Depth                int             `json:"depth"`
+	KnowledgeCount       int64           `json:"knowledge_count"`        // 包含子孙分类
+	DirectKnowledgeCount int64           `json:"direct_knowledge_count"` // 直接属于该分类
+	CompletedCount       *int64          `json:"completed_count,omitempty"`
+	CompletionRate       *float64        `json:"completion_rate,omitempty"` // 已完成知识点的百分比
+	Children             []*CategoryNode `json:"children"`
+}
+
+// GetTree 获取分类树，userID 不为 0 时统计用户的完成度
+func (s *CategoryService) GetTree(userID uint, req *CategoryTreeRequest) ([]*CategoryNode, error) {
+	rows, err := s.categoryRepo.GetTree(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, nodes := buildCategoryTree(rows, userID != 0)
+	if req.Root == 0 {
+		return roots, nil
+	}
+	node, ok := nodes[req.Root]
+	if !ok {
+		return nil, utils.ErrCategoryNotFound
+	}
+	return []*CategoryNode{node}, nil
+}
+
+// buildCategoryTree 由先序排列的行构建嵌套的分类树，并把知识点数量向上汇总
+func buildCategoryTree(rows []repository.CategoryTreeRow, withProgress bool) ([]*CategoryNode, map[uint]*CategoryNode) {
+	roots := make([]*CategoryNode, 0)
+	nodes := make(map[uint]*CategoryNode, len(rows))
+	completed := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		node := &CategoryNode{
+			ID:                   row.ID,
+			Name:                 row.Name,
+			Slug:                 row.Slug,
+			Description:          row.Description,
+			ParentID:             row.ParentID,
+			SortOrder:            row.SortOrder,
+			Depth:                row.Depth,
+			KnowledgeCount:       row.KnowledgeCount,
+			DirectKnowledgeCount: row.KnowledgeCount,
+			Children:             []*CategoryNode{},
+		}
+		nodes[row.ID] = node
+		completed[row.ID] = row.CompletedCount
+		if parent, ok := nodes[parentOf(row.ParentID)]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	// 逆先序遍历时子分类总在父分类之前
+	for i := len(rows) - 1; i >= 0; i-- {
+		node := nodes[rows[i].ID]
+		if parent, ok := nodes[parentOf(node.ParentID)]; ok {
+			parent.KnowledgeCount += node.KnowledgeCount
+			completed[parent.ID] += completed[node.ID]
+		}
+		if withProgress {
+			count := completed[node.ID]
+			rate := 0.0
+			if node.KnowledgeCount > 0 {
+				rate = math.Round(float64(count)/float64(node.KnowledgeCount)*1000) / 10
+			}
+			node.CompletedCount = &count
+			node.CompletionRate = &rate
+		}
+	}
+	return roots, nodes
+}
+
+// parentOf 父分类 ID，根分类为 0
+func parentOf(parentID *uint) uint {
+	if parentID == nil {
+		return 0
+	}
+	return *parentID
+}
+
+// checkCategoryParents 校验修改父分类后的分类树：changed 中的分类沿父分类向上不能回到自身，
+// 移动后整棵子树的层级也不能超过上限。parents 为全部分类的父分类（已应用修改）
+func checkCategoryParents(parents map[uint]*uint, changed []uint) error {
+	depths := make(map[uint]int, len(changed))
+	for _, id := range changed {
+		depth := 0
+		for p := parents[id]; p != nil; p = parents[*p] {
+			if *p == id {
+				return utils.NewParamError(fmt.Sprintf("不能把分类 %d 移动到自身或其子分类下", id))
+			}
+			if depth++; depth > repository.MaxCategoryDepth {
+				return utils.NewParamError(fmt.Sprintf("分类层级不能超过 %d 层", repository.MaxCategoryDepth))
+			}
+		}
+		depths[id] = depth
+	}
+
+	// 没有环之后再检查子树高度
+	children := make(map[uint][]uint)
+	for id, parent := range parents {
+		if parent != nil {
+			children[*parent] = append(children[*parent], id)
+		}
+	}
+	for _, id := range changed {
+		limit := repository.MaxCategoryDepth - depths[id]
+		if subtreeHeight(children, id, limit) > limit {
+			return utils.NewParamError(fmt.Sprintf("分类层级不能超过 %d 层", repository.MaxCategoryDepth))
+		}
+	}
+	return nil
+}
+
+// subtreeHeight 分类子树的高度（叶子为 0），超过 limit 后不再继续展开
+func subtreeHeight(children map[uint][]uint, id uint, limit int) int {
+	height := 0
+	for _, child := range children[id] {
+		if height > limit {
+			break
+		}
+		height = max(height, subtreeHeight(children, child, limit-1)+1)
+	}
+	return height
+}
+
+// categoryParents 分类的父分类映射
+func categoryParents(categories []models.Category) map[uint]*uint {
+	parents := make(map[uint]*uint, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+	return parents
+}
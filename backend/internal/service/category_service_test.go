@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"eight-gu-learning-platform/internal/repository"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	// 先序：后端(1) > Go(2) > 并发(3)，后端(1) > 数据库(4)；网络(5)
+	rows := []repository.CategoryTreeRow{
+		{ID: 1, Name: "后端", Depth: 0, KnowledgeCount: 1},
+		{ID: 2, Name: "Go", ParentID: uintPtr(1), Depth: 1, KnowledgeCount: 4, CompletedCount: 2},
+		{ID: 3, Name: "并发", ParentID: uintPtr(2), Depth: 2, KnowledgeCount: 3, CompletedCount: 3},
+		{ID: 4, Name: "数据库", ParentID: uintPtr(1), Depth: 1, KnowledgeCount: 2},
+		{ID: 5, Name: "网络", ParentID: uintPtr(99), Depth: 0}, // 父分类已删除
+	}
+
+	roots, nodes := buildCategoryTree(rows, true)
+	if len(roots) != 2 || roots[0].ID != 1 || roots[1].ID != 5 {
+		t.Fatalf("Unexpected roots: %+v", roots)
+	}
+	if len(roots[0].Children) != 2 || roots[0].Children[0].ID != 2 || roots[0].Children[0].Children[0].ID != 3 {
+		t.Errorf("Unexpected children: %+v", roots[0].Children)
+	}
+
+	root := nodes[1]
+	if root.KnowledgeCount != 10 || root.DirectKnowledgeCount != 1 {
+		t.Errorf("Expected 10 knowledge points (1 direct), got %d (%d)", root.KnowledgeCount, root.DirectKnowledgeCount)
+	}
+	if *root.CompletedCount != 5 || *root.CompletionRate != 50 {
+		t.Errorf("Expected 5 completed (50%%), got %d (%v%%)", *root.CompletedCount, *root.CompletionRate)
+	}
+	if goNode := nodes[2]; goNode.KnowledgeCount != 7 || *goNode.CompletionRate != 71.4 {
+		t.Errorf("Unexpected Go node: %d, %v", goNode.KnowledgeCount, *goNode.CompletionRate)
+	}
+	if *nodes[5].CompletionRate != 0 {
+		t.Errorf("Expected 0%% for empty category, got %v", *nodes[5].CompletionRate)
+	}
+
+	roots, _ = buildCategoryTree(rows, false)
+	if roots[0].CompletedCount != nil || roots[0].CompletionRate != nil {
+		t.Error("Expected no progress without user")
+	}
+}
+
+func TestCheckCategoryParents(t *testing.T) {
+	base := func() map[uint]*uint {
+		return map[uint]*uint{1: nil, 2: uintPtr(1), 3: uintPtr(2), 4: nil}
+	}
+
+	tests := []struct {
+		name  string
+		moves map[uint]*uint
+		valid bool
+	}{
+		{"move to sibling root", map[uint]*uint{3: uintPtr(4)}, true},
+		{"move to root", map[uint]*uint{2: nil}, true},
+		{"self", map[uint]*uint{1: uintPtr(1)}, false},
+		{"under descendant", map[uint]*uint{1: uintPtr(3)}, false},
+		{"swap", map[uint]*uint{1: uintPtr(4), 4: uintPtr(1)}, false},
+		{"swap after detach", map[uint]*uint{2: nil, 1: uintPtr(3)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parents := base()
+			var changed []uint
+			for id, parent := range tt.moves {
+				parents[id] = parent
+				changed = append(changed, id)
+			}
+			if err := checkCategoryParents(parents, changed); (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestCheckCategoryParentsDepth(t *testing.T) {
+	// 1 为根、深度 repository.MaxCategoryDepth 的链，100 -> 101 为另一棵两层的树
+	base := func() map[uint]*uint {
+		parents := map[uint]*uint{1: nil, 100: nil, 101: uintPtr(100)}
+		for id := uint(2); id <= repository.MaxCategoryDepth+1; id++ {
+			parents[id] = uintPtr(id - 1)
+		}
+		return parents
+	}
+	deepest := uint(repository.MaxCategoryDepth + 1)
+
+	tests := []struct {
+		name   string
+		id     uint
+		parent uint
+		valid  bool
+	}{
+		{"subtree fits", 100, deepest - 2, true},
+		{"subtree exceeds limit", 100, deepest - 1, false},
+		{"leaf exceeds limit", 101, deepest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parents := base()
+			parents[tt.id] = uintPtr(tt.parent)
+			if err := checkCategoryParents(parents, []uint{tt.id}); (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,559 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// ContentService 内容管理服务（后台）
+type ContentService struct {
+	categoryRepo  *repository.CategoryRepository
+	knowledgeRepo *repository.KnowledgeRepository
+	relationRepo  *repository.RelationRepository
+	exerciseRepo  *repository.ExerciseRepository
+	cache         *cache.ReadThrough
+}
+
+// NewContentService 创建内容管理服务
+func NewContentService(
+	categoryRepo *repository.CategoryRepository,
+	knowledgeRepo *repository.KnowledgeRepository,
+	relationRepo *repository.RelationRepository,
+	exerciseRepo *repository.ExerciseRepository,
+	rt *cache.ReadThrough,
+) *ContentService {
+	return &ContentService{
+		categoryRepo:  categoryRepo,
+		knowledgeRepo: knowledgeRepo,
+		relationRepo:  relationRepo,
+		exerciseRepo:  exerciseRepo,
+		cache:         rt,
+	}
+}
+
+// invalidate 内容变更后清除相关缓存，失败时仅记录日志（缓存会在 TTL 后自然过期）
+func (s *ContentService) invalidate(namespaces ...string) {
+	patterns := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		patterns = append(patterns, ns+":*")
+	}
+	if err := s.cache.Invalidate(patterns...); err != nil {
+		log.Printf("Failed to invalidate cache %v: %v", namespaces, err)
+	}
+}
+
+// CategoryRequest 分类创建/更新请求
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"` // 为空时自动生成（更新时保持不变）
+	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// KnowledgeRequest 知识点创建/更新请求
+type KnowledgeRequest struct {
+	Title       string   `json:"title" binding:"required,max=255"`
+	Slug        string   `json:"slug" binding:"omitempty,max=100"`
+	Description string   `json:"description"`
+	Content     string   `json:"content"`
+	CategoryID  uint     `json:"category_id" binding:"required"`
+	Difficulty  string   `json:"difficulty" binding:"required,oneof=easy medium hard"`
+	Frequency   string   `json:"frequency" binding:"required,oneof=high medium low"`
+	CodeExample string   `json:"code_example"`
+	References  []string `json:"references"`
+}
+
+// RelationRequest 知识关联创建/更新请求
+type RelationRequest struct {
+	FromPointID  uint   `json:"from_point_id" binding:"required"`
+	ToPointID    uint   `json:"to_point_id" binding:"required"`
+	RelationType string `json:"relation_type" binding:"required,oneof=prerequisite related extended"`
+}
+
+// ExerciseRequest 练习题创建/更新请求
+type ExerciseRequest struct {
+	Slug             string   `json:"slug" binding:"omitempty,max=100"`
+	KnowledgePointID uint     `json:"knowledge_point_id" binding:"required"`
+	Question         string   `json:"question" binding:"required"`
+	Options          []string `json:"options" binding:"omitempty,dive,required"` // 判断题、填空题可省略
+	Answer           []string `json:"answer" binding:"required,min=1,dive,required"`
+	Type             string   `json:"type" binding:"required,oneof=single_choice multiple_choice true_false fill_blank ordering code"`
+	Explanation      string   `json:"explanation"`
+	StarterCode      string   `json:"starter_code"` // 代码题的初始代码
+	TestCode         string   `json:"test_code"`    // 代码题的隐藏测试（package solution）
+	Difficulty       string   `json:"difficulty" binding:"required,oneof=easy medium hard"`
+}
+
+// RelationListRequest 知识关联列表请求
+type RelationListRequest struct {
+	FromPointID  uint   `form:"from_point_id"`
+	ToPointID    uint   `form:"to_point_id"`
+	RelationType string `form:"relation_type"`
+}
+
+// ==================== 分类 ====================
+
+// CreateCategory 创建分类
+func (s *ContentService) CreateCategory(req *CategoryRequest) (*models.Category, error) {
+	if err := s.validateParent(0, req.ParentID); err != nil {
+		return nil, err
+	}
+	slug, err := resolveSlug(req.Slug, "", "category", 0, s.categoryRepo.SlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &models.Category{
+		Slug:        slug,
+		Name:        req.Name,
+		Description: req.Description,
+		ParentID:    req.ParentID,
+		SortOrder:   req.SortOrder,
+	}
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	s.invalidate(categoryCacheNS)
+	return category, nil
+}
+
+// UpdateCategory 更新分类
+func (s *ContentService) UpdateCategory(id uint, req *CategoryRequest) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateParent(id, req.ParentID); err != nil {
+		return nil, err
+	}
+	if err := s.checkMoves(map[uint]*uint{id: req.ParentID}); err != nil {
+		return nil, err
+	}
+	slug, err := resolveSlug(req.Slug, category.Slug, "category", id, s.categoryRepo.SlugExists)
+	if err != nil {
+		return nil, err
+	}
+
+	category.Slug = slug
+	category.Name = req.Name
+	category.Description = req.Description
+	category.ParentID = req.ParentID
+	category.SortOrder = req.SortOrder
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+	s.invalidate(categoryCacheNS, knowledgeCacheNS, graphCacheNS)
+	return category, nil
+}
+
+// DeleteCategory 删除分类（存在子分类或知识点时拒绝删除）
+func (s *ContentService) DeleteCategory(id uint) error {
+	if _, err := s.categoryRepo.GetByID(id); err != nil {
+		return err
+	}
+
+	children, knowledges, err := s.categoryRepo.CountDependents(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 || knowledges > 0 {
+		return utils.NewConflictError("分类下仍有子分类或知识点，无法删除")
+	}
+
+	if err := s.categoryRepo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate(categoryCacheNS)
+	return nil
+}
+
+// RestoreCategory 恢复已删除的分类
+func (s *ContentService) RestoreCategory(id uint) (*models.Category, error) {
+	if err := s.categoryRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	s.invalidate(categoryCacheNS)
+	return s.categoryRepo.GetByID(id)
+}
+
+// CategoryOrderRequest 分类批量移动/排序请求
+type CategoryOrderRequest struct {
+	Items []repository.CategoryOrder `json:"items" binding:"required,min=1,dive"`
+}
+
+// ReorderCategories 批量修改分类的父分类与排序，修改后的分类树不能有环
+func (s *ContentService) ReorderCategories(req *CategoryOrderRequest) error {
+	moves := make(map[uint]*uint, len(req.Items))
+	for _, item := range req.Items {
+		if _, ok := moves[item.ID]; ok {
+			return utils.NewParamError(fmt.Sprintf("分类 %d 重复", item.ID))
+		}
+		if err := s.validateParent(item.ID, item.ParentID); err != nil {
+			return err
+		}
+		moves[item.ID] = item.ParentID
+	}
+	if err := s.checkMoves(moves); err != nil {
+		return err
+	}
+
+	if err := s.categoryRepo.UpdateOrder(req.Items); err != nil {
+		return err
+	}
+	s.invalidate(categoryCacheNS, knowledgeCacheNS, graphCacheNS)
+	return nil
+}
+
+// checkMoves 校验修改父分类后的分类树没有环
+func (s *ContentService) checkMoves(moves map[uint]*uint) error {
+	categories, err := s.categoryRepo.List()
+	if err != nil {
+		return err
+	}
+	parents := categoryParents(categories)
+	changed := make([]uint, 0, len(moves))
+	for id, parentID := range moves {
+		if _, ok := parents[id]; !ok {
+			return utils.ErrCategoryNotFound
+		}
+		parents[id] = parentID
+		changed = append(changed, id)
+	}
+	return checkCategoryParents(parents, changed)
+}
+
+// validateParent 校验父分类存在且不是自身
+func (s *ContentService) validateParent(id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return utils.NewParamError("父分类不能是自身")
+	}
+	if _, err := s.categoryRepo.GetByID(*parentID); err != nil {
+		if err == utils.ErrCategoryNotFound {
+			return utils.NewParamError("父分类不存在")
+		}
+		return err
+	}
+	return nil
+}
+
+// ==================== 知识点 ====================
+
+// GetKnowledge 获取知识点详情
+func (s *ContentService) GetKnowledge(id uint) (*models.KnowledgePoint, error) {
+	return s.knowledgeRepo.GetByID(id)
+}
+
+// CreateKnowledge 创建知识点
+func (s *ContentService) CreateKnowledge(req *KnowledgeRequest) (*models.KnowledgePoint, error) {
+	knowledge := &models.KnowledgePoint{}
+	if err := s.fillKnowledge(knowledge, req); err != nil {
+		return nil, err
+	}
+	if err := s.knowledgeRepo.Create(knowledge); err != nil {
+		return nil, err
+	}
+	s.invalidate(graphCacheNS)
+	return s.knowledgeRepo.GetByID(knowledge.ID)
+}
+
+// UpdateKnowledge 更新知识点
+func (s *ContentService) UpdateKnowledge(id uint, req *KnowledgeRequest) (*models.KnowledgePoint, error) {
+	knowledge, err := s.knowledgeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillKnowledge(knowledge, req); err != nil {
+		return nil, err
+	}
+	if err := s.knowledgeRepo.Update(knowledge); err != nil {
+		return nil, err
+	}
+	s.invalidate(knowledgeCacheNS, graphCacheNS)
+	return s.knowledgeRepo.GetByID(id)
+}
+
+// DeleteKnowledge 删除知识点
+func (s *ContentService) DeleteKnowledge(id uint) error {
+	if _, err := s.knowledgeRepo.GetByID(id); err != nil {
+		return err
+	}
+	if err := s.knowledgeRepo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate(knowledgeCacheNS, graphCacheNS)
+	return nil
+}
+
+// RestoreKnowledge 恢复已删除的知识点
+func (s *ContentService) RestoreKnowledge(id uint) (*models.KnowledgePoint, error) {
+	if err := s.knowledgeRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	s.invalidate(graphCacheNS)
+	return s.knowledgeRepo.GetByID(id)
+}
+
+// fillKnowledge 校验请求并填充知识点字段
+func (s *ContentService) fillKnowledge(knowledge *models.KnowledgePoint, req *KnowledgeRequest) error {
+	if _, err := s.categoryRepo.GetByID(req.CategoryID); err != nil {
+		if err == utils.ErrCategoryNotFound {
+			return utils.NewParamError("分类不存在")
+		}
+		return err
+	}
+
+	references := req.References
+	if references == nil {
+		references = []string{}
+	}
+	referencesJSON, err := json.Marshal(references)
+	if err != nil {
+		return err
+	}
+	slug, err := resolveSlug(req.Slug, knowledge.Slug, "knowledge", knowledge.ID, s.knowledgeRepo.SlugExists)
+	if err != nil {
+		return err
+	}
+
+	knowledge.Slug = slug
+	knowledge.Title = req.Title
+	knowledge.Description = req.Description
+	knowledge.Content = req.Content
+	knowledge.CategoryID = req.CategoryID
+	knowledge.Category = models.Category{}
+	knowledge.Difficulty = req.Difficulty
+	knowledge.Frequency = req.Frequency
+	knowledge.CodeExample = req.CodeExample
+	knowledge.References = string(referencesJSON)
+	return nil
+}
+
+// ==================== 知识关联 ====================
+
+// ListRelations 获取知识关联列表
+func (s *ContentService) ListRelations(req *RelationListRequest) ([]models.KnowledgeRelation, error) {
+	return s.relationRepo.List(req.FromPointID, req.ToPointID, req.RelationType)
+}
+
+// CreateRelation 创建知识关联
+func (s *ContentService) CreateRelation(req *RelationRequest) (*models.KnowledgeRelation, error) {
+	if err := s.validateRelation(0, req); err != nil {
+		return nil, err
+	}
+
+	relation := &models.KnowledgeRelation{
+		FromPointID:  req.FromPointID,
+		ToPointID:    req.ToPointID,
+		RelationType: req.RelationType,
+	}
+	if err := s.relationRepo.Create(relation); err != nil {
+		return nil, err
+	}
+	s.invalidate(graphCacheNS)
+	return relation, nil
+}
+
+// UpdateRelation 更新知识关联
+func (s *ContentService) UpdateRelation(id uint, req *RelationRequest) (*models.KnowledgeRelation, error) {
+	relation, err := s.relationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateRelation(id, req); err != nil {
+		return nil, err
+	}
+
+	relation.FromPointID = req.FromPointID
+	relation.ToPointID = req.ToPointID
+	relation.RelationType = req.RelationType
+	if err := s.relationRepo.Update(relation); err != nil {
+		return nil, err
+	}
+	s.invalidate(graphCacheNS)
+	return relation, nil
+}
+
+// DeleteRelation 删除知识关联
+func (s *ContentService) DeleteRelation(id uint) error {
+	if _, err := s.relationRepo.GetByID(id); err != nil {
+		return err
+	}
+	if err := s.relationRepo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate(graphCacheNS)
+	return nil
+}
+
+// RestoreRelation 恢复已删除的知识关联
+func (s *ContentService) RestoreRelation(id uint) (*models.KnowledgeRelation, error) {
+	if err := s.relationRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	s.invalidate(graphCacheNS)
+	return s.relationRepo.GetByID(id)
+}
+
+// validateRelation 校验关联两端知识点存在且关联不重复
+func (s *ContentService) validateRelation(id uint, req *RelationRequest) error {
+	if req.FromPointID == req.ToPointID {
+		return utils.NewParamError("不能关联知识点自身")
+	}
+	for _, pointID := range []uint{req.FromPointID, req.ToPointID} {
+		if err := s.requireKnowledge(pointID); err != nil {
+			return err
+		}
+	}
+
+	exists, err := s.relationRepo.Exists(req.FromPointID, req.ToPointID, req.RelationType, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return utils.NewConflictError("知识关联已存在")
+	}
+	return nil
+}
+
+// ==================== 练习题 ====================
+
+// ListExercises 获取练习题列表（包含答案）
+func (s *ContentService) ListExercises(req *ExerciseListRequest) ([]models.Exercise, int64, error) {
+	offset := (req.Page - 1) * req.PageSize
+	return s.exerciseRepo.List(offset, req.PageSize, req.KnowledgeID, req.Difficulty)
+}
+
+// GetExercise 获取练习题详情（包含答案）
+func (s *ContentService) GetExercise(id uint) (*models.Exercise, error) {
+	return s.exerciseRepo.GetByID(id)
+}
+
+// CreateExercise 创建练习题
+func (s *ContentService) CreateExercise(req *ExerciseRequest) (*models.Exercise, error) {
+	exercise := &models.Exercise{}
+	if err := s.fillExercise(exercise, req); err != nil {
+		return nil, err
+	}
+	if err := s.exerciseRepo.Create(exercise); err != nil {
+		return nil, err
+	}
+	return exercise, nil
+}
+
+// UpdateExercise 更新练习题
+func (s *ContentService) UpdateExercise(id uint, req *ExerciseRequest) (*models.Exercise, error) {
+	exercise, err := s.exerciseRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillExercise(exercise, req); err != nil {
+		return nil, err
+	}
+	if err := s.exerciseRepo.Update(exercise); err != nil {
+		return nil, err
+	}
+	return exercise, nil
+}
+
+// DeleteExercise 删除练习题
+func (s *ContentService) DeleteExercise(id uint) error {
+	if _, err := s.exerciseRepo.GetByID(id); err != nil {
+		return err
+	}
+	return s.exerciseRepo.Delete(id)
+}
+
+// RestoreExercise 恢复已删除的练习题
+func (s *ContentService) RestoreExercise(id uint) (*models.Exercise, error) {
+	if err := s.exerciseRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	return s.exerciseRepo.GetByID(id)
+}
+
+// fillExercise 校验请求并填充练习题字段
+func (s *ContentService) fillExercise(exercise *models.Exercise, req *ExerciseRequest) error {
+	if err := s.requireKnowledge(req.KnowledgePointID); err != nil {
+		return err
+	}
+	if err := ValidateExercise(req.Type, req.Options, req.Answer); err != nil {
+		return err
+	}
+	if err := ValidateTestCode(req.Type, req.TestCode); err != nil {
+		return err
+	}
+
+	optionsJSON, err := encodeStrings(req.Options)
+	if err != nil {
+		return err
+	}
+	answerJSON, err := encodeStrings(req.Answer)
+	if err != nil {
+		return err
+	}
+	slug, err := resolveSlug(req.Slug, exercise.Slug, "exercise", exercise.ID, s.exerciseRepo.SlugExists)
+	if err != nil {
+		return err
+	}
+
+	exercise.Slug = slug
+	exercise.KnowledgePointID = req.KnowledgePointID
+	exercise.Question = req.Question
+	exercise.Options = optionsJSON
+	exercise.Answer = answerJSON
+	exercise.Type = req.Type
+	exercise.Explanation = req.Explanation
+	exercise.StarterCode = req.StarterCode
+	exercise.TestCode = req.TestCode
+	exercise.Difficulty = req.Difficulty
+	return nil
+}
+
+// requireKnowledge 校验知识点存在
+func (s *ContentService) requireKnowledge(id uint) error {
+	if _, err := s.knowledgeRepo.GetByID(id); err != nil {
+		if err == utils.ErrKnowledgeNotFound {
+			return utils.NewParamError("知识点不存在")
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveSlug 校验请求中的 slug；未指定时保留原值，新建时自动生成
+func resolveSlug(requested, current, prefix string, id uint, exists func(slug string, excludeID uint) (bool, error)) (string, error) {
+	if requested == "" {
+		if current != "" {
+			return current, nil
+		}
+		return prefix + "-" + strings.ReplaceAll(uuid.New().String(), "-", "")[:12], nil
+	}
+	if !slugPattern.MatchString(requested) {
+		return "", utils.NewParamError("slug 只能包含小写字母、数字和短横线")
+	}
+	if requested == current {
+		return current, nil
+	}
+
+	taken, err := exists(requested, id)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", utils.NewConflictError("slug 已被使用")
+	}
+	return requested, nil
+}
@@ -0,0 +1,751 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+
+	"go.yaml.in/yaml/v3"
+	"gorm.io/gorm"
+)
+
+// ContentFormatVersion 导入导出格式版本
+const ContentFormatVersion = 1
+
+// 导入导出格式
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// 变更类型
+const (
+	ChangeCreate  = "create"
+	ChangeUpdate  = "update"
+	ChangeRestore = "restore"
+)
+
+// slugPattern slug 格式：小写字母、数字、短横线
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// errDryRun 试运行结束时回滚事务
+var errDryRun = errors.New("dry run")
+
+// ContentBundle 导入导出的内容包，格式说明见 docs/content-format.md
+type ContentBundle struct {
+	Version    int             `json:"version" yaml:"version"`
+	Categories []CategoryItem  `json:"categories,omitempty" yaml:"categories,omitempty"`
+	Knowledge  []KnowledgeItem `json:"knowledge_points,omitempty" yaml:"knowledge_points,omitempty"`
+	Relations  []RelationItem  `json:"relations,omitempty" yaml:"relations,omitempty"`
+	Exercises  []ExerciseItem  `json:"exercises,omitempty" yaml:"exercises,omitempty"`
+}
+
+// CategoryItem 分类
+type CategoryItem struct {
+	Slug        string `json:"slug" yaml:"slug"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Parent      string `json:"parent,omitempty" yaml:"parent,omitempty"` // 父分类 slug
+	SortOrder   int    `json:"sort_order,omitempty" yaml:"sort_order,omitempty"`
+}
+
+// KnowledgeItem 知识点
+type KnowledgeItem struct {
+	Slug        string   `json:"slug" yaml:"slug"`
+	Title       string   `json:"title" yaml:"title"`
+	Category    string   `json:"category" yaml:"category"` // 分类 slug
+	Difficulty  string   `json:"difficulty" yaml:"difficulty"`
+	Frequency   string   `json:"frequency" yaml:"frequency"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Content     string   `json:"content,omitempty" yaml:"content,omitempty"` // Markdown
+	CodeExample string   `json:"code_example,omitempty" yaml:"code_example,omitempty"`
+	References  []string `json:"references,omitempty" yaml:"references,omitempty"`
+}
+
+// RelationItem 知识关联
+type RelationItem struct {
+	From string `json:"from" yaml:"from"` // 知识点 slug
+	To   string `json:"to" yaml:"to"`     // 知识点 slug
+	Type string `json:"type" yaml:"type"`
+}
+
+// ExerciseItem 练习题
+type ExerciseItem struct {
+	Slug           string   `json:"slug" yaml:"slug"`
+	KnowledgePoint string   `json:"knowledge_point" yaml:"knowledge_point"` // 知识点 slug
+	Type           string   `json:"type" yaml:"type"`
+	Difficulty     string   `json:"difficulty" yaml:"difficulty"`
+	Question       string   `json:"question" yaml:"question"`
+	Options        []string `json:"options" yaml:"options"`
+	Answer         []string `json:"answer" yaml:"answer"`
+	Explanation    string   `json:"explanation,omitempty" yaml:"explanation,omitempty"`
+	StarterCode    string   `json:"starter_code,omitempty" yaml:"starter_code,omitempty"` // 代码题
+	TestCode       string   `json:"test_code,omitempty" yaml:"test_code,omitempty"`       // 代码题
+}
+
+// ContentChange 一条内容变更
+type ContentChange struct {
+	Kind   string   `json:"kind"` // category, knowledge, relation, exercise
+	Key    string   `json:"key"`  // slug，关联为 from->to:type
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"` // 更新的字段
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Created   int             `json:"created"`
+	Updated   int             `json:"updated"`
+	Restored  int             `json:"restored"`
+	Unchanged int             `json:"unchanged"`
+	Changes   []ContentChange `json:"changes"`
+}
+
+// ContentValidationError 导入内容校验失败
+type ContentValidationError struct {
+	Problems []string
+}
+
+func (e *ContentValidationError) Error() string {
+	return "导入内容校验失败: " + strings.Join(e.Problems, "; ")
+}
+
+// DecodeContentBundle 解析 YAML 或 JSON 格式的内容包
+func DecodeContentBundle(data []byte, format string) (*ContentBundle, error) {
+	var bundle ContentBundle
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &bundle)
+	case FormatYAML, "":
+		err = yaml.Unmarshal(data, &bundle)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	if err != nil {
+		return nil, &ContentValidationError{Problems: []string{"无法解析内容: " + err.Error()}}
+	}
+	if bundle.Version != ContentFormatVersion {
+		return nil, &ContentValidationError{Problems: []string{
+			fmt.Sprintf("不支持的格式版本 %d（当前为 %d）", bundle.Version, ContentFormatVersion),
+		}}
+	}
+	return &bundle, nil
+}
+
+// EncodeContentBundle 将内容包编码为 YAML 或 JSON
+func EncodeContentBundle(bundle *ContentBundle, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(bundle, "", "  ")
+	case FormatYAML, "":
+		return yaml.Marshal(bundle)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// ContentTransferService 内容批量导入导出服务
+type ContentTransferService struct {
+	contentRepo *repository.ContentRepository
+	cache       *cache.ReadThrough
+}
+
+// NewContentTransferService 创建内容导入导出服务
+func NewContentTransferService(contentRepo *repository.ContentRepository, rt *cache.ReadThrough) *ContentTransferService {
+	return &ContentTransferService{
+		contentRepo: contentRepo,
+		cache:       rt,
+	}
+}
+
+// Export 导出全部未删除的内容；引用了已删除内容的记录不导出
+func (s *ContentTransferService) Export() (*ContentBundle, error) {
+	snapshot, err := s.contentRepo.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &ContentBundle{Version: ContentFormatVersion}
+
+	categorySlugs := make(map[uint]string, len(snapshot.Categories))
+	for _, c := range snapshot.Categories {
+		categorySlugs[c.ID] = c.Slug
+	}
+	for _, c := range snapshot.Categories {
+		item := CategoryItem{
+			Slug:        c.Slug,
+			Name:        c.Name,
+			Description: c.Description,
+			SortOrder:   c.SortOrder,
+		}
+		if c.ParentID != nil {
+			item.Parent = categorySlugs[*c.ParentID]
+		}
+		bundle.Categories = append(bundle.Categories, item)
+	}
+
+	knowledgeSlugs := make(map[uint]string, len(snapshot.Knowledge))
+	for _, k := range snapshot.Knowledge {
+		category, ok := categorySlugs[k.CategoryID]
+		if !ok {
+			continue
+		}
+		knowledgeSlugs[k.ID] = k.Slug
+		bundle.Knowledge = append(bundle.Knowledge, KnowledgeItem{
+			Slug:        k.Slug,
+			Title:       k.Title,
+			Category:    category,
+			Difficulty:  k.Difficulty,
+			Frequency:   k.Frequency,
+			Description: k.Description,
+			Content:     k.Content,
+			CodeExample: k.CodeExample,
+			References:  decodeStrings(k.References),
+		})
+	}
+
+	for _, r := range snapshot.Relations {
+		from, okFrom := knowledgeSlugs[r.FromPointID]
+		to, okTo := knowledgeSlugs[r.ToPointID]
+		if !okFrom || !okTo {
+			continue
+		}
+		bundle.Relations = append(bundle.Relations, RelationItem{From: from, To: to, Type: r.RelationType})
+	}
+
+	for _, e := range snapshot.Exercises {
+		knowledge, ok := knowledgeSlugs[e.KnowledgePointID]
+		if !ok {
+			continue
+		}
+		bundle.Exercises = append(bundle.Exercises, ExerciseItem{
+			Slug:           e.Slug,
+			KnowledgePoint: knowledge,
+			Type:           e.Type,
+			Difficulty:     e.Difficulty,
+			Question:       e.Question,
+			Options:        decodeStrings(e.Options),
+			Answer:         decodeStrings(e.Answer),
+			Explanation:    e.Explanation,
+			StarterCode:    e.StarterCode,
+			TestCode:       e.TestCode,
+		})
+	}
+
+	return bundle, nil
+}
+
+// Import 按 slug 幂等导入内容：不存在则创建，已存在则更新，已删除则恢复；
+// 不在内容包中的已有内容保持不变。整个导入在一个事务中执行，dryRun 时只返回变更而不提交
+func (s *ContentTransferService) Import(bundle *ContentBundle, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{DryRun: dryRun, Changes: []ContentChange{}}
+
+	err := s.contentRepo.Transaction(func(repo *repository.ContentRepository) error {
+		snapshot, err := repo.Snapshot()
+		if err != nil {
+			return err
+		}
+		if problems := validateContentBundle(bundle, snapshot); len(problems) > 0 {
+			return &ContentValidationError{Problems: problems}
+		}
+
+		imp := newContentImporter(repo, snapshot, result)
+		if err := imp.importCategories(bundle.Categories); err != nil {
+			return err
+		}
+		if err := imp.importKnowledge(bundle.Knowledge); err != nil {
+			return err
+		}
+		if err := imp.importRelations(bundle.Relations); err != nil {
+			return err
+		}
+		if err := imp.importExercises(bundle.Exercises); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && err != errDryRun {
+		return nil, err
+	}
+
+	if !dryRun && len(result.Changes) > 0 {
+		if err := s.cache.Invalidate(categoryCacheNS+":*", knowledgeCacheNS+":*", graphCacheNS+":*"); err != nil {
+			log.Printf("Failed to invalidate cache after import: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// validateContentBundle 校验内容包：必填字段、取值范围、slug 唯一，以及引用的分类和知识点
+// 存在于内容包或数据库中
+func validateContentBundle(bundle *ContentBundle, snapshot *repository.ContentSnapshot) []string {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// 数据库中已有的 slug
+	categoryParents := make(map[string]string)
+	categorySlugByID := make(map[uint]string)
+	for _, c := range snapshot.Categories {
+		categorySlugByID[c.ID] = c.Slug
+	}
+	for _, c := range snapshot.Categories {
+		parent := ""
+		if c.ParentID != nil {
+			parent = categorySlugByID[*c.ParentID]
+		}
+		categoryParents[c.Slug] = parent
+	}
+	knowledgeSlugs := make(map[string]bool)
+	for _, k := range snapshot.Knowledge {
+		knowledgeSlugs[k.Slug] = true
+	}
+
+	// 分类
+	seen := make(map[string]bool)
+	for i, c := range bundle.Categories {
+		where := fmt.Sprintf("categories[%d]", i)
+		checkSlug(c.Slug, where, seen, addf)
+		if c.Name == "" {
+			addf("%s: name 不能为空", where)
+		}
+		if c.Parent == c.Slug && c.Slug != "" {
+			addf("%s: 父分类不能是自身", where)
+		}
+		categoryParents[c.Slug] = c.Parent
+	}
+	for i, c := range bundle.Categories {
+		if c.Parent == "" {
+			continue
+		}
+		if _, ok := categoryParents[c.Parent]; !ok {
+			addf("categories[%d]: 父分类 %q 不存在", i, c.Parent)
+			continue
+		}
+		if hasParentCycle(c.Slug, categoryParents) {
+			addf("categories[%d]: 分类 %q 的父分类形成循环", i, c.Slug)
+		}
+	}
+	// 层级上限与在线修改分类相同；内容包可能移动已有分类，其下的子分类也一并检查
+	for i, c := range bundle.Categories {
+		if !hasParentCycle(c.Slug, categoryParents) && categoryDepth(c.Slug, categoryParents) > repository.MaxCategoryDepth {
+			addf("categories[%d]: 分类 %q 的层级超过 %d 层", i, c.Slug, repository.MaxCategoryDepth)
+		}
+	}
+	inBundle := make(map[string]bool, len(bundle.Categories))
+	for _, c := range bundle.Categories {
+		inBundle[c.Slug] = true
+	}
+	for _, c := range snapshot.Categories {
+		if !inBundle[c.Slug] && !hasParentCycle(c.Slug, categoryParents) && categoryDepth(c.Slug, categoryParents) > repository.MaxCategoryDepth {
+			addf("已有分类 %q 随父分类移动后层级超过 %d 层", c.Slug, repository.MaxCategoryDepth)
+		}
+	}
+
+	// 知识点
+	seen = make(map[string]bool)
+	for i, k := range bundle.Knowledge {
+		where := fmt.Sprintf("knowledge_points[%d]", i)
+		checkSlug(k.Slug, where, seen, addf)
+		if k.Title == "" {
+			addf("%s: title 不能为空", where)
+		}
+		if _, ok := categoryParents[k.Category]; !ok {
+			addf("%s: 分类 %q 不存在", where, k.Category)
+		}
+		if !oneOf(k.Difficulty, "easy", "medium", "hard") {
+			addf("%s: difficulty 必须是 easy/medium/hard", where)
+		}
+		if !oneOf(k.Frequency, "high", "medium", "low") {
+			addf("%s: frequency 必须是 high/medium/low", where)
+		}
+		knowledgeSlugs[k.Slug] = true
+	}
+
+	// 知识关联
+	for i, r := range bundle.Relations {
+		where := fmt.Sprintf("relations[%d]", i)
+		for _, slug := range []string{r.From, r.To} {
+			if !knowledgeSlugs[slug] {
+				addf("%s: 知识点 %q 不存在", where, slug)
+			}
+		}
+		if r.From == r.To {
+			addf("%s: 不能关联知识点自身", where)
+		}
+		if !oneOf(r.Type, "prerequisite", "related", "extended") {
+			addf("%s: type 必须是 prerequisite/related/extended", where)
+		}
+	}
+
+	// 练习题
+	seen = make(map[string]bool)
+	for i, e := range bundle.Exercises {
+		where := fmt.Sprintf("exercises[%d]", i)
+		checkSlug(e.Slug, where, seen, addf)
+		if !knowledgeSlugs[e.KnowledgePoint] {
+			addf("%s: 知识点 %q 不存在", where, e.KnowledgePoint)
+		}
+		if e.Question == "" {
+			addf("%s: question 不能为空", where)
+		}
+		if !oneOf(e.Difficulty, "easy", "medium", "hard") {
+			addf("%s: difficulty 必须是 easy/medium/hard", where)
+		}
+		if !oneOf(e.Type, ExerciseTypes()...) {
+			addf("%s: type 必须是 %s", where, strings.Join(ExerciseTypes(), "/"))
+		} else if err := ValidateExercise(e.Type, e.Options, e.Answer); err != nil {
+			addf("%s: %s", where, err.Error())
+		} else if err := ValidateTestCode(e.Type, e.TestCode); err != nil {
+			addf("%s: %s", where, err.Error())
+		}
+	}
+
+	return problems
+}
+
+// checkSlug 校验 slug 格式且在同类内容中唯一
+func checkSlug(slug, where string, seen map[string]bool, addf func(string, ...interface{})) {
+	if !slugPattern.MatchString(slug) || len(slug) > 100 {
+		addf("%s: slug %q 格式无效（小写字母、数字和短横线）", where, slug)
+		return
+	}
+	if seen[slug] {
+		addf("%s: slug %q 重复", where, slug)
+	}
+	seen[slug] = true
+}
+
+// hasParentCycle 检查从 slug 出发沿父分类向上是否回到自身
+func hasParentCycle(slug string, parents map[string]string) bool {
+	visited := map[string]bool{slug: true}
+	for current := parents[slug]; current != ""; current = parents[current] {
+		if visited[current] {
+			return true
+		}
+		visited[current] = true
+	}
+	return false
+}
+
+// categoryDepth 分类的层级（根分类为 0），调用前需确认没有循环
+func categoryDepth(slug string, parents map[string]string) int {
+	depth := 0
+	for current := parents[slug]; current != ""; current = parents[current] {
+		depth++
+	}
+	return depth
+}
+
+func oneOf(value string, options ...string) bool {
+	return slices.Contains(options, value)
+}
+
+// contentImporter 在事务中执行导入并记录变更
+type contentImporter struct {
+	repo         *repository.ContentRepository
+	result       *ImportResult
+	categoryIDs  map[string]uint
+	knowledgeIDs map[string]uint
+}
+
+func newContentImporter(repo *repository.ContentRepository, snapshot *repository.ContentSnapshot, result *ImportResult) *contentImporter {
+	imp := &contentImporter{
+		repo:         repo,
+		result:       result,
+		categoryIDs:  make(map[string]uint),
+		knowledgeIDs: make(map[string]uint),
+	}
+	for _, c := range snapshot.Categories {
+		imp.categoryIDs[c.Slug] = c.ID
+	}
+	for _, k := range snapshot.Knowledge {
+		imp.knowledgeIDs[k.Slug] = k.ID
+	}
+	return imp
+}
+
+// record 记录一条变更
+func (imp *contentImporter) record(kind, key string, deleted bool, isNew bool, fields []string) {
+	var action string
+	switch {
+	case isNew:
+		action = ChangeCreate
+		imp.result.Created++
+	case deleted:
+		action = ChangeRestore
+		imp.result.Restored++
+	case len(fields) > 0:
+		action = ChangeUpdate
+		imp.result.Updated++
+	default:
+		imp.result.Unchanged++
+		return
+	}
+	if isNew {
+		fields = nil
+	}
+	imp.result.Changes = append(imp.result.Changes, ContentChange{Kind: kind, Key: key, Action: action, Fields: fields})
+}
+
+// importCategories 导入分类，父分类先于子分类处理
+func (imp *contentImporter) importCategories(items []CategoryItem) error {
+	for _, item := range sortCategoryItems(items) {
+		existing, err := imp.repo.CategoryBySlug(item.Slug)
+		if err != nil {
+			return err
+		}
+
+		var parentID *uint
+		if item.Parent != "" {
+			id := imp.categoryIDs[item.Parent]
+			parentID = &id
+		}
+
+		category := existing
+		if category == nil {
+			category = &models.Category{Slug: item.Slug}
+		}
+		var fields []string
+		if existing != nil {
+			fields = diff(fields, "name", existing.Name != item.Name)
+			fields = diff(fields, "description", existing.Description != item.Description)
+			fields = diff(fields, "parent", !equalIDPtr(existing.ParentID, parentID))
+			fields = diff(fields, "sort_order", existing.SortOrder != item.SortOrder)
+		}
+		deleted := existing != nil && existing.DeletedAt.Valid
+
+		if existing == nil || deleted || len(fields) > 0 {
+			category.Name = item.Name
+			category.Description = item.Description
+			category.ParentID = parentID
+			category.SortOrder = item.SortOrder
+			category.DeletedAt = gorm.DeletedAt{}
+			if err := imp.repo.Save(category); err != nil {
+				return err
+			}
+		}
+		imp.categoryIDs[item.Slug] = category.ID
+		imp.record("category", item.Slug, deleted, existing == nil, fields)
+	}
+	return nil
+}
+
+// importKnowledge 导入知识点
+func (imp *contentImporter) importKnowledge(items []KnowledgeItem) error {
+	for _, item := range items {
+		existing, err := imp.repo.KnowledgeBySlug(item.Slug)
+		if err != nil {
+			return err
+		}
+
+		references := item.References
+		if references == nil {
+			references = []string{}
+		}
+		categoryID := imp.categoryIDs[item.Category]
+
+		knowledge := existing
+		if knowledge == nil {
+			knowledge = &models.KnowledgePoint{Slug: item.Slug}
+		}
+		var fields []string
+		if existing != nil {
+			fields = diff(fields, "title", existing.Title != item.Title)
+			fields = diff(fields, "category", existing.CategoryID != categoryID)
+			fields = diff(fields, "difficulty", existing.Difficulty != item.Difficulty)
+			fields = diff(fields, "frequency", existing.Frequency != item.Frequency)
+			fields = diff(fields, "description", existing.Description != item.Description)
+			fields = diff(fields, "content", existing.Content != item.Content)
+			fields = diff(fields, "code_example", existing.CodeExample != item.CodeExample)
+			fields = diff(fields, "references", !slices.Equal(decodeStrings(existing.References), references))
+		}
+		deleted := existing != nil && existing.DeletedAt.Valid
+
+		if existing == nil || deleted || len(fields) > 0 {
+			referencesJSON, err := json.Marshal(references)
+			if err != nil {
+				return err
+			}
+			knowledge.Title = item.Title
+			knowledge.CategoryID = categoryID
+			knowledge.Difficulty = item.Difficulty
+			knowledge.Frequency = item.Frequency
+			knowledge.Description = item.Description
+			knowledge.Content = item.Content
+			knowledge.CodeExample = item.CodeExample
+			knowledge.References = string(referencesJSON)
+			knowledge.DeletedAt = gorm.DeletedAt{}
+			if err := imp.repo.Save(knowledge); err != nil {
+				return err
+			}
+		}
+		imp.knowledgeIDs[item.Slug] = knowledge.ID
+		imp.record("knowledge", item.Slug, deleted, existing == nil, fields)
+	}
+	return nil
+}
+
+// importRelations 导入知识关联（按 from、to、type 去重）
+func (imp *contentImporter) importRelations(items []RelationItem) error {
+	for _, item := range items {
+		from, to := imp.knowledgeIDs[item.From], imp.knowledgeIDs[item.To]
+		existing, err := imp.repo.FindRelation(from, to, item.Type)
+		if err != nil {
+			return err
+		}
+
+		deleted := existing != nil && existing.DeletedAt.Valid
+		if existing == nil {
+			existing = &models.KnowledgeRelation{FromPointID: from, ToPointID: to, RelationType: item.Type}
+			if err := imp.repo.Save(existing); err != nil {
+				return err
+			}
+			imp.record("relation", relationKey(item), false, true, nil)
+			continue
+		}
+		if deleted {
+			existing.DeletedAt = gorm.DeletedAt{}
+			if err := imp.repo.Save(existing); err != nil {
+				return err
+			}
+		}
+		imp.record("relation", relationKey(item), deleted, false, nil)
+	}
+	return nil
+}
+
+// importExercises 导入练习题
+func (imp *contentImporter) importExercises(items []ExerciseItem) error {
+	for _, item := range items {
+		existing, err := imp.repo.ExerciseBySlug(item.Slug)
+		if err != nil {
+			return err
+		}
+
+		knowledgeID := imp.knowledgeIDs[item.KnowledgePoint]
+
+		exercise := existing
+		if exercise == nil {
+			exercise = &models.Exercise{Slug: item.Slug}
+		}
+		var fields []string
+		if existing != nil {
+			fields = diff(fields, "knowledge_point", existing.KnowledgePointID != knowledgeID)
+			fields = diff(fields, "type", existing.Type != item.Type)
+			fields = diff(fields, "difficulty", existing.Difficulty != item.Difficulty)
+			fields = diff(fields, "question", existing.Question != item.Question)
+			fields = diff(fields, "options", !slices.Equal(decodeStrings(existing.Options), item.Options))
+			fields = diff(fields, "answer", !slices.Equal(decodeStrings(existing.Answer), item.Answer))
+			fields = diff(fields, "explanation", existing.Explanation != item.Explanation)
+			fields = diff(fields, "starter_code", existing.StarterCode != item.StarterCode)
+			fields = diff(fields, "test_code", existing.TestCode != item.TestCode)
+		}
+		deleted := existing != nil && existing.DeletedAt.Valid
+
+		if existing == nil || deleted || len(fields) > 0 {
+			optionsJSON, err := encodeStrings(item.Options)
+			if err != nil {
+				return err
+			}
+			answerJSON, err := encodeStrings(item.Answer)
+			if err != nil {
+				return err
+			}
+			exercise.KnowledgePointID = knowledgeID
+			exercise.Type = item.Type
+			exercise.Difficulty = item.Difficulty
+			exercise.Question = item.Question
+			exercise.Options = optionsJSON
+			exercise.Answer = answerJSON
+			exercise.Explanation = item.Explanation
+			exercise.StarterCode = item.StarterCode
+			exercise.TestCode = item.TestCode
+			exercise.DeletedAt = gorm.DeletedAt{}
+			if err := imp.repo.Save(exercise); err != nil {
+				return err
+			}
+		}
+		imp.record("exercise", item.Slug, deleted, existing == nil, fields)
+	}
+	return nil
+}
+
+// sortCategoryItems 按父子关系排序，保证父分类先于子分类（父分类已校验无循环）
+func sortCategoryItems(items []CategoryItem) []CategoryItem {
+	inBundle := make(map[string]CategoryItem, len(items))
+	for _, item := range items {
+		inBundle[item.Slug] = item
+	}
+
+	sorted := make([]CategoryItem, 0, len(items))
+	done := make(map[string]bool, len(items))
+	var visit func(item CategoryItem)
+	visit = func(item CategoryItem) {
+		if done[item.Slug] {
+			return
+		}
+		done[item.Slug] = true
+		if parent, ok := inBundle[item.Parent]; ok {
+			visit(parent)
+		}
+		sorted = append(sorted, item)
+	}
+	for _, item := range items {
+		visit(item)
+	}
+	return sorted
+}
+
+// diff 字段有变化时追加字段名
+func diff(fields []string, name string, changed bool) []string {
+	if changed {
+		return append(fields, name)
+	}
+	return fields
+}
+
+func equalIDPtr(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func relationKey(item RelationItem) string {
+	return item.From + "->" + item.To + ":" + item.Type
+}
+
+// encodeStrings 序列化为 JSON 字符串数组，nil 序列化为 []
+func encodeStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+// decodeStrings 解析 JSON 字符串数组，无效或为空时返回空切片
+func decodeStrings(raw string) []string {
+	values := []string{}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &values); err != nil {
+			return []string{}
+		}
+	}
+	return values
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+)
+
+const sampleBundle = `
+version: 1
+categories:
+  - slug: golang
+    name: Go 语言
+  - slug: go-runtime
+    name: 运行时
+    parent: golang
+knowledge_points:
+  - slug: go-gmp
+    title: GMP 调度模型
+    category: go-runtime
+    difficulty: hard
+    frequency: high
+    content: |
+      ## GMP
+      G、M、P 分别是 goroutine、线程和处理器。
+    references:
+      - https://go.dev/src/runtime/proc.go
+relations:
+  - from: go-goroutine
+    to: go-gmp
+    type: prerequisite
+exercises:
+  - slug: go-gmp-p-count
+    knowledge_point: go-gmp
+    type: single_choice
+    difficulty: medium
+    question: P 的默认数量由什么决定？
+    options: [GOMAXPROCS, CPU 核数的两倍]
+    answer: [GOMAXPROCS]
+`
+
+func TestDecodeContentBundle(t *testing.T) {
+	bundle, err := DecodeContentBundle([]byte(sampleBundle), FormatYAML)
+	if err != nil {
+		t.Fatalf("DecodeContentBundle: %v", err)
+	}
+	if len(bundle.Categories) != 2 || len(bundle.Knowledge) != 1 || len(bundle.Relations) != 1 || len(bundle.Exercises) != 1 {
+		t.Fatalf("unexpected bundle sizes: %+v", bundle)
+	}
+	if !strings.Contains(bundle.Knowledge[0].Content, "goroutine") {
+		t.Errorf("markdown content not preserved: %q", bundle.Knowledge[0].Content)
+	}
+
+	if _, err := DecodeContentBundle([]byte("version: 2"), FormatYAML); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestValidateContentBundleResolvesExistingContent(t *testing.T) {
+	bundle, err := DecodeContentBundle([]byte(sampleBundle), FormatYAML)
+	if err != nil {
+		t.Fatalf("DecodeContentBundle: %v", err)
+	}
+
+	// go-goroutine 不在内容包中，也不在数据库中
+	problems := validateContentBundle(bundle, &repository.ContentSnapshot{})
+	if len(problems) != 1 || !strings.Contains(problems[0], "go-goroutine") {
+		t.Fatalf("expected missing knowledge point problem, got %v", problems)
+	}
+
+	// 数据库中已有 go-goroutine
+	snapshot := &repository.ContentSnapshot{
+		Knowledge: []models.KnowledgePoint{{ID: 1, Slug: "go-goroutine"}},
+	}
+	if problems := validateContentBundle(bundle, snapshot); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+}
+
+func TestValidateContentBundleRejectsInvalidItems(t *testing.T) {
+	bundle := &ContentBundle{
+		Version: ContentFormatVersion,
+		Categories: []CategoryItem{
+			{Slug: "a", Name: "A", Parent: "b"},
+			{Slug: "b", Name: "B", Parent: "a"},
+			{Slug: "Bad Slug", Name: "C"},
+		},
+		Knowledge: []KnowledgeItem{
+			{Slug: "k", Title: "K", Category: "a", Difficulty: "easy", Frequency: "high"},
+			{Slug: "k", Title: "K2", Category: "a", Difficulty: "extreme", Frequency: "high"},
+		},
+		Exercises: []ExerciseItem{
+			{Slug: "e", KnowledgePoint: "missing", Type: "single_choice", Difficulty: "easy",
+				Question: "Q", Options: []string{"x", "y"}, Answer: []string{"x", "y"}},
+		},
+	}
+
+	problems := strings.Join(validateContentBundle(bundle, &repository.ContentSnapshot{}), "\n")
+	for _, want := range []string{"循环", "Bad Slug", `slug "k" 重复`, "difficulty", `"missing" 不存在`, "单选题"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("expected problem containing %q, got:\n%s", want, problems)
+		}
+	}
+}
+
+// 导入不能绕过分类层级上限：超出的分类在校验阶段报错，不会写入数据库
+func TestValidateContentBundleRejectsDeepCategories(t *testing.T) {
+	chain := func(prefix string, n int, root string) []CategoryItem {
+		items := make([]CategoryItem, n)
+		parent := root
+		for i := range items {
+			slug := fmt.Sprintf("%s-%d", prefix, i)
+			items[i] = CategoryItem{Slug: slug, Name: slug, Parent: parent}
+			parent = slug
+		}
+		return items
+	}
+
+	// 根分类加 MaxCategoryDepth 层子分类恰好允许
+	bundle := &ContentBundle{Version: ContentFormatVersion, Categories: chain("ok", repository.MaxCategoryDepth+1, "")}
+	if problems := validateContentBundle(bundle, &repository.ContentSnapshot{}); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	bundle.Categories = chain("deep", repository.MaxCategoryDepth+2, "")
+	problems := validateContentBundle(bundle, &repository.ContentSnapshot{})
+	if len(problems) != 1 || !strings.Contains(problems[0], fmt.Sprintf(`"deep-%d"`, repository.MaxCategoryDepth+1)) {
+		t.Fatalf("expected depth problem for the deepest category, got %v", problems)
+	}
+
+	// 把已有分类移动到深层分类下，它已有的子分类超出上限
+	snapshot := &repository.ContentSnapshot{Categories: []models.Category{
+		{ID: 1, Slug: "moved"},
+		{ID: 2, Slug: "moved-child", ParentID: uintPtr(1)},
+	}}
+	bundle.Categories = append(chain("host", repository.MaxCategoryDepth, ""),
+		CategoryItem{Slug: "moved", Name: "moved", Parent: fmt.Sprintf("host-%d", repository.MaxCategoryDepth-1)})
+	problems = validateContentBundle(bundle, snapshot)
+	if len(problems) != 1 || !strings.Contains(problems[0], `"moved-child"`) {
+		t.Fatalf("expected depth problem for the existing child, got %v", problems)
+	}
+}
+
+func TestSortCategoryItemsPutsParentsFirst(t *testing.T) {
+	sorted := sortCategoryItems([]CategoryItem{
+		{Slug: "leaf", Parent: "mid"},
+		{Slug: "mid", Parent: "root"},
+		{Slug: "root"},
+	})
+
+	position := make(map[string]int)
+	for i, item := range sorted {
+		position[item.Slug] = i
+	}
+	if position["root"] > position["mid"] || position["mid"] > position["leaf"] {
+		t.Errorf("unexpected order: %+v", sorted)
+	}
+}
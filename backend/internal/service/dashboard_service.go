@@ -0,0 +1,183 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"eight-gu-learning-platform/internal/repository"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// 看板统计范围
+const (
+	defaultDashboardDays = 30
+	weakestPointLimit    = 5
+	dashboardTopicLimit  = 3
+)
+
+// DashboardService 学习看板服务
+type DashboardService struct {
+	dashboardRepo *repository.DashboardRepository
+	progressRepo  *repository.ProgressRepository
+	pathService   *LearningPathService
+}
+
+// NewDashboardService 创建学习看板服务
+func NewDashboardService(
+	dashboardRepo *repository.DashboardRepository,
+	progressRepo *repository.ProgressRepository,
+	pathService *LearningPathService,
+) *DashboardService {
+	return &DashboardService{
+		dashboardRepo: dashboardRepo,
+		progressRepo:  progressRepo,
+		pathService:   pathService,
+	}
+}
+
+// DashboardRequest 学习看板请求
+type DashboardRequest struct {
+	Days int `form:"days" binding:"omitempty,min=7,max=365"` // 活动时间序列的天数
+}
+
+// DashboardSummary 看板概览：学习进度统计与时间范围内的作答汇总
+type DashboardSummary struct {
+	repository.LearningStats
+	Exercises          int64   `json:"exercises"`            // 时间范围内的作答次数
+	Accuracy           float64 `json:"accuracy"`             // 时间范围内的平均得分百分比
+	ActiveDays         int     `json:"active_days"`          // 时间范围内有学习活动的天数
+	PeriodStudySeconds int64   `json:"period_study_seconds"` // 时间范围内的学习时长
+}
+
+// Streak 连续学习天数
+type Streak struct {
+	Current    int    `json:"current"` // 截至今天（或昨天）的连续天数
+	Longest    int    `json:"longest"`
+	LastActive string `json:"last_active,omitempty"`
+}
+
+// Dashboard 学习看板
+type Dashboard struct {
+	From                 string                         `json:"from"`
+	To                   string                         `json:"to"`
+	Summary              DashboardSummary               `json:"summary"`
+	Activity             []repository.DailyActivity     `json:"activity"`
+	Streak               Streak                         `json:"streak"`
+	AccuracyByCategory   []repository.AccuracyStat      `json:"accuracy_by_category"`
+	AccuracyByDifficulty []repository.AccuracyStat      `json:"accuracy_by_difficulty"`
+	WeakestPoints        []repository.WeakPoint         `json:"weakest_points"`
+	Coverage             []repository.FrequencyCoverage `json:"coverage"`
+	NextTopics           []TopicRecommendation          `json:"next_topics"`
+}
+
+// Get 获取用户的学习看板，各项统计并发查询
+func (s *DashboardService) Get(userID uint, req *DashboardRequest) (*Dashboard, error) {
+	days := req.Days
+	if days == 0 {
+		days = defaultDashboardDays
+	}
+	to := time.Now()
+	from := to.AddDate(0, 0, 1-days)
+
+	var (
+		stats    *repository.LearningStats
+		activity []repository.DailyActivity
+		runs     []repository.ActiveRun
+		accuracy []repository.AccuracyStat
+		weakest  []repository.WeakPoint
+		coverage []repository.FrequencyCoverage
+		topics   []TopicRecommendation
+	)
+	var g errgroup.Group
+	g.Go(func() (err error) {
+		stats, err = s.progressRepo.GetStats(userID)
+		return err
+	})
+	g.Go(func() (err error) {
+		activity, err = s.dashboardRepo.GetDailyActivity(userID, from, to)
+		return err
+	})
+	g.Go(func() (err error) {
+		runs, err = s.dashboardRepo.GetActiveRuns(userID)
+		return err
+	})
+	g.Go(func() (err error) {
+		accuracy, err = s.dashboardRepo.GetAccuracy(userID)
+		return err
+	})
+	g.Go(func() (err error) {
+		weakest, err = s.dashboardRepo.GetWeakestPoints(userID, weakestPointLimit)
+		return err
+	})
+	g.Go(func() (err error) {
+		coverage, err = s.dashboardRepo.GetCoverage(userID, masteredLevel)
+		return err
+	})
+	g.Go(func() (err error) {
+		topics, err = s.pathService.NextTopics(userID, &NextTopicsRequest{Limit: dashboardTopicLimit})
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	dashboard := &Dashboard{
+		From:                 from.Format(time.DateOnly),
+		To:                   to.Format(time.DateOnly),
+		Summary:              summarize(*stats, activity),
+		Activity:             activity,
+		Streak:               computeStreak(runs, to),
+		AccuracyByCategory:   []repository.AccuracyStat{},
+		AccuracyByDifficulty: []repository.AccuracyStat{},
+		WeakestPoints:        weakest,
+		Coverage:             coverage,
+		NextTopics:           topics,
+	}
+	for _, a := range accuracy {
+		if a.Dimension == repository.DimensionCategory {
+			dashboard.AccuracyByCategory = append(dashboard.AccuracyByCategory, a)
+		} else {
+			dashboard.AccuracyByDifficulty = append(dashboard.AccuracyByDifficulty, a)
+		}
+	}
+	return dashboard, nil
+}
+
+// summarize 汇总时间范围内的作答次数、平均得分、学习时长与活跃天数
+func summarize(stats repository.LearningStats, activity []repository.DailyActivity) DashboardSummary {
+	summary := DashboardSummary{LearningStats: stats}
+	var score float64
+	for _, a := range activity {
+		summary.Exercises += a.Exercises
+		score += a.Score
+		summary.PeriodStudySeconds += a.StudySeconds
+		if a.Exercises > 0 || a.Reviewed > 0 || a.StudySeconds > 0 {
+			summary.ActiveDays++
+		}
+	}
+	if summary.Exercises > 0 {
+		summary.Accuracy = math.Round(score/float64(summary.Exercises)*1000) / 10
+	}
+	return summary
+}
+
+// computeStreak 由连续学习区间计算当前与最长连续天数；
+// 最后一段区间结束于今天或昨天时才算作当前连续（今天还没学习不算中断）
+func computeStreak(runs []repository.ActiveRun, today time.Time) Streak {
+	var streak Streak
+	for _, run := range runs {
+		streak.Longest = max(streak.Longest, run.Days)
+	}
+	if len(runs) == 0 {
+		return streak
+	}
+
+	last := runs[len(runs)-1]
+	streak.LastActive = last.LastDay
+	yesterday := today.AddDate(0, 0, -1).Format(time.DateOnly)
+	if last.LastDay >= yesterday {
+		streak.Current = last.Days
+	}
+	return streak
+}
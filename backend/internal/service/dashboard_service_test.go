@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/repository"
+)
+
+func TestComputeStreak(t *testing.T) {
+	today := time.Date(2024, 3, 10, 21, 0, 0, 0, time.Local)
+	runs := []repository.ActiveRun{
+		{Days: 6, LastDay: "2024-02-20"},
+		{Days: 2, LastDay: "2024-03-01"},
+	}
+
+	tests := []struct {
+		name    string
+		last    repository.ActiveRun
+		current int
+	}{
+		{"active today", repository.ActiveRun{Days: 3, LastDay: "2024-03-10"}, 3},
+		{"active yesterday", repository.ActiveRun{Days: 4, LastDay: "2024-03-09"}, 4},
+		{"broken", repository.ActiveRun{Days: 8, LastDay: "2024-03-08"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streak := computeStreak(append(runs, tt.last), today)
+			if streak.Current != tt.current || streak.Longest != max(6, tt.last.Days) || streak.LastActive != tt.last.LastDay {
+				t.Errorf("Unexpected streak: %+v", streak)
+			}
+		})
+	}
+
+	if streak := computeStreak(nil, today); streak != (Streak{}) {
+		t.Errorf("Expected empty streak, got %+v", streak)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	activity := []repository.DailyActivity{
+		{Date: "2024-03-08", Exercises: 3, Correct: 2, Score: 2.5},
+		{Date: "2024-03-09"},
+		{Date: "2024-03-10", StudySeconds: 600},
+		{Date: "2024-03-11", Exercises: 1, Score: 0.5, Reviewed: 2, StudySeconds: 300},
+		{Date: "2024-03-12", Reviewed: 1},
+	}
+
+	summary := summarize(repository.LearningStats{Total: 5}, activity)
+	if summary.Total != 5 || summary.Exercises != 4 || summary.ActiveDays != 4 || summary.Accuracy != 75 || summary.PeriodStudySeconds != 900 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+const (
+	defaultExamQuestions      = 10
+	defaultMinutesPerQuestion = 2
+	maxExamMinutes            = 180
+	// examGracePeriod 截止时间后的宽限期，容忍网络延迟
+	examGracePeriod = 5 * time.Second
+)
+
+// ExamService 模拟面试考试服务
+type ExamService struct {
+	examRepo        *repository.ExamRepository
+	knowledgeRepo   *repository.KnowledgeRepository
+	progressService *ProgressService
+}
+
+// NewExamService 创建考试服务
+func NewExamService(
+	examRepo *repository.ExamRepository,
+	knowledgeRepo *repository.KnowledgeRepository,
+	progressService *ProgressService,
+) *ExamService {
+	return &ExamService{
+		examRepo:        examRepo,
+		knowledgeRepo:   knowledgeRepo,
+		progressService: progressService,
+	}
+}
+
+// StartExamRequest 开始考试请求
+type StartExamRequest struct {
+	CategoryID      uint   `json:"category_id"`
+	Difficulty      string `json:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+	Count           int    `json:"count" binding:"omitempty,min=1,max=100"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=1,max=180"` // 默认每题 2 分钟
+}
+
+// SaveExamAnswerRequest 保存答案请求
+type SaveExamAnswerRequest struct {
+	Answer []string `json:"answer" binding:"required"`
+}
+
+// ExamListRequest 考试列表请求
+type ExamListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ExamQuestion 考试题目（交卷前不包含答案）
+type ExamQuestion struct {
+	ExerciseID       uint       `json:"exercise_id"`
+	KnowledgePointID uint       `json:"knowledge_point_id"`
+	Position         int        `json:"position"`
+	Type             string     `json:"type"`
+	Difficulty       string     `json:"difficulty"`
+	Question         string     `json:"question"`
+	Options          []string   `json:"options"`
+	UserAnswer       []string   `json:"user_answer"`
+	AnsweredAt       *time.Time `json:"answered_at"`
+	IsCorrect        *bool      `json:"is_correct,omitempty"`
+	Score            *float64   `json:"score,omitempty"` // 0~1
+	CorrectAnswer    []string   `json:"correct_answer,omitempty"`
+	Explanation      string     `json:"explanation,omitempty"`
+}
+
+// ExamDetail 考试详情
+type ExamDetail struct {
+	ID               uint           `json:"id"`
+	Status           string         `json:"status"`
+	CategoryID       *uint          `json:"category_id"`
+	Difficulty       string         `json:"difficulty"`
+	QuestionCount    int            `json:"question_count"`
+	DurationSeconds  int            `json:"duration_seconds"`
+	StartedAt        time.Time      `json:"started_at"`
+	Deadline         time.Time      `json:"deadline"`
+	RemainingSeconds int            `json:"remaining_seconds"`
+	SubmittedAt      *time.Time     `json:"submitted_at"`
+	Questions        []ExamQuestion `json:"questions"`
+}
+
+// KnowledgeScore 知识点得分
+type KnowledgeScore struct {
+	KnowledgePointID uint    `json:"knowledge_point_id"`
+	Title            string  `json:"title"`
+	Total            int     `json:"total"`
+	Answered         int     `json:"answered"`
+	Correct          int     `json:"correct"`
+	Score            float64 `json:"score"`    // 各题得分之和（含部分得分）
+	Accuracy         float64 `json:"accuracy"` // 百分比
+}
+
+// ExamReport 考试报告
+type ExamReport struct {
+	ID              uint             `json:"id"`
+	Status          string           `json:"status"`
+	Score           float64          `json:"score"`
+	QuestionCount   int              `json:"question_count"`
+	AnsweredCount   int              `json:"answered_count"`
+	CorrectCount    int              `json:"correct_count"`
+	StartedAt       time.Time        `json:"started_at"`
+	SubmittedAt     *time.Time       `json:"submitted_at"`
+	DurationSeconds int              `json:"duration_seconds"` // 实际用时
+	KnowledgePoints []KnowledgeScore `json:"knowledge_points"`
+	Questions       []ExamQuestion   `json:"questions"`
+}
+
+// Start 开始考试：按条件随机组卷并开始计时
+func (s *ExamService) Start(userID uint, req *StartExamRequest) (*ExamDetail, error) {
+	count := req.Count
+	if count == 0 {
+		count = defaultExamQuestions
+	}
+
+	exercises, err := s.examRepo.PickExercises(req.CategoryID, req.Difficulty, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(exercises) == 0 {
+		return nil, utils.NewParamError("没有符合条件的练习题")
+	}
+
+	minutes := req.DurationMinutes
+	if minutes == 0 {
+		minutes = min(len(exercises)*defaultMinutesPerQuestion, maxExamMinutes)
+	}
+
+	now := time.Now()
+	session := &models.ExamSession{
+		UserID:          userID,
+		Difficulty:      req.Difficulty,
+		QuestionCount:   len(exercises),
+		DurationSeconds: minutes * 60,
+		Status:          models.ExamStatusInProgress,
+		StartedAt:       now,
+		Deadline:        now.Add(time.Duration(minutes) * time.Minute),
+	}
+	if req.CategoryID > 0 {
+		session.CategoryID = &req.CategoryID
+	}
+	for i, exercise := range exercises {
+		session.Answers = append(session.Answers, models.ExamAnswer{
+			ExerciseID: exercise.ID,
+			Position:   i + 1,
+			UserAnswer: "[]",
+		})
+	}
+
+	if err := s.examRepo.Create(session); err != nil {
+		return nil, err
+	}
+	return s.Get(userID, session.ID)
+}
+
+// List 获取用户的考试列表
+func (s *ExamService) List(userID uint, req *ExamListRequest) ([]models.ExamSession, int64, error) {
+	offset := (req.Page - 1) * req.PageSize
+	return s.examRepo.ListByUser(userID, offset, req.PageSize)
+}
+
+// Get 获取考试详情（已超时的考试会先自动交卷）
+func (s *ExamService) Get(userID, sessionID uint) (*ExamDetail, error) {
+	session, err := s.load(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return toExamDetail(session, time.Now()), nil
+}
+
+// SaveAnswer 保存单题答案，截止时间之后拒绝保存
+func (s *ExamService) SaveAnswer(userID, sessionID, exerciseID uint, req *SaveExamAnswerRequest) (*ExamQuestion, error) {
+	session, err := s.load(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.ExamStatusInProgress {
+		return nil, utils.ErrExamFinished
+	}
+
+	for i := range session.Answers {
+		answer := &session.Answers[i]
+		if answer.ExerciseID != exerciseID {
+			continue
+		}
+
+		answerJSON, err := json.Marshal(req.Answer)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		answer.UserAnswer = string(answerJSON)
+		answer.AnsweredAt = &now
+		if err := s.examRepo.SaveAnswer(answer); err != nil {
+			return nil, err
+		}
+
+		question := toExamQuestion(answer, false)
+		return &question, nil
+	}
+	return nil, utils.NewNotFoundError("题目不在本次考试中")
+}
+
+// Submit 交卷并返回考试报告
+func (s *ExamService) Submit(userID, sessionID uint) (*ExamReport, error) {
+	session, err := s.load(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.ExamStatusInProgress {
+		return nil, utils.ErrExamFinished
+	}
+
+	if err := s.finalize(session, models.ExamStatusSubmitted, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.buildReport(session)
+}
+
+// Report 获取考试报告（仅已交卷的考试）
+func (s *ExamService) Report(userID, sessionID uint) (*ExamReport, error) {
+	session, err := s.load(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == models.ExamStatusInProgress {
+		return nil, utils.NewConflictError("考试尚未交卷")
+	}
+	return s.buildReport(session)
+}
+
+// load 加载考试并校验归属；超过截止时间仍未交卷的考试自动按已保存的答案交卷
+func (s *ExamService) load(userID, sessionID uint) (*models.ExamSession, error) {
+	session, err := s.examRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, utils.ErrExamNotFound
+	}
+
+	if session.Status == models.ExamStatusInProgress && time.Now().After(session.Deadline.Add(examGracePeriod)) {
+		if err := s.finalize(session, models.ExamStatusExpired, session.Deadline); err != nil && err != utils.ErrExamFinished {
+			return nil, err
+		}
+		// 可能已被并发请求交卷，重新加载
+		return s.examRepo.GetByID(sessionID)
+	}
+	return session, nil
+}
+
+// finalize 判分并保存交卷结果，同时写入练习记录和复习计划
+func (s *ExamService) finalize(session *models.ExamSession, status string, submittedAt time.Time) error {
+	correct := 0
+	total := 0.0
+	for i := range session.Answers {
+		answer := &session.Answers[i]
+		answer.IsCorrect = false
+		answer.Score = 0
+		if answer.AnsweredAt == nil {
+			continue
+		}
+
+		var userAnswer []string
+		if err := json.Unmarshal([]byte(answer.UserAnswer), &userAnswer); err != nil {
+			continue
+		}
+		result, err := GradeExercise(&answer.Exercise, userAnswer)
+		if err != nil {
+			return err
+		}
+		answer.IsCorrect = result.IsCorrect
+		answer.Score = result.Score
+		total += result.Score
+		if answer.IsCorrect {
+			correct++
+		}
+	}
+
+	session.Status = status
+	session.SubmittedAt = &submittedAt
+	session.CorrectCount = correct
+	session.Score = scorePercentage(total, session.QuestionCount)
+
+	// 已作答的题目计入练习记录，与交卷结果一起保存，避免重复交卷或部分写入
+	var records []models.ExerciseRecord
+	for _, answer := range session.Answers {
+		if answer.AnsweredAt == nil {
+			continue
+		}
+		records = append(records, models.ExerciseRecord{
+			UserID:     session.UserID,
+			ExerciseID: answer.ExerciseID,
+			UserAnswer: answer.UserAnswer,
+			IsCorrect:  answer.IsCorrect,
+			Score:      answer.Score,
+		})
+	}
+	if err := s.examRepo.Finalize(session, records); err != nil {
+		return err
+	}
+
+	// 更新知识点复习计划；交卷结果已保存，失败只记录日志，与单题作答一致
+	for _, answer := range session.Answers {
+		if answer.AnsweredAt == nil {
+			continue
+		}
+		knowledgeID := answer.Exercise.KnowledgePointID
+		if _, err := s.progressService.RecordReview(session.UserID, knowledgeID, reviewQuality(answer.Score)); err != nil {
+			log.Printf("Failed to record review for user %d knowledge %d: %v", session.UserID, knowledgeID, err)
+		}
+	}
+	return nil
+}
+
+// buildReport 生成考试报告，按知识点汇总正确率
+func (s *ExamService) buildReport(session *models.ExamSession) (*ExamReport, error) {
+	report := &ExamReport{
+		ID:              session.ID,
+		Status:          session.Status,
+		Score:           session.Score,
+		QuestionCount:   session.QuestionCount,
+		CorrectCount:    session.CorrectCount,
+		StartedAt:       session.StartedAt,
+		SubmittedAt:     session.SubmittedAt,
+		KnowledgePoints: []KnowledgeScore{},
+		Questions:       make([]ExamQuestion, 0, len(session.Answers)),
+	}
+	if session.SubmittedAt != nil {
+		report.DurationSeconds = int(session.SubmittedAt.Sub(session.StartedAt).Seconds())
+	}
+
+	scores := make(map[uint]*KnowledgeScore)
+	var knowledgeIDs []uint
+	for i := range session.Answers {
+		answer := &session.Answers[i]
+		report.Questions = append(report.Questions, toExamQuestion(answer, true))
+
+		kpID := answer.Exercise.KnowledgePointID
+		score, ok := scores[kpID]
+		if !ok {
+			score = &KnowledgeScore{KnowledgePointID: kpID}
+			scores[kpID] = score
+			knowledgeIDs = append(knowledgeIDs, kpID)
+		}
+		score.Total++
+		if answer.AnsweredAt != nil {
+			score.Answered++
+			report.AnsweredCount++
+		}
+		if answer.IsCorrect {
+			score.Correct++
+		}
+		score.Score += answer.Score
+	}
+
+	knowledges, err := s.knowledgeRepo.ListByIDs(knowledgeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range knowledges {
+		scores[k.ID].Title = k.Title
+	}
+
+	// 按题目中首次出现的顺序输出
+	for _, id := range knowledgeIDs {
+		score := scores[id]
+		score.Score = math.Round(score.Score*10000) / 10000
+		score.Accuracy = scorePercentage(score.Score, score.Total)
+		report.KnowledgePoints = append(report.KnowledgePoints, *score)
+	}
+	return report, nil
+}
+
+// toExamDetail 转换为考试详情，交卷后才包含答案和解析
+func toExamDetail(session *models.ExamSession, now time.Time) *ExamDetail {
+	finished := session.Status != models.ExamStatusInProgress
+	detail := &ExamDetail{
+		ID:              session.ID,
+		Status:          session.Status,
+		CategoryID:      session.CategoryID,
+		Difficulty:      session.Difficulty,
+		QuestionCount:   session.QuestionCount,
+		DurationSeconds: session.DurationSeconds,
+		StartedAt:       session.StartedAt,
+		Deadline:        session.Deadline,
+		SubmittedAt:     session.SubmittedAt,
+		Questions:       make([]ExamQuestion, 0, len(session.Answers)),
+	}
+	if !finished {
+		detail.RemainingSeconds = max(int(session.Deadline.Sub(now).Seconds()), 0)
+	}
+	for i := range session.Answers {
+		detail.Questions = append(detail.Questions, toExamQuestion(&session.Answers[i], finished))
+	}
+	return detail
+}
+
+// toExamQuestion 转换为考试题目，reveal 为 true 时包含判分结果、正确答案和解析
+func toExamQuestion(answer *models.ExamAnswer, reveal bool) ExamQuestion {
+	question := ExamQuestion{
+		ExerciseID:       answer.ExerciseID,
+		KnowledgePointID: answer.Exercise.KnowledgePointID,
+		Position:         answer.Position,
+		Type:             answer.Exercise.Type,
+		Difficulty:       answer.Exercise.Difficulty,
+		Question:         answer.Exercise.Question,
+		Options:          decodeStrings(answer.Exercise.Options),
+		UserAnswer:       decodeStrings(answer.UserAnswer),
+		AnsweredAt:       answer.AnsweredAt,
+	}
+	if reveal {
+		isCorrect, score := answer.IsCorrect, answer.Score
+		question.IsCorrect = &isCorrect
+		question.Score = &score
+		question.CorrectAnswer = decodeStrings(answer.Exercise.Answer)
+		question.Explanation = answer.Exercise.Explanation
+	}
+	return question
+}
+
+// scorePercentage 按得分之和计算百分比，保留一位小数
+func scorePercentage(score float64, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(score*1000/float64(total)) / 10
+}
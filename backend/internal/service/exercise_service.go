@@ -1,38 +1,52 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
 
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/sandbox"
+	"eight-gu-learning-platform/internal/utils"
 )
 
 // ExerciseService 练习题服务
 type ExerciseService struct {
-	exerciseRepo *repository.ExerciseRepository
-	recordRepo   *repository.RecordRepository
+	exerciseRepo    *repository.ExerciseRepository
+	recordRepo      *repository.RecordRepository
+	progressService *ProgressService
+	runner          *sandbox.Runner // 代码题沙箱，未启用时为 nil
+	revealAfter     int             // 作答达到该次数后在详情中公开答案，0 表示只在提交后返回
 }
 
 // NewExerciseService 创建练习题服务
 func NewExerciseService(
 	exerciseRepo *repository.ExerciseRepository,
 	recordRepo *repository.RecordRepository,
+	progressService *ProgressService,
+	runner *sandbox.Runner,
+	revealAfter int,
 ) *ExerciseService {
 	return &ExerciseService{
-		exerciseRepo: exerciseRepo,
-		recordRepo:   recordRepo,
+		exerciseRepo:    exerciseRepo,
+		recordRepo:      recordRepo,
+		progressService: progressService,
+		runner:          runner,
+		revealAfter:     revealAfter,
 	}
 }
 
 // ListRequest 列表请求
 type ExerciseListRequest struct {
-	Page         int    `form:"page" binding:"min=1"`
-	PageSize     int    `form:"page_size" binding:"min=1,max=100"`
+	Page         int    `form:"page" binding:"omitempty,min=1"`
+	PageSize     int    `form:"page_size" binding:"omitempty,min=1,max=100"`
 	KnowledgeID  uint   `form:"knowledge_id"`
 	Difficulty   string `form:"difficulty"`
 }
 
-// SubmitAnswerRequest 提交答案请求
+// SubmitAnswerRequest 提交答案请求（代码题的答案为一个元素，即提交的代码）
 type SubmitAnswerRequest struct {
 	Answer []string `json:"answer" binding:"required"`
 }
@@ -40,106 +54,114 @@ type SubmitAnswerRequest struct {
 // SubmitAnswerResponse 提交答案响应
 type SubmitAnswerResponse struct {
 	IsCorrect    bool   `json:"is_correct"`
+	Score        float64 `json:"score"` // 0~1，多选、填空、排序题支持部分得分
 	CorrectAnswer string `json:"correct_answer"`
 	Explanation  string `json:"explanation"`
 	RecordID     uint   `json:"record_id"`
+	CodeResult   *sandbox.Result `json:"code_result,omitempty"` // 代码题的编译与测试结果
 }
 
-// WrongExercise 错题
-type WrongExercise struct {
-	ID           uint   `json:"id"`
-	Question     string `json:"question"`
-	UserAnswer   string `json:"user_answer"`
-	CorrectAnswer string `json:"correct_answer"`
-	Explanation  string `json:"explanation"`
-	WrongCount   int    `json:"wrong_count"`
-}
-
-// List 获取练习题列表
-func (s *ExerciseService) List(req *ExerciseListRequest) ([]models.Exercise, int64, error) {
+// List 获取练习题列表（不包含答案和解析）
+func (s *ExerciseService) List(req *ExerciseListRequest) ([]PublicExercise, int64, error) {
 	offset := (req.Page - 1) * req.PageSize
-	return s.exerciseRepo.List(offset, req.PageSize, req.KnowledgeID, req.Difficulty)
+	exercises, total, err := s.exerciseRepo.List(offset, req.PageSize, req.KnowledgeID, req.Difficulty)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toPublicExercises(exercises), total, nil
 }
 
-// GetByID 根据 ID 获取练习题详情
-func (s *ExerciseService) GetByID(id uint) (*models.Exercise, error) {
-	return s.exerciseRepo.GetByID(id)
+// GetByID 根据 ID 获取练习题详情；privileged 为编辑/管理员，可直接查看答案和解析
+func (s *ExerciseService) GetByID(userID, id uint, privileged bool) (*ExerciseDetail, error) {
+	exercise, err := s.exerciseRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.recordRepo.CountAttempts(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toExerciseDetail(exercise, int(attempts), shouldReveal(privileged, int(attempts), s.revealAfter)), nil
 }
 
 // SubmitAnswer 提交答案
-func (s *ExerciseService) SubmitAnswer(userID, exerciseID uint, req *SubmitAnswerRequest) (*SubmitAnswerResponse, error) {
+func (s *ExerciseService) SubmitAnswer(ctx context.Context, userID, exerciseID uint, req *SubmitAnswerRequest) (*SubmitAnswerResponse, error) {
 	// 获取练习题
 	exercise, err := s.exerciseRepo.GetByID(exerciseID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 解析正确答案
-	var correctAnswer []string
-	if err := json.Unmarshal([]byte(exercise.Answer), &correctAnswer); err != nil {
-		return nil, err
+	// 按题目类型判分，代码题在沙箱中运行隐藏测试
+	var result GradeResult
+	var codeResult *sandbox.Result
+	testResults := "[]"
+	if exercise.Type == models.ExerciseTypeCode {
+		codeResult, err = s.runCode(ctx, exercise, req.Answer)
+		if err != nil {
+			return nil, err
+		}
+		result = GradeResult{Score: codeResult.Score(), IsCorrect: codeResult.Status == sandbox.StatusPassed}
+		testResultsJSON, err := json.Marshal(codeResult.Tests)
+		if err != nil {
+			return nil, err
+		}
+		testResults = string(testResultsJSON)
+	} else {
+		result, err = GradeExercise(exercise, req.Answer)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// 比较答案
-	isCorrect := compareAnswers(req.Answer, correctAnswer)
-
 	// 序列化用户答案
 	userAnswerJSON, _ := json.Marshal(req.Answer)
 
 	// 创建练习记录
 	record := &models.ExerciseRecord{
-		UserID:     userID,
-		ExerciseID: exerciseID,
-		UserAnswer: string(userAnswerJSON),
-		IsCorrect:  isCorrect,
+		UserID:      userID,
+		ExerciseID:  exerciseID,
+		UserAnswer:  string(userAnswerJSON),
+		IsCorrect:   result.IsCorrect,
+		Score:       result.Score,
+		TestResults: testResults,
 	}
 
 	if err := s.recordRepo.Create(record); err != nil {
 		return nil, err
 	}
 
+	// 更新知识点复习计划；作答记录已保存，失败只记录日志，避免客户端重试产生重复记录
+	if _, err := s.progressService.RecordReview(userID, exercise.KnowledgePointID, reviewQuality(result.Score)); err != nil {
+		log.Printf("Failed to record review for user %d knowledge %d: %v", userID, exercise.KnowledgePointID, err)
+	}
+
 	return &SubmitAnswerResponse{
-		IsCorrect:    isCorrect,
+		IsCorrect:    result.IsCorrect,
+		Score:        result.Score,
 		CorrectAnswer: exercise.Answer,
 		Explanation:  exercise.Explanation,
 		RecordID:     record.ID,
+		CodeResult:   codeResult,
 	}, nil
 }
 
-// compareAnswers 比较答案
-func compareAnswers(userAnswer, correctAnswer []string) bool {
-	if len(userAnswer) != len(correctAnswer) {
-		return false
+// runCode 在沙箱中编译提交的代码并运行隐藏测试
+func (s *ExerciseService) runCode(ctx context.Context, exercise *models.Exercise, answer []string) (*sandbox.Result, error) {
+	if s.runner == nil {
+		return nil, utils.ErrSandboxDisabled
 	}
-
-	for i, a := range userAnswer {
-		if a != correctAnswer[i] {
-			return false
-		}
+	if len(answer) != 1 {
+		return nil, utils.NewParamError("代码题需要提交一份代码")
 	}
 
-	return true
-}
-
-// GetWrongList 获取错题列表
-func (s *ExerciseService) GetWrongList(userID uint, page, pageSize int) ([]WrongExercise, int64, error) {
-	offset := (page - 1) * pageSize
-	records, total, err := s.recordRepo.GetWrongList(userID, offset, pageSize)
-	if err != nil {
-		return nil, 0, err
+	result, err := s.runner.Run(ctx, sandbox.Submission{
+		Source:   answer[0],
+		TestCode: exercise.TestCode,
+	})
+	if errors.Is(err, sandbox.ErrSourceTooLarge) {
+		return nil, utils.NewParamError(err.Error())
 	}
-
-	wrongExercises := make([]WrongExercise, 0, len(records))
-	for _, r := range records {
-		wrongExercises = append(wrongExercises, WrongExercise{
-			ID:           r.ID,
-			Question:     r.Exercise.Question,
-			UserAnswer:   r.UserAnswer,
-			CorrectAnswer: r.Exercise.Answer,
-			Explanation:  r.Exercise.Explanation,
-			WrongCount:   1, // TODO: 统计错误次数
-		})
-	}
-
-	return wrongExercises, total, nil
+	return result, err
 }
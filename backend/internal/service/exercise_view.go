@@ -0,0 +1,74 @@
+package service
+
+import (
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+)
+
+// PublicExercise 返回给学习者的练习题，不包含答案、解析和代码题的隐藏测试
+type PublicExercise struct {
+	ID               uint      `json:"id"`
+	Slug             string    `json:"slug"`
+	KnowledgePointID uint      `json:"knowledge_point_id"`
+	Type             string    `json:"type"`
+	Difficulty       string    `json:"difficulty"`
+	Question         string    `json:"question"`
+	Options          string    `json:"options"` // JSON array
+	StarterCode      string    `json:"starter_code,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ExerciseDetail 练习题详情：作答次数达到公开条件或为编辑/管理员时包含答案和解析
+type ExerciseDetail struct {
+	PublicExercise
+	Attempts    int    `json:"attempts"` // 当前用户的作答次数
+	Revealed    bool   `json:"revealed"`
+	Answer      string `json:"answer,omitempty"` // JSON array
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// toPublicExercise 转换为学习者可见的练习题
+func toPublicExercise(e *models.Exercise) PublicExercise {
+	return PublicExercise{
+		ID:               e.ID,
+		Slug:             e.Slug,
+		KnowledgePointID: e.KnowledgePointID,
+		Type:             e.Type,
+		Difficulty:       e.Difficulty,
+		Question:         e.Question,
+		Options:          e.Options,
+		StarterCode:      e.StarterCode,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
+	}
+}
+
+// toPublicExercises 批量转换为学习者可见的练习题
+func toPublicExercises(exercises []models.Exercise) []PublicExercise {
+	items := make([]PublicExercise, 0, len(exercises))
+	for i := range exercises {
+		items = append(items, toPublicExercise(&exercises[i]))
+	}
+	return items
+}
+
+// toExerciseDetail 转换为练习题详情，reveal 为 true 时包含答案和解析
+func toExerciseDetail(e *models.Exercise, attempts int, reveal bool) *ExerciseDetail {
+	detail := &ExerciseDetail{
+		PublicExercise: toPublicExercise(e),
+		Attempts:       attempts,
+		Revealed:       reveal,
+	}
+	if reveal {
+		detail.Answer = e.Answer
+		detail.Explanation = e.Explanation
+	}
+	return detail
+}
+
+// shouldReveal 是否在详情中公开答案：revealAfter 为 0 时只在提交答案后返回
+func shouldReveal(privileged bool, attempts, revealAfter int) bool {
+	return privileged || (revealAfter > 0 && attempts >= revealAfter)
+}
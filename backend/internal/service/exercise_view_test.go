@@ -0,0 +1,108 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+)
+
+// secretExercise 答案、解析和隐藏测试都带有唯一标记，便于在序列化结果中查找
+var secretExercise = models.Exercise{
+	ID:               7,
+	KnowledgePointID: 3,
+	Question:         "Go 中哪个关键字用于启动 goroutine？",
+	Options:          `["go","defer","chan"]`,
+	Answer:           `["SECRET-ANSWER"]`,
+	Type:             models.ExerciseTypeCode,
+	Explanation:      "SECRET-EXPLANATION",
+	StarterCode:      "package solution\n",
+	TestCode:         "SECRET-TEST-CODE",
+	Difficulty:       "easy",
+}
+
+// assertNoSecrets 检查 JSON 中不包含答案相关的字段和值
+func assertNoSecrets(t *testing.T, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	payload := string(data)
+	for _, secret := range []string{`"answer"`, `"correct_answer"`, `"explanation"`, `"test_code"`, "SECRET-"} {
+		if strings.Contains(payload, secret) {
+			t.Errorf("Public payload contains %s: %s", secret, payload)
+		}
+	}
+}
+
+func TestPublicExerciseHidesAnswer(t *testing.T) {
+	assertNoSecrets(t, toPublicExercise(&secretExercise))
+	assertNoSecrets(t, toPublicExercises([]models.Exercise{secretExercise, secretExercise}))
+	assertNoSecrets(t, &WrongPracticeSet{Items: toPublicExercises([]models.Exercise{secretExercise}), Total: 1})
+	assertNoSecrets(t, toExerciseDetail(&secretExercise, 2, false))
+	assertNoSecrets(t, toExamQuestion(&models.ExamAnswer{Exercise: secretExercise}, false))
+
+	// 排序题的条目即答案的全部内容，下发的顺序不能与正确顺序相同
+	ordering := models.Exercise{ID: 8, Type: models.ExerciseTypeOrdering, Options: `["b","c","a"]`, Answer: `["a","b","c"]`}
+	if err := ValidateExercise(ordering.Type, []string{"b", "c", "a"}, []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("Expected shuffled ordering exercise to be valid, got %v", err)
+	}
+	for _, v := range []interface{}{toPublicExercise(&ordering), toExamQuestion(&models.ExamAnswer{Exercise: ordering}, false)} {
+		data, _ := json.Marshal(v)
+		if strings.Contains(string(data), strings.ReplaceAll(ordering.Answer, `"`, `\"`)) {
+			t.Errorf("Public ordering payload reveals answer order: %s", data)
+		}
+	}
+}
+
+// TestPublicExerciseFields 新增字段时需要确认不会泄露答案
+func TestPublicExerciseFields(t *testing.T) {
+	forbidden := map[string]bool{"answer": true, "correct_answer": true, "explanation": true, "test_code": true}
+	for _, typ := range []reflect.Type{reflect.TypeOf(PublicExercise{}), reflect.TypeOf(repository.SearchHit{})} {
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if forbidden[name] {
+				t.Errorf("%s.%s exposes %q", typ.Name(), typ.Field(i).Name, name)
+			}
+		}
+	}
+}
+
+func TestExerciseDetailReveal(t *testing.T) {
+	tests := []struct {
+		name        string
+		privileged  bool
+		attempts    int
+		revealAfter int
+		reveal      bool
+	}{
+		{"never reveal", false, 10, 0, false},
+		{"below threshold", false, 2, 3, false},
+		{"threshold reached", false, 3, 3, true},
+		{"privileged", true, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reveal := shouldReveal(tt.privileged, tt.attempts, tt.revealAfter)
+			if reveal != tt.reveal {
+				t.Fatalf("Expected reveal=%v, got %v", tt.reveal, reveal)
+			}
+			detail := toExerciseDetail(&secretExercise, tt.attempts, reveal)
+			if !reveal {
+				assertNoSecrets(t, detail)
+				return
+			}
+			if detail.Answer != secretExercise.Answer || detail.Explanation != secretExercise.Explanation {
+				t.Errorf("Expected answer and explanation to be revealed, got %+v", detail)
+			}
+			data, _ := json.Marshal(detail)
+			if strings.Contains(string(data), secretExercise.TestCode) {
+				t.Errorf("Detail exposes test code: %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/sandbox"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// fillBlankRegexPrefix 填空题答案以该前缀开头时按正则表达式匹配
+const fillBlankRegexPrefix = "re:"
+
+// Grader 练习题判分器，按练习题类型注册
+type Grader interface {
+	// Validate 校验题目的选项与标准答案
+	Validate(options, answer []string) error
+	// Grade 判分，返回 0~1 的得分，1 表示完全正确
+	Grade(userAnswer, correctAnswer []string) float64
+}
+
+// graders 判分器注册表
+var graders = map[string]Grader{
+	models.ExerciseTypeSingleChoice:   singleChoiceGrader{},
+	models.ExerciseTypeMultipleChoice: multipleChoiceGrader{},
+	models.ExerciseTypeTrueFalse:      trueFalseGrader{},
+	models.ExerciseTypeFillBlank:      fillBlankGrader{},
+	models.ExerciseTypeOrdering:       orderingGrader{},
+	models.ExerciseTypeCode:           codeGrader{},
+}
+
+// RegisterGrader 注册练习题类型的判分器（新增类型时还需要修改数据库约束）
+func RegisterGrader(exerciseType string, grader Grader) {
+	graders[exerciseType] = grader
+}
+
+// ExerciseTypes 返回已注册的练习题类型
+func ExerciseTypes() []string {
+	types := make([]string, 0, len(graders))
+	for t := range graders {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// GradeResult 判分结果
+type GradeResult struct {
+	Score     float64 `json:"score"` // 0~1
+	IsCorrect bool    `json:"is_correct"`
+}
+
+// ValidateExercise 按题目类型校验选项与标准答案
+func ValidateExercise(exerciseType string, options, answer []string) error {
+	grader, ok := graders[exerciseType]
+	if !ok {
+		return utils.NewParamError(fmt.Sprintf("不支持的题目类型 %q", exerciseType))
+	}
+	if len(answer) == 0 {
+		return utils.NewParamError("答案不能为空")
+	}
+	return grader.Validate(options, answer)
+}
+
+// ValidateTestCode 校验代码题的隐藏测试，其他类型的题目不能设置测试代码
+func ValidateTestCode(exerciseType, testCode string) error {
+	if exerciseType != models.ExerciseTypeCode {
+		if testCode != "" {
+			return utils.NewParamError("只有代码题可以设置测试代码")
+		}
+		return nil
+	}
+	if _, err := sandbox.ListTests(testCode); err != nil {
+		return utils.NewParamError("测试代码无效: " + err.Error())
+	}
+	return nil
+}
+
+// GradeExercise 按题目类型判分（代码题需要通过沙箱运行测试，不在此判分）
+func GradeExercise(exercise *models.Exercise, userAnswer []string) (GradeResult, error) {
+	if exercise.Type == models.ExerciseTypeCode {
+		return GradeResult{}, fmt.Errorf("exercise %d: code exercises are graded by the sandbox", exercise.ID)
+	}
+	grader, ok := graders[exercise.Type]
+	if !ok {
+		return GradeResult{}, fmt.Errorf("exercise %d: no grader for type %q", exercise.ID, exercise.Type)
+	}
+
+	var correctAnswer []string
+	if err := json.Unmarshal([]byte(exercise.Answer), &correctAnswer); err != nil {
+		return GradeResult{}, err
+	}
+
+	score := math.Round(grader.Grade(userAnswer, correctAnswer)*10000) / 10000
+	return GradeResult{Score: score, IsCorrect: score >= 1}, nil
+}
+
+// reviewQuality 按得分换算间隔复习的回忆质量
+func reviewQuality(score float64) int {
+	switch {
+	case score >= 1:
+		return QualityCorrect
+	case score >= 0.5:
+		return QualityHard
+	default:
+		return QualityIncorrect
+	}
+}
+
+// singleChoiceGrader 单选题：答案为选项之一
+type singleChoiceGrader struct{}
+
+func (singleChoiceGrader) Validate(options, answer []string) error {
+	if len(options) < 2 {
+		return utils.NewParamError("至少需要两个选项")
+	}
+	if len(answer) != 1 {
+		return utils.NewParamError("单选题只能有一个答案")
+	}
+	return requireInOptions(options, answer)
+}
+
+func (singleChoiceGrader) Grade(userAnswer, correctAnswer []string) float64 {
+	if len(userAnswer) == 1 && len(correctAnswer) == 1 &&
+		strings.TrimSpace(userAnswer[0]) == strings.TrimSpace(correctAnswer[0]) {
+		return 1
+	}
+	return 0
+}
+
+// multipleChoiceGrader 多选题：不区分顺序；有错选不得分，漏选按选对的比例得分
+type multipleChoiceGrader struct{}
+
+func (multipleChoiceGrader) Validate(options, answer []string) error {
+	if len(options) < 2 {
+		return utils.NewParamError("至少需要两个选项")
+	}
+	if hasDuplicates(answer) {
+		return utils.NewParamError("答案不能重复")
+	}
+	return requireInOptions(options, answer)
+}
+
+func (multipleChoiceGrader) Grade(userAnswer, correctAnswer []string) float64 {
+	if len(correctAnswer) == 0 {
+		return 0
+	}
+	correct := make(map[string]bool, len(correctAnswer))
+	for _, a := range correctAnswer {
+		correct[strings.TrimSpace(a)] = true
+	}
+
+	selected := make(map[string]bool, len(userAnswer))
+	for _, a := range userAnswer {
+		a = strings.TrimSpace(a)
+		if !correct[a] {
+			return 0
+		}
+		selected[a] = true
+	}
+	return float64(len(selected)) / float64(len(correct))
+}
+
+// trueFalseGrader 判断题：答案为 true 或 false，作答时也接受“对/错”“正确/错误”等写法
+type trueFalseGrader struct{}
+
+func (trueFalseGrader) Validate(options, answer []string) error {
+	if len(answer) != 1 {
+		return utils.NewParamError("判断题只能有一个答案")
+	}
+	if _, ok := parseTrueFalse(answer[0]); !ok {
+		return utils.NewParamError("判断题答案必须是 true 或 false")
+	}
+	return nil
+}
+
+func (trueFalseGrader) Grade(userAnswer, correctAnswer []string) float64 {
+	if len(userAnswer) != 1 || len(correctAnswer) != 1 {
+		return 0
+	}
+	got, ok := parseTrueFalse(userAnswer[0])
+	want, _ := parseTrueFalse(correctAnswer[0])
+	if ok && got == want {
+		return 1
+	}
+	return 0
+}
+
+// parseTrueFalse 解析判断题答案
+func parseTrueFalse(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t", "yes", "对", "正确", "是", "√":
+		return true, true
+	case "false", "f", "no", "错", "错误", "否", "×":
+		return false, true
+	}
+	return false, false
+}
+
+// fillBlankGrader 填空题：答案按空的顺序排列，每空忽略首尾空白、大小写和全半角差异后比较；
+// 以 re: 开头的答案按正则表达式整体匹配。多个空按答对的比例得分
+type fillBlankGrader struct{}
+
+func (fillBlankGrader) Validate(options, answer []string) error {
+	for i, a := range answer {
+		if strings.TrimSpace(a) == "" {
+			return utils.NewParamError(fmt.Sprintf("第 %d 空的答案不能为空", i+1))
+		}
+		if pattern, ok := strings.CutPrefix(a, fillBlankRegexPrefix); ok {
+			if _, err := compileBlankPattern(pattern); err != nil {
+				return utils.NewParamError(fmt.Sprintf("第 %d 空的正则表达式无效: %v", i+1, err))
+			}
+		}
+	}
+	return nil
+}
+
+func (fillBlankGrader) Grade(userAnswer, correctAnswer []string) float64 {
+	if len(correctAnswer) == 0 {
+		return 0
+	}
+	matched := 0
+	for i, want := range correctAnswer {
+		if i < len(userAnswer) && matchBlank(userAnswer[i], want) {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(correctAnswer))
+}
+
+// matchBlank 判断单个空的作答是否正确
+func matchBlank(got, want string) bool {
+	got = normalizeBlank(got)
+	if pattern, ok := strings.CutPrefix(want, fillBlankRegexPrefix); ok {
+		re, err := compileBlankPattern(pattern)
+		return err == nil && re.MatchString(got)
+	}
+	return got != "" && got == normalizeBlank(want)
+}
+
+// compileBlankPattern 编译填空题正则表达式：整体匹配且不区分大小写
+func compileBlankPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?i)^(?:` + pattern + `)$`)
+}
+
+// normalizeBlank 规范化填空题答案：全角转半角、合并连续空白、转为小写
+func normalizeBlank(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			r -= 0xfee0
+		}
+		return unicode.ToLower(r)
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// orderingGrader 排序题：选项为待排序的条目，答案为正确顺序；按位置正确的比例得分
+type orderingGrader struct{}
+
+func (orderingGrader) Validate(options, answer []string) error {
+	if len(options) < 2 {
+		return utils.NewParamError("排序题至少需要两个条目")
+	}
+	if hasDuplicates(options) {
+		return utils.NewParamError("排序题的条目不能重复")
+	}
+	sorted := slices.Sorted(slices.Values(answer))
+	if !slices.Equal(sorted, slices.Sorted(slices.Values(options))) {
+		return utils.NewParamError("排序题的答案必须包含全部条目且每个条目只出现一次")
+	}
+	// 条目按原样下发给学习者，与答案顺序相同会直接泄露答案
+	if slices.Equal(options, answer) {
+		return utils.NewParamError("排序题的条目不能按正确顺序排列")
+	}
+	return nil
+}
+
+func (orderingGrader) Grade(userAnswer, correctAnswer []string) float64 {
+	if len(correctAnswer) == 0 || len(userAnswer) != len(correctAnswer) {
+		return 0
+	}
+	correct := 0
+	for i, a := range correctAnswer {
+		if strings.TrimSpace(userAnswer[i]) == strings.TrimSpace(a) {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(correctAnswer))
+}
+
+// codeGrader 代码题：答案为一份参考实现，提交由沙箱运行隐藏测试判分
+type codeGrader struct{}
+
+func (codeGrader) Validate(options, answer []string) error {
+	if len(answer) != 1 {
+		return utils.NewParamError("代码题的答案必须是一份参考实现")
+	}
+	if err := sandbox.CheckSource(answer[0]); err != nil {
+		return utils.NewParamError("参考实现无效: " + err.Error())
+	}
+	return nil
+}
+
+func (codeGrader) Grade(userAnswer, correctAnswer []string) float64 {
+	return 0
+}
+
+// requireInOptions 校验答案都在选项中
+func requireInOptions(options, answer []string) error {
+	for _, a := range answer {
+		if !slices.Contains(options, a) {
+			return utils.NewParamError(fmt.Sprintf("答案 %q 不在选项中", a))
+		}
+	}
+	return nil
+}
+
+// hasDuplicates 检查是否有重复值
+func hasDuplicates(values []string) bool {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if seen[v] {
+			return true
+		}
+		seen[v] = true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"eight-gu-learning-platform/internal/models"
+)
+
+func TestGradeExercise(t *testing.T) {
+	tests := []struct {
+		name       string
+		typ        string
+		answer     string
+		userAnswer []string
+		score      float64
+	}{
+		{"single correct", models.ExerciseTypeSingleChoice, `["A"]`, []string{"A"}, 1},
+		{"single wrong", models.ExerciseTypeSingleChoice, `["A"]`, []string{"B"}, 0},
+		{"single extra", models.ExerciseTypeSingleChoice, `["A"]`, []string{"A", "B"}, 0},
+
+		{"multiple unordered", models.ExerciseTypeMultipleChoice, `["A","B"]`, []string{"B", "A"}, 1},
+		{"multiple partial", models.ExerciseTypeMultipleChoice, `["A","B","C"]`, []string{"A"}, 0.3333},
+		{"multiple wrong pick", models.ExerciseTypeMultipleChoice, `["A","B"]`, []string{"A", "C"}, 0},
+		{"multiple duplicate pick", models.ExerciseTypeMultipleChoice, `["A","B"]`, []string{"A", "A"}, 0.5},
+		{"multiple empty", models.ExerciseTypeMultipleChoice, `["A","B"]`, []string{}, 0},
+
+		{"true false", models.ExerciseTypeTrueFalse, `["true"]`, []string{"True"}, 1},
+		{"true false chinese", models.ExerciseTypeTrueFalse, `["false"]`, []string{"错"}, 1},
+		{"true false wrong", models.ExerciseTypeTrueFalse, `["false"]`, []string{"true"}, 0},
+		{"true false garbage", models.ExerciseTypeTrueFalse, `["false"]`, []string{"maybe"}, 0},
+
+		{"fill normalized", models.ExerciseTypeFillBlank, `["sync.Mutex"]`, []string{"  SYNC.MUTEX "}, 1},
+		{"fill full width", models.ExerciseTypeFillBlank, `["GOMAXPROCS(4)"]`, []string{"ＧＯＭＡＸＰＲＯＣＳ（4）"}, 1},
+		{"fill regex", models.ExerciseTypeFillBlank, `["re:o\\(log ?n\\)"]`, []string{"O(log n)"}, 1},
+		{"fill regex anchored", models.ExerciseTypeFillBlank, `["re:\\d+"]`, []string{"12 ms"}, 0},
+		{"fill partial", models.ExerciseTypeFillBlank, `["chan","select"]`, []string{"chan", "switch"}, 0.5},
+		{"fill missing blank", models.ExerciseTypeFillBlank, `["chan","select"]`, []string{"chan"}, 0.5},
+		{"fill empty", models.ExerciseTypeFillBlank, `["chan"]`, []string{""}, 0},
+
+		{"ordering correct", models.ExerciseTypeOrdering, `["a","b","c","d"]`, []string{"a", "b", "c", "d"}, 1},
+		{"ordering partial", models.ExerciseTypeOrdering, `["a","b","c","d"]`, []string{"a", "b", "d", "c"}, 0.5},
+		{"ordering length mismatch", models.ExerciseTypeOrdering, `["a","b","c"]`, []string{"a", "b"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GradeExercise(&models.Exercise{Type: tt.typ, Answer: tt.answer}, tt.userAnswer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Score != tt.score {
+				t.Errorf("Expected score %v, got %v", tt.score, result.Score)
+			}
+			if result.IsCorrect != (tt.score == 1) {
+				t.Errorf("Expected is_correct %v, got %v", tt.score == 1, result.IsCorrect)
+			}
+		})
+	}
+}
+
+func TestGradeExerciseUnknownType(t *testing.T) {
+	if _, err := GradeExercise(&models.Exercise{Type: "essay", Answer: `["x"]`}, []string{"x"}); err == nil {
+		t.Error("Expected error for unknown exercise type")
+	}
+}
+
+func TestValidateExercise(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		options []string
+		answer  []string
+		valid   bool
+	}{
+		{"single ok", models.ExerciseTypeSingleChoice, []string{"A", "B"}, []string{"A"}, true},
+		{"single two answers", models.ExerciseTypeSingleChoice, []string{"A", "B"}, []string{"A", "B"}, false},
+		{"single not in options", models.ExerciseTypeSingleChoice, []string{"A", "B"}, []string{"C"}, false},
+		{"single one option", models.ExerciseTypeSingleChoice, []string{"A"}, []string{"A"}, false},
+		{"multiple ok", models.ExerciseTypeMultipleChoice, []string{"A", "B", "C"}, []string{"A", "C"}, true},
+		{"multiple duplicate", models.ExerciseTypeMultipleChoice, []string{"A", "B"}, []string{"A", "A"}, false},
+		{"true false ok", models.ExerciseTypeTrueFalse, nil, []string{"false"}, true},
+		{"true false invalid", models.ExerciseTypeTrueFalse, nil, []string{"maybe"}, false},
+		{"fill ok", models.ExerciseTypeFillBlank, nil, []string{"chan", "re:sel(ect)?"}, true},
+		{"fill bad regex", models.ExerciseTypeFillBlank, nil, []string{"re:("}, false},
+		{"ordering ok", models.ExerciseTypeOrdering, []string{"a", "b", "c"}, []string{"c", "a", "b"}, true},
+		{"ordering missing item", models.ExerciseTypeOrdering, []string{"a", "b", "c"}, []string{"a", "b"}, false},
+		{"ordering repeated item", models.ExerciseTypeOrdering, []string{"a", "b"}, []string{"a", "a"}, false},
+		{"ordering options in answer order", models.ExerciseTypeOrdering, []string{"a", "b", "c"}, []string{"a", "b", "c"}, false},
+		{"empty answer", models.ExerciseTypeFillBlank, nil, nil, false},
+		{"unknown type", "essay", nil, []string{"x"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExercise(tt.typ, tt.options, tt.answer)
+			if (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got error %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+)
+
+// 缓存键命名空间
+const (
+	knowledgeCacheNS = "knowledge"
+	graphCacheNS     = "graph"
+	categoryCacheNS  = repository.CategoryCacheNS
+)
+
+// KnowledgeReader 知识点查询接口
+type KnowledgeReader interface {
+	List(req *ListRequest) ([]models.KnowledgePoint, int64, error)
+	GetByID(id uint) (*models.KnowledgePoint, error)
+	GetGraph(categoryID uint) (*GraphData, error)
+	GetUserGraph(userID, categoryID uint) (*GraphData, error)
+	GetNeighbors(userID, id uint, req *NeighborRequest) (*GraphData, error)
+	ShortestPath(userID uint, req *GraphPathRequest) (*GraphPath, error)
+}
+
+// CachedKnowledgeService 带读穿透缓存的知识点服务
+type CachedKnowledgeService struct {
+	*KnowledgeService
+	cache *cache.ReadThrough
+	ttl   time.Duration
+}
+
+// NewCachedKnowledgeService 创建带缓存的知识点服务
+func NewCachedKnowledgeService(knowledgeService *KnowledgeService, rt *cache.ReadThrough, ttl time.Duration) *CachedKnowledgeService {
+	return &CachedKnowledgeService{
+		KnowledgeService: knowledgeService,
+		cache:            rt,
+		ttl:              ttl,
+	}
+}
+
+// GetByID 根据 ID 获取知识点详情（缓存）
+func (s *CachedKnowledgeService) GetByID(id uint) (*models.KnowledgePoint, error) {
+	var knowledge models.KnowledgePoint
+	err := s.cache.Fetch(cache.Key(knowledgeCacheNS, id), s.ttl, &knowledge, func() (interface{}, error) {
+		return s.KnowledgeService.GetByID(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &knowledge, nil
+}
+
+// GetGraph 获取知识图谱数据（缓存）
+func (s *CachedKnowledgeService) GetGraph(categoryID uint) (*GraphData, error) {
+	var graph GraphData
+	err := s.cache.Fetch(cache.Key(graphCacheNS, categoryID), s.ttl, &graph, func() (interface{}, error) {
+		return s.KnowledgeService.GetGraph(categoryID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &graph, nil
+}
+
+// GetUserGraph 获取知识图谱数据（图谱缓存，用户学习状态实时叠加）
+func (s *CachedKnowledgeService) GetUserGraph(userID, categoryID uint) (*GraphData, error) {
+	graph, err := s.GetGraph(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.overlayProgress(userID, graph)
+}
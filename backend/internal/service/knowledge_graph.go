@@ -0,0 +1,267 @@
+package service
+
+import (
+	"slices"
+	"strconv"
+
+	"eight-gu-learning-platform/internal/models"
+)
+
+// 邻域查询深度
+const (
+	defaultNeighborDepth = 1
+	maxNeighborDepth     = 3
+)
+
+// NeighborRequest 邻域查询请求
+type NeighborRequest struct {
+	Depth int    `form:"depth" binding:"omitempty,min=1,max=3"`
+	Type  string `form:"type" binding:"omitempty,oneof=prerequisite related extended"`
+}
+
+// GraphPathRequest 最短路径查询请求
+type GraphPathRequest struct {
+	From     uint   `form:"from" binding:"required"`
+	To       uint   `form:"to" binding:"required"`
+	Type     string `form:"type" binding:"omitempty,oneof=prerequisite related extended"`
+	Directed bool   `form:"directed"` // 只沿关联方向查找（如前置 → 后续）
+}
+
+// GraphPath 两个知识点之间的最短路径
+type GraphPath struct {
+	Found  bool   `json:"found"`
+	Length int    `json:"length"` // 路径上的关联数量
+	Path   []uint `json:"path"`   // 从起点到终点的知识点 ID
+	GraphData
+}
+
+// GetUserGraph 获取知识图谱数据，并在节点上叠加用户的学习状态
+func (s *KnowledgeService) GetUserGraph(userID, categoryID uint) (*GraphData, error) {
+	graph, err := s.GetGraph(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.overlayProgress(userID, graph)
+}
+
+// GetNeighbors 获取知识点 depth 层以内的子图（不区分关联方向），节点包含与中心的距离
+func (s *KnowledgeService) GetNeighbors(userID, id uint, req *NeighborRequest) (*GraphData, error) {
+	if _, err := s.knowledgeRepo.GetByID(id); err != nil {
+		return nil, err
+	}
+	depth := req.Depth
+	if depth == 0 {
+		depth = defaultNeighborDepth
+	}
+
+	neighbors, err := s.relationRepo.GetNeighborhood(id, min(depth, maxNeighborDepth), req.Type)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(neighbors))
+	depths := make(map[uint]int, len(neighbors))
+	for _, n := range neighbors {
+		ids = append(ids, n.ID)
+		depths[n.ID] = n.Depth
+	}
+
+	knowledges, err := s.knowledgeRepo.ListByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.relationRepo.ListAmong(ids, req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := buildGraph(knowledges, relations)
+	for i := range graph.Nodes {
+		graph.Nodes[i].Data["depth"] = depths[knowledges[i].ID]
+	}
+	return s.overlayProgress(userID, graph)
+}
+
+// ShortestPath 查找两个知识点之间关联数量最少的路径，找不到时 Found 为 false
+func (s *KnowledgeService) ShortestPath(userID uint, req *GraphPathRequest) (*GraphPath, error) {
+	for _, id := range []uint{req.From, req.To} {
+		if _, err := s.knowledgeRepo.GetByID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	knowledges, err := s.knowledgeRepo.ListBrief()
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.relationRepo.List(0, 0, req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[uint]bool, len(knowledges))
+	for _, k := range knowledges {
+		active[k.ID] = true
+	}
+	path, edges := shortestPath(relations, active, req.From, req.To, req.Directed)
+	result := &GraphPath{Found: path != nil, Path: path, GraphData: GraphData{Nodes: []GraphNode{}, Edges: []GraphEdge{}}}
+	if path == nil {
+		result.Path = []uint{}
+		return result, nil
+	}
+	result.Length = len(edges)
+
+	points, err := s.knowledgeRepo.ListByIDs(path)
+	if err != nil {
+		return nil, err
+	}
+	graph := buildGraph(points, edges)
+	if _, err := s.overlayProgress(userID, graph); err != nil {
+		return nil, err
+	}
+	result.GraphData = *graph
+	return result, nil
+}
+
+// overlayProgress 在节点上叠加用户的学习状态、掌握度以及是否已掌握
+func (s *KnowledgeService) overlayProgress(userID uint, graph *GraphData) (*GraphData, error) {
+	progresses, err := s.progressRepo.ListBrief(userID)
+	if err != nil {
+		return nil, err
+	}
+	byPoint := make(map[string]*models.LearningProgress, len(progresses))
+	for i := range progresses {
+		byPoint[graphID(progresses[i].KnowledgePointID)] = &progresses[i]
+	}
+
+	for i := range graph.Nodes {
+		data := graph.Nodes[i].Data
+		if p, ok := byPoint[graph.Nodes[i].ID]; ok {
+			data["status"] = p.Status
+			data["mastery_level"] = p.MasteryLevel
+			data["mastered"] = isMastered(p)
+		} else {
+			data["status"] = "not_started"
+			data["mastery_level"] = 0
+			data["mastered"] = false
+		}
+	}
+	return graph, nil
+}
+
+// shortestPath 在关联关系上做广度优先搜索，返回路径上的知识点与关联；
+// directed 为 false 时关联可以反向经过，不经过 active 之外的知识点
+func shortestPath(relations []models.KnowledgeRelation, active map[uint]bool, from, to uint, directed bool) ([]uint, []models.KnowledgeRelation) {
+	if !active[from] || !active[to] {
+		return nil, nil
+	}
+
+	adjacent := make(map[uint][]int)
+	for i, r := range relations {
+		if !active[r.FromPointID] || !active[r.ToPointID] {
+			continue
+		}
+		adjacent[r.FromPointID] = append(adjacent[r.FromPointID], i)
+		if !directed {
+			adjacent[r.ToPointID] = append(adjacent[r.ToPointID], i)
+		}
+	}
+
+	// via 记录到达每个知识点经过的关联下标，起点为 -1
+	via := map[uint]int{from: -1}
+	queue := []uint{from}
+	for len(queue) > 0 {
+		if _, found := via[to]; found {
+			break
+		}
+		id := queue[0]
+		queue = queue[1:]
+		for _, i := range adjacent[id] {
+			next := relations[i].ToPointID
+			if next == id {
+				next = relations[i].FromPointID
+			}
+			if _, seen := via[next]; seen {
+				continue
+			}
+			via[next] = i
+			queue = append(queue, next)
+		}
+	}
+	if _, ok := via[to]; !ok {
+		return nil, nil
+	}
+
+	path := []uint{to}
+	var edges []models.KnowledgeRelation
+	for id := to; id != from; {
+		r := relations[via[id]]
+		edges = append(edges, r)
+		if r.ToPointID == id {
+			id = r.FromPointID
+		} else {
+			id = r.ToPointID
+		}
+		path = append(path, id)
+	}
+	slices.Reverse(path)
+	slices.Reverse(edges)
+	return path, edges
+}
+
+// buildGraph 构建图谱数据：只保留两端都在节点集合中的关联
+func buildGraph(knowledges []models.KnowledgePoint, relations []models.KnowledgeRelation) *GraphData {
+	nodes := make([]GraphNode, 0, len(knowledges))
+	inGraph := make(map[uint]bool, len(knowledges))
+	for _, k := range knowledges {
+		inGraph[k.ID] = true
+		nodes = append(nodes, GraphNode{
+			ID:    graphID(k.ID),
+			Label: k.Title,
+			Data: map[string]interface{}{
+				"id":          k.ID,
+				"title":       k.Title,
+				"slug":        k.Slug,
+				"category_id": k.CategoryID,
+				"difficulty":  k.Difficulty,
+				"frequency":   k.Frequency,
+			},
+		})
+	}
+
+	edges := make([]GraphEdge, 0, len(relations))
+	for _, r := range relations {
+		if !inGraph[r.FromPointID] || !inGraph[r.ToPointID] {
+			continue
+		}
+		edges = append(edges, GraphEdge{
+			ID:     graphID(r.ID),
+			Source: graphID(r.FromPointID),
+			Target: graphID(r.ToPointID),
+			Label:  relationLabel(r.RelationType),
+			Type:   r.RelationType,
+		})
+	}
+
+	return &GraphData{
+		Nodes: nodes,
+		Edges: edges,
+	}
+}
+
+// graphID 图谱节点与边的 ID：知识点或关联的十进制 ID
+func graphID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// relationLabel 关联类型的显示名称
+func relationLabel(relationType string) string {
+	switch relationType {
+	case "prerequisite":
+		return "前置"
+	case "related":
+		return "相关"
+	case "extended":
+		return "扩展"
+	}
+	return ""
+}
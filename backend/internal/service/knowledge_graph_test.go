@@ -0,0 +1,66 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"eight-gu-learning-platform/internal/models"
+)
+
+func TestBuildGraph(t *testing.T) {
+	knowledges := []models.KnowledgePoint{{ID: 65, Title: "goroutine"}, {ID: 1024, Title: "GMP"}}
+	relations := []models.KnowledgeRelation{
+		{ID: 300, FromPointID: 65, ToPointID: 1024, RelationType: "prerequisite"},
+		{ID: 301, FromPointID: 1024, ToPointID: 7, RelationType: "related"}, // 7 不在节点集合中
+	}
+
+	graph := buildGraph(knowledges, relations)
+	if graph.Nodes[0].ID != "65" || graph.Nodes[1].ID != "1024" {
+		t.Errorf("Unexpected node IDs: %q, %q", graph.Nodes[0].ID, graph.Nodes[1].ID)
+	}
+	if len(graph.Edges) != 1 {
+		t.Fatalf("Expected 1 edge, got %+v", graph.Edges)
+	}
+	edge := graph.Edges[0]
+	if edge.ID != "300" || edge.Source != "65" || edge.Target != "1024" || edge.Label != "前置" {
+		t.Errorf("Unexpected edge: %+v", edge)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	relations := []models.KnowledgeRelation{
+		{ID: 1, FromPointID: 1, ToPointID: 2, RelationType: "prerequisite"},
+		{ID: 2, FromPointID: 2, ToPointID: 3, RelationType: "prerequisite"},
+		{ID: 3, FromPointID: 4, ToPointID: 3, RelationType: "related"},
+		{ID: 4, FromPointID: 1, ToPointID: 5, RelationType: "extended"},
+		{ID: 5, FromPointID: 5, ToPointID: 4, RelationType: "extended"},
+	}
+	active := map[uint]bool{1: true, 2: true, 3: true, 4: true, 5: true}
+
+	tests := []struct {
+		name     string
+		from, to uint
+		directed bool
+		active   map[uint]bool
+		path     []uint
+		edges    []uint
+	}{
+		{"directed", 1, 3, true, active, []uint{1, 2, 3}, []uint{1, 2}},
+		{"reverse edge", 3, 1, false, active, []uint{3, 2, 1}, []uint{2, 1}},
+		{"directed unreachable", 3, 1, true, active, nil, nil},
+		{"same point", 2, 2, false, active, []uint{2}, nil},
+		{"skip deleted", 1, 3, false, map[uint]bool{1: true, 3: true, 4: true, 5: true}, []uint{1, 5, 4, 3}, []uint{4, 5, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, edges := shortestPath(relations, tt.active, tt.from, tt.to, tt.directed)
+			var edgeIDs []uint
+			for _, e := range edges {
+				edgeIDs = append(edgeIDs, e.ID)
+			}
+			if !slices.Equal(path, tt.path) || !slices.Equal(edgeIDs, tt.edges) {
+				t.Errorf("Expected %v via %v, got %v via %v", tt.path, tt.edges, path, edgeIDs)
+			}
+		})
+	}
+}
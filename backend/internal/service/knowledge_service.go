@@ -10,6 +10,7 @@ type KnowledgeService struct {
 	knowledgeRepo *repository.KnowledgeRepository
 	categoryRepo  *repository.CategoryRepository
 	relationRepo  *repository.RelationRepository
+	progressRepo  *repository.ProgressRepository
 }
 
 // NewKnowledgeService 创建知识点服务
@@ -17,25 +18,27 @@ func NewKnowledgeService(
 	knowledgeRepo *repository.KnowledgeRepository,
 	categoryRepo *repository.CategoryRepository,
 	relationRepo *repository.RelationRepository,
+	progressRepo *repository.ProgressRepository,
 ) *KnowledgeService {
 	return &KnowledgeService{
 		knowledgeRepo: knowledgeRepo,
 		categoryRepo:  categoryRepo,
 		relationRepo:  relationRepo,
+		progressRepo:  progressRepo,
 	}
 }
 
 // ListRequest 列表请求
 type ListRequest struct {
-	Page        int    `form:"page" binding:"min=1"`
-	PageSize    int    `form:"page_size" binding:"min=1,max=100"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
 	CategoryID  uint   `form:"category_id"`
 	Difficulty  string `form:"difficulty"`
 	Frequency   string `form:"frequency"`
 	Search      string `form:"search"`
 }
 
-// GraphNode 图谱节点
+// GraphNode 图谱节点，ID 为知识点 ID 的十进制字符串
 type GraphNode struct {
 	ID   string                 `json:"id"`
 	Label string                 `json:"label"`
@@ -74,45 +77,5 @@ func (s *KnowledgeService) GetGraph(categoryID uint) (*GraphData, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	// 构建节点
-	nodes := make([]GraphNode, 0, len(knowledges))
-	for _, k := range knowledges {
-		nodes = append(nodes, GraphNode{
-			ID:   string(rune(k.ID)),
-			Label: k.Title,
-			Data: map[string]interface{}{
-				"id":         k.ID,
-				"title":      k.Title,
-				"difficulty": k.Difficulty,
-			},
-		})
-	}
-
-	// 构建边
-	edges := make([]GraphEdge, 0, len(relations))
-	for _, r := range relations {
-		var label string
-		switch r.RelationType {
-		case "prerequisite":
-			label = "前置"
-		case "related":
-			label = "相关"
-		case "extended":
-			label = "扩展"
-		}
-
-		edges = append(edges, GraphEdge{
-			ID:    string(rune(r.ID)),
-			Source: string(rune(r.FromPointID)),
-			Target: string(rune(r.ToPointID)),
-			Label:  label,
-			Type:   r.RelationType,
-		})
-	}
-
-	return &GraphData{
-		Nodes: nodes,
-		Edges: edges,
-	}, nil
+	return buildGraph(knowledges, relations), nil
 }
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// masteredLevel 掌握度达到该值视为已掌握（状态为 completed 时同样视为已掌握）
+const masteredLevel = 80
+
+// 推荐数量
+const (
+	defaultTopicLimit = 5
+	maxTopicLimit     = 20
+)
+
+// frequencyWeight 面试频率权重
+var frequencyWeight = map[string]float64{"high": 3, "medium": 2, "low": 1}
+
+// difficultyWeight 难度权重，越简单越优先
+var difficultyWeight = map[string]float64{"easy": 1.5, "medium": 1, "hard": 0.5}
+
+// LearningPathService 学习路径推荐服务：基于前置关系图与用户学习进度
+type LearningPathService struct {
+	knowledgeRepo *repository.KnowledgeRepository
+	relationRepo  *repository.RelationRepository
+	progressRepo  *repository.ProgressRepository
+}
+
+// NewLearningPathService 创建学习路径推荐服务
+func NewLearningPathService(
+	knowledgeRepo *repository.KnowledgeRepository,
+	relationRepo *repository.RelationRepository,
+	progressRepo *repository.ProgressRepository,
+) *LearningPathService {
+	return &LearningPathService{
+		knowledgeRepo: knowledgeRepo,
+		relationRepo:  relationRepo,
+		progressRepo:  progressRepo,
+	}
+}
+
+// LearningPathRequest 学习路径请求
+type LearningPathRequest struct {
+	Target uint `form:"target" binding:"required"`
+}
+
+// NextTopicsRequest 推荐知识点请求
+type NextTopicsRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// PathStep 学习路径中的一个知识点
+type PathStep struct {
+	KnowledgePointID uint    `json:"knowledge_point_id"`
+	Title            string  `json:"title"`
+	Slug             string  `json:"slug"`
+	CategoryID       uint    `json:"category_id"`
+	Difficulty       string  `json:"difficulty"`
+	Frequency        string  `json:"frequency"`
+	Status           string  `json:"status"`
+	MasteryLevel     int     `json:"mastery_level"`
+	Weight           float64 `json:"weight"`        // 同一层级内按权重从高到低排列
+	Prerequisites    []uint  `json:"prerequisites"` // 尚未掌握的直接前置知识点
+}
+
+// LearningPath 到达目标知识点的学习路径
+type LearningPath struct {
+	TargetID uint       `json:"target_id"`
+	Mastered bool       `json:"mastered"` // 目标已掌握时路径为空
+	Steps    []PathStep `json:"steps"`    // 按学习顺序排列，最后一步为目标知识点
+	Total    int        `json:"total"`
+}
+
+// TopicRecommendation 推荐学习的知识点：前置知识点均已掌握
+type TopicRecommendation struct {
+	PathStep
+	Unlocks int `json:"unlocks"` // 掌握后可解锁的后续知识点数量
+}
+
+// GetPath 按前置关系对目标知识点尚未掌握的前置知识点做拓扑排序；
+// 已掌握的前置知识点不再展开其前置，前置关系存在环时返回冲突错误
+func (s *LearningPathService) GetPath(userID uint, req *LearningPathRequest) (*LearningPath, error) {
+	if _, err := s.knowledgeRepo.GetByID(req.Target); err != nil {
+		return nil, err
+	}
+
+	relations, err := s.relationRepo.GetPrerequisiteClosure(req.Target)
+	if err != nil {
+		return nil, err
+	}
+	ids := []uint{req.Target}
+	for _, r := range relations {
+		ids = append(ids, r.FromPointID)
+	}
+	g, err := s.loadGraph(userID, ids, relations)
+	if err != nil {
+		return nil, err
+	}
+
+	order, cycle := g.plan(req.Target)
+	if len(cycle) > 0 {
+		return nil, utils.NewConflictError("前置关系存在环: " + g.describe(cycle))
+	}
+
+	path := &LearningPath{TargetID: req.Target, Mastered: g.mastered(req.Target), Steps: make([]PathStep, 0, len(order))}
+	for _, id := range order {
+		path.Steps = append(path.Steps, g.step(id))
+	}
+	path.Total = len(path.Steps)
+	return path, nil
+}
+
+// NextTopics 推荐下一步学习的知识点：尚未掌握且前置均已掌握，按学习中优先、
+// 可解锁的后续知识点数量和频率/难度权重排序
+func (s *LearningPathService) NextTopics(userID uint, req *NextTopicsRequest) ([]TopicRecommendation, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultTopicLimit
+	}
+
+	relations, err := s.relationRepo.List(0, 0, "prerequisite")
+	if err != nil {
+		return nil, err
+	}
+	g, err := s.loadGraph(userID, nil, relations)
+	if err != nil {
+		return nil, err
+	}
+	return g.nextTopics(min(limit, maxTopicLimit)), nil
+}
+
+// loadGraph 加载知识点、前置关系与用户进度；ids 为空时加载全部知识点
+func (s *LearningPathService) loadGraph(userID uint, ids []uint, relations []models.KnowledgeRelation) (*prerequisiteGraph, error) {
+	var points []models.KnowledgePoint
+	var err error
+	if ids == nil {
+		points, err = s.knowledgeRepo.ListBrief()
+	} else {
+		points, err = s.knowledgeRepo.ListByIDs(ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	progresses, err := s.progressRepo.ListBrief(userID)
+	if err != nil {
+		return nil, err
+	}
+	return newPrerequisiteGraph(points, relations, progresses), nil
+}
+
+// prerequisiteGraph 前置关系图，边由前置知识点指向后续知识点
+type prerequisiteGraph struct {
+	points     map[uint]*models.KnowledgePoint
+	prereqs    map[uint][]uint // 知识点 -> 直接前置
+	dependents map[uint][]uint // 知识点 -> 直接后续
+	progress   map[uint]models.LearningProgress
+}
+
+// newPrerequisiteGraph 构建前置关系图，忽略端点不存在（如已删除）的关系
+func newPrerequisiteGraph(points []models.KnowledgePoint, relations []models.KnowledgeRelation, progresses []models.LearningProgress) *prerequisiteGraph {
+	g := &prerequisiteGraph{
+		points:     make(map[uint]*models.KnowledgePoint, len(points)),
+		prereqs:    make(map[uint][]uint),
+		dependents: make(map[uint][]uint),
+		progress:   make(map[uint]models.LearningProgress, len(progresses)),
+	}
+	for i := range points {
+		g.points[points[i].ID] = &points[i]
+	}
+	for _, r := range relations {
+		if r.RelationType != "prerequisite" || g.points[r.FromPointID] == nil || g.points[r.ToPointID] == nil {
+			continue
+		}
+		if slices.Contains(g.prereqs[r.ToPointID], r.FromPointID) {
+			continue
+		}
+		g.prereqs[r.ToPointID] = append(g.prereqs[r.ToPointID], r.FromPointID)
+		g.dependents[r.FromPointID] = append(g.dependents[r.FromPointID], r.ToPointID)
+	}
+	for _, p := range progresses {
+		g.progress[p.KnowledgePointID] = p
+	}
+	return g
+}
+
+// mastered 知识点是否已掌握
+func (g *prerequisiteGraph) mastered(id uint) bool {
+	p, ok := g.progress[id]
+	return ok && isMastered(&p)
+}
+
+// isMastered 学习进度是否达到已掌握
+func isMastered(p *models.LearningProgress) bool {
+	return p.Status == "completed" || p.MasteryLevel >= masteredLevel
+}
+
+// weight 知识点权重：高频、简单的知识点优先
+func (g *prerequisiteGraph) weight(id uint) float64 {
+	k := g.points[id]
+	return frequencyWeight[k.Frequency] + difficultyWeight[k.Difficulty]
+}
+
+// unmetPrereqs 尚未掌握的直接前置知识点
+func (g *prerequisiteGraph) unmetPrereqs(id uint) []uint {
+	var unmet []uint
+	for _, p := range g.prereqs[id] {
+		if !g.mastered(p) {
+			unmet = append(unmet, p)
+		}
+	}
+	return unmet
+}
+
+// plan 收集目标及其尚未掌握的前置知识点并做拓扑排序（Kahn 算法，就绪的知识点按权重优先）；
+// 存在环时返回环上的知识点
+func (g *prerequisiteGraph) plan(target uint) (order, cycle []uint) {
+	if g.mastered(target) {
+		return nil, nil
+	}
+
+	// 从目标出发沿未掌握的前置知识点反向遍历
+	pending := map[uint]bool{target: true}
+	stack := []uint{target}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, p := range g.unmetPrereqs(id) {
+			if !pending[p] {
+				pending[p] = true
+				stack = append(stack, p)
+			}
+		}
+	}
+
+	indegree := make(map[uint]int, len(pending))
+	var ready []uint
+	for id := range pending {
+		indegree[id] = len(g.unmetPrereqs(id))
+		if indegree[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	for len(ready) > 0 {
+		slices.SortFunc(ready, g.compare)
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		delete(pending, id)
+		for _, d := range g.dependents[id] {
+			if !pending[d] {
+				continue
+			}
+			if indegree[d]--; indegree[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		return nil, g.findCycle(pending)
+	}
+	return order, nil
+}
+
+// compare 权重从高到低，权重相同时按 ID 升序
+func (g *prerequisiteGraph) compare(a, b uint) int {
+	if c := cmp.Compare(g.weight(b), g.weight(a)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a, b)
+}
+
+// findCycle 在拓扑排序剩余的知识点中找出一个环：剩余的知识点都至少有一个剩余的前置
+func (g *prerequisiteGraph) findCycle(remaining map[uint]bool) []uint {
+	visited := map[uint]int{}
+	var walk []uint
+	for id := slices.Min(slices.Collect(maps.Keys(remaining))); ; {
+		if i, ok := visited[id]; ok {
+			cycle := slices.Clone(walk[i:])
+			slices.Reverse(cycle) // 按前置到后续的顺序
+			return append(cycle, cycle[0])
+		}
+		visited[id] = len(walk)
+		walk = append(walk, id)
+		for _, p := range g.unmetPrereqs(id) {
+			if remaining[p] {
+				id = p
+				break
+			}
+		}
+	}
+}
+
+// describe 以标题描述知识点序列
+func (g *prerequisiteGraph) describe(ids []uint) string {
+	titles := make([]string, 0, len(ids))
+	for _, id := range ids {
+		titles = append(titles, g.points[id].Title)
+	}
+	return strings.Join(titles, " → ")
+}
+
+// step 转换为学习路径步骤
+func (g *prerequisiteGraph) step(id uint) PathStep {
+	k := g.points[id]
+	status := "not_started"
+	var mastery int
+	if p, ok := g.progress[id]; ok {
+		status, mastery = p.Status, p.MasteryLevel
+	}
+	prereqs := g.unmetPrereqs(id)
+	if prereqs == nil {
+		prereqs = []uint{}
+	}
+	slices.Sort(prereqs)
+	return PathStep{
+		KnowledgePointID: id,
+		Title:            k.Title,
+		Slug:             k.Slug,
+		CategoryID:       k.CategoryID,
+		Difficulty:       k.Difficulty,
+		Frequency:        k.Frequency,
+		Status:           status,
+		MasteryLevel:     mastery,
+		Weight:           g.weight(id),
+		Prerequisites:    prereqs,
+	}
+}
+
+// nextTopics 尚未掌握且前置均已掌握的知识点，学习中的优先，其次按可解锁数量与权重排序
+func (g *prerequisiteGraph) nextTopics(limit int) []TopicRecommendation {
+	topics := make([]TopicRecommendation, 0)
+	for id := range g.points {
+		if g.mastered(id) || len(g.unmetPrereqs(id)) > 0 {
+			continue
+		}
+		unlocks := 0
+		for _, d := range g.dependents[id] {
+			if !g.mastered(d) {
+				unlocks++
+			}
+		}
+		topics = append(topics, TopicRecommendation{PathStep: g.step(id), Unlocks: unlocks})
+	}
+
+	slices.SortFunc(topics, func(a, b TopicRecommendation) int {
+		inProgressA, inProgressB := a.Status == "in_progress", b.Status == "in_progress"
+		if inProgressA != inProgressB {
+			if inProgressA {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(b.Unlocks, a.Unlocks); c != 0 {
+			return c
+		}
+		return g.compare(a.KnowledgePointID, b.KnowledgePointID)
+	})
+	if len(topics) > limit {
+		topics = topics[:limit]
+	}
+	return topics
+}
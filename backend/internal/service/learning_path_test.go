@@ -0,0 +1,85 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"eight-gu-learning-platform/internal/models"
+)
+
+// testGraph 1 goroutine → 3 GMP → 5 调度器；2 channel → 4 select → 5；6 与 7 互为前置
+func testGraph(progresses ...models.LearningProgress) *prerequisiteGraph {
+	points := []models.KnowledgePoint{
+		{ID: 1, Title: "goroutine", Frequency: "high", Difficulty: "easy"},
+		{ID: 2, Title: "channel", Frequency: "high", Difficulty: "medium"},
+		{ID: 3, Title: "GMP", Frequency: "high", Difficulty: "hard"},
+		{ID: 4, Title: "select", Frequency: "medium", Difficulty: "medium"},
+		{ID: 5, Title: "调度器", Frequency: "medium", Difficulty: "hard"},
+		{ID: 6, Title: "A", Frequency: "low", Difficulty: "easy"},
+		{ID: 7, Title: "B", Frequency: "low", Difficulty: "easy"},
+	}
+	edge := func(from, to uint) models.KnowledgeRelation {
+		return models.KnowledgeRelation{FromPointID: from, ToPointID: to, RelationType: "prerequisite"}
+	}
+	relations := []models.KnowledgeRelation{
+		edge(1, 3), edge(3, 5), edge(2, 4), edge(4, 5), edge(6, 7), edge(7, 6),
+		{FromPointID: 1, ToPointID: 2, RelationType: "related"},
+		edge(99, 1), // 端点已删除
+	}
+	return newPrerequisiteGraph(points, relations, progresses)
+}
+
+func TestLearningPathPlan(t *testing.T) {
+	order, cycle := testGraph().plan(5)
+	if cycle != nil {
+		t.Fatalf("Unexpected cycle: %v", cycle)
+	}
+	// 就绪的知识点中高频、简单的优先：1 > 2，之后 3（高频）> 4
+	if want := []uint{1, 2, 3, 4, 5}; !slices.Equal(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+
+	// 已掌握的前置知识点不再展开
+	order, _ = testGraph(models.LearningProgress{KnowledgePointID: 3, Status: "completed"}).plan(5)
+	if want := []uint{2, 4, 5}; !slices.Equal(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+
+	order, _ = testGraph(models.LearningProgress{KnowledgePointID: 5, Status: "in_progress", MasteryLevel: 90}).plan(5)
+	if len(order) != 0 {
+		t.Errorf("Expected empty path for mastered target, got %v", order)
+	}
+}
+
+func TestLearningPathCycle(t *testing.T) {
+	g := testGraph()
+	order, cycle := g.plan(7)
+	if order != nil {
+		t.Errorf("Expected no order, got %v", order)
+	}
+	if want := []uint{7, 6, 7}; !slices.Equal(cycle, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycle)
+	}
+	if got := g.describe(cycle); got != "B → A → B" {
+		t.Errorf("Unexpected description: %q", got)
+	}
+}
+
+func TestNextTopics(t *testing.T) {
+	g := testGraph(
+		models.LearningProgress{KnowledgePointID: 1, Status: "completed"},
+		models.LearningProgress{KnowledgePointID: 4, Status: "in_progress", MasteryLevel: 40},
+	)
+	var ids []uint
+	for _, topic := range g.nextTopics(10) {
+		ids = append(ids, topic.KnowledgePointID)
+	}
+	// 4 的前置 2 未掌握，不推荐；6、7 在环上，不会就绪
+	if want := []uint{2, 3}; !slices.Equal(ids, want) {
+		t.Errorf("Expected topics %v, got %v", want, ids)
+	}
+
+	if topics := g.nextTopics(1); len(topics) != 1 || topics[0].Unlocks != 1 {
+		t.Errorf("Unexpected topics: %+v", topics)
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+)
+
+func TestAccountGuardKeyNormalizesEmail(t *testing.T) {
+	if accountGuardKey(" Alice@Example.com ") != accountGuardKey("alice@example.com") {
+		t.Error("Expected account key to ignore case and surrounding spaces")
+	}
+	if accountGuardKey("alice@example.com") == accountGuardKey("bob@example.com") {
+		t.Error("Expected different accounts to have different keys")
+	}
+}
+
+func TestFormatWait(t *testing.T) {
+	tests := []struct {
+		wait time.Duration
+		want string
+	}{
+		{200 * time.Millisecond, "1 秒"},
+		{42 * time.Second, "42 秒"},
+		{time.Minute, "1 分钟"},
+		{61 * time.Second, "2 分钟"},
+		{time.Hour, "60 分钟"},
+	}
+	for _, tt := range tests {
+		if got := formatWait(tt.wait); got != tt.want {
+			t.Errorf("formatWait(%v) = %q, want %q", tt.wait, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardWithoutBackendAllowsEverything(t *testing.T) {
+	guard := NewLoginGuard(nil, cache.LoginLockPolicy{}, cache.LoginLockPolicy{})
+	if err := guard.Check("a@example.com", "127.0.0.1"); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	if err := guard.Fail("a@example.com", "127.0.0.1"); err != nil {
+		t.Errorf("Fail: %v", err)
+	}
+	if err := guard.Succeed("a@example.com"); err != nil {
+		t.Errorf("Succeed: %v", err)
+	}
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/totp"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// mfaChallengeKey 登录第二步的挑战（令牌哈希）
+const mfaChallengeKey = "auth:mfa_challenge:%s"
+
+const (
+	defaultMFAChallengeTTL = 5 * time.Minute
+	mfaMaxAttempts         = 5  // 每个挑战允许输错验证码的次数
+	mfaSkew                = 1  // 允许前后各一个周期的时钟偏差
+	recoveryCodeCount      = 10 // 每次生成的恢复码数量
+	recoveryCodeLen        = 10 // 恢复码长度（不含分隔符）
+)
+
+var recoveryEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// mfaChallenge 密码或第三方登录通过后等待两步验证的登录
+type mfaChallenge struct {
+	UserID    uint      `json:"user_id"`
+	Method    string    `json:"method"`
+	Enroll    bool      `json:"enroll"` // 角色要求两步验证但用户尚未绑定，需要先完成绑定
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MFAService 两步验证服务：TOTP 绑定、验证码与恢复码校验、登录第二步的挑战
+type MFAService struct {
+	mfaRepo       *repository.MFARepository
+	userRepo      *repository.UserRepository
+	cache         *cache.Cache
+	box           *utils.SecretBox
+	issuer        string
+	requiredRoles []string
+	challengeTTL  time.Duration
+}
+
+// NewMFAService 创建两步验证服务，requiredRoles 中的角色必须启用两步验证
+func NewMFAService(
+	mfaRepo *repository.MFARepository,
+	userRepo *repository.UserRepository,
+	c *cache.Cache,
+	box *utils.SecretBox,
+	issuer string,
+	requiredRoles []string,
+	challengeTTL time.Duration,
+) *MFAService {
+	if challengeTTL <= 0 {
+		challengeTTL = defaultMFAChallengeTTL
+	}
+	return &MFAService{
+		mfaRepo:       mfaRepo,
+		userRepo:      userRepo,
+		cache:         c,
+		box:           box,
+		issuer:        issuer,
+		requiredRoles: requiredRoles,
+		challengeTTL:  challengeTTL,
+	}
+}
+
+// MFACodeRequest 验证码请求
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableMFARequest 关闭两步验证请求，需要密码与验证码（或恢复码）
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFAStatus 两步验证状态
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	Required               bool       `json:"required"` // 当前角色必须启用
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// MFAEnrollment 待确认的绑定：用验证器应用扫描 ProvisioningURI 的二维码或手动输入 Secret
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse 新生成的恢复码，只展示这一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Required 角色是否必须启用两步验证
+func (s *MFAService) Required(role string) bool {
+	return slices.Contains(s.requiredRoles, role)
+}
+
+// Enabled 用户是否已启用两步验证
+func (s *MFAService) Enabled(userID uint) (bool, error) {
+	mfa, err := s.mfaRepo.Get(userID)
+	if errors.Is(err, utils.ErrMFANotEnabled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return mfa.EnabledAt != nil, nil
+}
+
+// Status 获取用户的两步验证状态
+func (s *MFAService) Status(userID uint) (*MFAStatus, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &MFAStatus{Required: s.Required(user.Role)}
+
+	mfa, err := s.mfaRepo.Get(userID)
+	if errors.Is(err, utils.ErrMFANotEnabled) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mfa.EnabledAt == nil {
+		return status, nil
+	}
+
+	status.Enabled = true
+	status.EnabledAt = mfa.EnabledAt
+	status.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// BeginEnrollment 生成新密钥，等待用户输入验证码确认；重复调用会替换未确认的密钥
+func (s *MFAService) BeginEnrollment(userID uint) (*MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.box.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.SavePending(userID, sealed); err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable 校验验证码后启用两步验证，返回恢复码
+func (s *MFAService) Enable(userID uint, req *MFACodeRequest) (*RecoveryCodesResponse, error) {
+	mfa, err := s.mfaRepo.Get(userID)
+	if errors.Is(err, utils.ErrMFANotEnabled) {
+		return nil, utils.NewParamError("请先获取绑定密钥")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mfa.EnabledAt != nil {
+		return nil, utils.ErrMFAAlreadyEnabled
+	}
+
+	step, err := s.validateTOTP(mfa, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.Enable(userID, step, time.Now(), hashes); err != nil {
+		return nil, err
+	}
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 校验密码与验证码后关闭两步验证；角色要求两步验证时不能关闭
+func (s *MFAService) Disable(userID uint, req *DisableMFARequest) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if s.Required(user.Role) {
+		return utils.ErrMFARequired
+	}
+	if !utils.CheckPassword(req.Password, user.Password) {
+		return utils.NewParamError("密码不正确")
+	}
+	if err := s.Verify(userID, req.Code); err != nil {
+		return err
+	}
+	return s.mfaRepo.Delete(userID)
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧的全部作废
+func (s *MFAService) RegenerateRecoveryCodes(userID uint, req *MFACodeRequest) (*RecoveryCodesResponse, error) {
+	if err := s.Verify(userID, req.Code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Verify 校验 6 位验证码或恢复码；验证码在有效期内只能使用一次，恢复码用后作废
+func (s *MFAService) Verify(userID uint, code string) error {
+	mfa, err := s.mfaRepo.Get(userID)
+	if err != nil {
+		return err
+	}
+	if mfa.EnabledAt == nil {
+		return utils.ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, err := s.validateTOTP(mfa, code)
+		if err != nil {
+			return err
+		}
+		ok, err := s.mfaRepo.UseStep(userID, step)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return utils.ErrMFACodeInvalid
+		}
+		return nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeLen {
+		return utils.ErrMFACodeInvalid
+	}
+	ok, err := s.mfaRepo.UseRecoveryCode(userID, hashToken(normalized), time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return utils.ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// issueChallenge 签发登录第二步的挑战令牌；enroll 为 true 时用户需要先完成绑定
+func (s *MFAService) issueChallenge(userID uint, method string, enroll bool) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	challenge := mfaChallenge{
+		UserID:    userID,
+		Method:    method,
+		Enroll:    enroll,
+		ExpiresAt: time.Now().Add(s.challengeTTL),
+	}
+	if err := s.storeChallenge(token, &challenge); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// peekChallenge 读取挑战但不消耗，enroll 必须与挑战类型一致
+func (s *MFAService) peekChallenge(token string, enroll bool) (*mfaChallenge, error) {
+	raw, err := s.cache.Get(fmt.Sprintf(mfaChallengeKey, hashToken(token)))
+	if cache.IsNil(err) {
+		return nil, utils.ErrMFAChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var challenge mfaChallenge
+	if err := json.Unmarshal([]byte(raw), &challenge); err != nil || challenge.Enroll != enroll {
+		return nil, utils.ErrMFAChallengeInvalid
+	}
+	return &challenge, nil
+}
+
+// consumeChallenge 取出挑战并执行 fn；fn 成功后挑战作废，失败时计入尝试次数并放回，
+// 超过 mfaMaxAttempts 次后作废。取出与放回之间的并发请求会得到 ErrMFAChallengeInvalid
+func (s *MFAService) consumeChallenge(token string, enroll bool, fn func(*mfaChallenge) error) error {
+	key := fmt.Sprintf(mfaChallengeKey, hashToken(token))
+	raw, err := s.cache.GetDel(key)
+	if cache.IsNil(err) {
+		return utils.ErrMFAChallengeInvalid
+	}
+	if err != nil {
+		return err
+	}
+	var challenge mfaChallenge
+	if err := json.Unmarshal([]byte(raw), &challenge); err != nil || challenge.Enroll != enroll {
+		return utils.ErrMFAChallengeInvalid
+	}
+
+	err = fn(&challenge)
+	if err == nil {
+		return nil
+	}
+	challenge.Attempts++
+	if challenge.Attempts < mfaMaxAttempts {
+		if storeErr := s.storeChallenge(token, &challenge); storeErr != nil {
+			return storeErr
+		}
+	}
+	return err
+}
+
+// storeChallenge 保存挑战，有效期按 ExpiresAt 计算，已过期的不再保存
+func (s *MFAService) storeChallenge(token string, challenge *mfaChallenge) error {
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	raw, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(fmt.Sprintf(mfaChallengeKey, hashToken(token)), raw, ttl)
+}
+
+// validateTOTP 解密密钥并校验验证码，返回匹配的时间步
+func (s *MFAService) validateTOTP(mfa *models.UserMFA, code string) (int64, error) {
+	secret, err := s.box.Open(mfa.Secret)
+	if err != nil {
+		return 0, fmt.Errorf("decrypt totp secret: %w", err)
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), mfaSkew)
+	if !ok || step <= mfa.LastUsedStep {
+		return 0, utils.ErrMFACodeInvalid
+	}
+	return step, nil
+}
+
+// generateRecoveryCodes 生成恢复码（xxxxx-xxxxx）及其哈希
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	buf := make([]byte, recoveryCodeLen*5/8)
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		code := recoveryEncoding.EncodeToString(buf)
+		codes[i] = code[:recoveryCodeLen/2] + "-" + code[recoveryCodeLen/2:]
+		hashes[i] = hashToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode 忽略大小写、空格与分隔符
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
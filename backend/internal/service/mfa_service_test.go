@@ -0,0 +1,43 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("Expected %d codes, got %d codes and %d hashes", recoveryCodeCount, len(codes), len(hashes))
+	}
+
+	format := regexp.MustCompile(`^[a-km-np-z2-9]{5}-[a-km-np-z2-9]{5}$`)
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("Unexpected recovery code format %q", code)
+		}
+		if seen[code] {
+			t.Errorf("Duplicate recovery code %q", code)
+		}
+		seen[code] = true
+		// 用户输入时可能带空格、大写或省略分隔符
+		if got := hashToken(normalizeRecoveryCode(" " + strings.ToUpper(code[:5]) + " " + code[6:] + " ")); got != hashes[i] {
+			t.Errorf("Normalized code %q does not match its hash", code)
+		}
+	}
+}
+
+func TestRequiredRoles(t *testing.T) {
+	s := NewMFAService(nil, nil, nil, nil, "test", []string{"admin", "editor"}, 0)
+	if !s.Required("admin") || !s.Required("editor") || s.Required("learner") {
+		t.Error("Expected admin and editor to require mfa, learner not")
+	}
+	if s.challengeTTL != defaultMFAChallengeTTL {
+		t.Errorf("Expected default challenge ttl, got %v", s.challengeTTL)
+	}
+}
@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/oauth"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// Redis 键前缀
+const (
+	oauthStateKey = "auth:oauth_state:%s" // 进行中的授权请求（state 哈希）
+	oauthCodeKey  = "auth:oauth_code:%s"  // 回调签发给前端的一次性登录码（哈希）
+)
+
+// 未配置时的默认有效期
+const (
+	defaultOAuthStateTTL = 10 * time.Minute
+	oauthCodeTTL         = time.Minute
+)
+
+// 回调跳转到前端时的错误码
+const (
+	OAuthErrorDenied        = "access_denied"   // 用户在提供方拒绝授权
+	OAuthErrorInvalidState  = "invalid_state"   // 授权请求无效或已过期
+	OAuthErrorEmailRequired = "email_required"  // 第三方账号没有已验证的邮箱
+	OAuthErrorIdentityInUse = "identity_in_use" // 第三方账号已绑定其他用户
+	OAuthErrorLinked        = "already_linked"  // 用户已绑定该提供方的其他账号
+	OAuthErrorFailed        = "failed"          // 与提供方通信失败等其它错误
+)
+
+// errOAuthDenied 提供方回调携带 error 参数
+var errOAuthDenied = errors.New("oauth: authorization denied")
+
+// oauthFlow 授权请求，回调时取出并作废
+type oauthFlow struct {
+	Provider   string `json:"provider"`
+	Verifier   string `json:"verifier"`
+	Nonce      string `json:"nonce"`
+	LinkUserID uint   `json:"link_user_id,omitempty"` // 不为 0 时为已登录用户绑定第三方账号
+}
+
+// oauthLogin 一次性登录码对应的登录结果
+type oauthLogin struct {
+	UserID   uint   `json:"user_id"`
+	Provider string `json:"provider"`
+}
+
+// OAuthService 第三方登录服务：授权码 + PKCE 流程，外部账号绑定到用户
+type OAuthService struct {
+	providers    *oauth.Registry
+	identityRepo *repository.IdentityRepository
+	userRepo     *repository.UserRepository
+	authService  *AuthService
+	tokenService *TokenService
+	patService   *PersonalTokenService
+	cache        *cache.Cache
+	frontendURL  string
+	stateTTL     time.Duration
+}
+
+// NewOAuthService 创建第三方登录服务，frontendURL 为回调完成后跳转的前端地址
+func NewOAuthService(
+	providers *oauth.Registry,
+	identityRepo *repository.IdentityRepository,
+	userRepo *repository.UserRepository,
+	authService *AuthService,
+	tokenService *TokenService,
+	patService *PersonalTokenService,
+	c *cache.Cache,
+	frontendURL string,
+	stateTTL time.Duration,
+) *OAuthService {
+	if stateTTL <= 0 {
+		stateTTL = defaultOAuthStateTTL
+	}
+	return &OAuthService{
+		providers:    providers,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		tokenService: tokenService,
+		patService:   patService,
+		cache:        c,
+		frontendURL:  strings.TrimRight(frontendURL, "/"),
+		stateTTL:     stateTTL,
+	}
+}
+
+// OAuthCallbackRequest 提供方回调参数
+type OAuthCallbackRequest struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+	Error string `form:"error"`
+}
+
+// OAuthExchangeRequest 使用回调中的一次性登录码换取令牌
+type OAuthExchangeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// OAuthRedirect 授权地址；State 需要由处理器写入浏览器 Cookie，回调时校验
+type OAuthRedirect struct {
+	URL   string `json:"url"`
+	State string `json:"-"`
+}
+
+// Providers 已启用的提供方
+func (s *OAuthService) Providers() []oauth.ProviderInfo {
+	return s.providers.List()
+}
+
+// Begin 发起授权请求；linkUserID 不为 0 时回调把第三方账号绑定到该用户，否则为登录
+func (s *OAuthService) Begin(ctx context.Context, provider string, linkUserID uint) (*OAuthRedirect, error) {
+	p, ok := s.providers.Get(provider)
+	if !ok {
+		return nil, utils.ErrOAuthProviderNotFound
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		token, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = token
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	flow, err := json.Marshal(oauthFlow{Provider: provider, Verifier: verifier, Nonce: nonce, LinkUserID: linkUserID})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(fmt.Sprintf(oauthStateKey, hashToken(state)), flow, s.stateTTL); err != nil {
+		return nil, err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthRedirect{URL: authURL, State: state}, nil
+}
+
+// Callback 处理提供方回调，返回跳转到前端的地址：
+// 登录成功带一次性登录码 code，绑定成功带 linked，失败带 error 错误码。
+// browserState 为发起登录或绑定时写入浏览器的 state，防止他人的授权结果被登录或绑定到发起者的账号
+func (s *OAuthService) Callback(ctx context.Context, provider string, req *OAuthCallbackRequest, browserState string) string {
+	query, err := s.callback(ctx, provider, req, browserState)
+	if err != nil {
+		code := oauthErrorCode(err)
+		if code == OAuthErrorFailed {
+			log.Printf("OAuth callback from %s failed: %v", provider, err)
+		}
+		query = url.Values{"error": {code}}
+	}
+	return s.frontendURL + "/oauth/callback?" + query.Encode()
+}
+
+// callback 校验授权请求、换取第三方账号并登录或绑定
+func (s *OAuthService) callback(ctx context.Context, provider string, req *OAuthCallbackRequest, browserState string) (url.Values, error) {
+	if req.State == "" {
+		return nil, utils.ErrOAuthStateInvalid
+	}
+	raw, err := s.cache.GetDel(fmt.Sprintf(oauthStateKey, hashToken(req.State)))
+	if cache.IsNil(err) {
+		return nil, utils.ErrOAuthStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var flow oauthFlow
+	if err := json.Unmarshal([]byte(raw), &flow); err != nil || flow.Provider != provider {
+		return nil, utils.ErrOAuthStateInvalid
+	}
+	// 登录与绑定都必须回到发起它的浏览器：否则攻击者可以把自己发起的授权地址发给他人，
+	// 把他人的第三方账号登录到攻击者的浏览器，或绑定到攻击者的账号上
+	if subtle.ConstantTimeCompare([]byte(browserState), []byte(req.State)) != 1 {
+		return nil, utils.ErrOAuthStateInvalid
+	}
+	if req.Error != "" {
+		return nil, errOAuthDenied
+	}
+
+	p, ok := s.providers.Get(provider)
+	if !ok {
+		return nil, utils.ErrOAuthProviderNotFound
+	}
+	identity, err := p.Exchange(ctx, req.Code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if flow.LinkUserID != 0 {
+		if err := s.link(flow.LinkUserID, provider, identity); err != nil {
+			return nil, err
+		}
+		return url.Values{"linked": {provider}}, nil
+	}
+
+	user, err := s.resolveUser(provider, identity)
+	if err != nil {
+		return nil, err
+	}
+	code, err := s.issueCode(oauthLogin{UserID: user.ID, Provider: provider})
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{"code": {code}}, nil
+}
+
+// Exchange 使用一次性登录码换取令牌，登录码只能使用一次
+func (s *OAuthService) Exchange(req *OAuthExchangeRequest, client LoginClient) (*AuthResponse, error) {
+	raw, err := s.cache.GetDel(fmt.Sprintf(oauthCodeKey, hashToken(req.Code)))
+	if cache.IsNil(err) {
+		return nil, utils.ErrOAuthStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var login oauthLogin
+	if err := json.Unmarshal([]byte(raw), &login); err != nil {
+		return nil, utils.ErrOAuthStateInvalid
+	}
+	return s.authService.LoginExternal(login.UserID, models.LoginMethodOAuth+login.Provider, client)
+}
+
+// ListIdentities 获取用户绑定的第三方账号
+func (s *OAuthService) ListIdentities(userID uint) ([]models.UserIdentity, error) {
+	return s.identityRepo.ListByUser(userID)
+}
+
+// Unlink 解绑第三方账号；通过第三方登录创建的用户可以用找回密码设置密码
+func (s *OAuthService) Unlink(userID uint, provider string) error {
+	return s.identityRepo.Delete(userID, provider)
+}
+
+// resolveUser 查找第三方账号对应的用户：已绑定的直接登录；
+// 首次登录时按提供方验证过的邮箱关联已有用户，没有则创建新用户
+func (s *OAuthService) resolveUser(provider string, identity *oauth.Identity) (*models.User, error) {
+	now := time.Now()
+	existing, err := s.identityRepo.GetBySubject(provider, identity.Subject)
+	if err == nil {
+		if err := s.identityRepo.Touch(existing.ID, identity.Email, identityLogin(identity), now); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetByID(existing.UserID)
+	}
+	if !errors.Is(err, utils.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	// 只信任提供方验证过的邮箱，否则任何人都能用他人的邮箱注册第三方账号接管用户
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, utils.ErrOAuthEmailRequired
+	}
+	record := &models.UserIdentity{
+		Provider:    provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		Login:       identityLogin(identity),
+		LastLoginAt: &now,
+	}
+
+	user, err := s.userRepo.GetByEmail(identity.Email)
+	if errors.Is(err, utils.ErrUserNotFound) {
+		return s.createUser(identity, record, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record.UserID = user.ID
+	if err := s.identityRepo.Create(record); err != nil {
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		if err := s.claimUnverified(user, now); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// createUser 为第三方账号创建新用户，密码随机生成，之后可以通过找回密码设置
+func (s *OAuthService) createUser(identity *oauth.Identity, record *models.UserIdentity, now time.Time) (*models.User, error) {
+	password, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{
+		Email:           identity.Email,
+		Password:        password,
+		Username:        truncateRunes(oauthUsername(identity), 100),
+		Avatar:          truncateRunes(identity.AvatarURL, 500),
+		Role:            models.RoleLearner,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.identityRepo.CreateWithUser(user, record); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// claimUnverified 邮箱未验证的已有用户由提供方证明的邮箱所有者接管：标记邮箱已验证，
+// 并替换密码、注销全部会话与个人访问令牌，防止他人抢先用该邮箱注册后保留访问权限
+func (s *OAuthService) claimUnverified(user *models.User, now time.Time) error {
+	password, err := randomPasswordHash()
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, password); err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerified(user.ID, now); err != nil {
+		return err
+	}
+	user.EmailVerifiedAt = &now
+	if err := s.patService.RevokeAll(user.ID); err != nil {
+		return err
+	}
+	return s.tokenService.RevokeUser(user.ID)
+}
+
+// link 把第三方账号绑定到已登录用户，已绑定到该用户时只更新信息
+func (s *OAuthService) link(userID uint, provider string, identity *oauth.Identity) error {
+	existing, err := s.identityRepo.GetBySubject(provider, identity.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return utils.ErrIdentityInUse
+		}
+		return s.identityRepo.Touch(existing.ID, identity.Email, identityLogin(identity), time.Now())
+	}
+	if !errors.Is(err, utils.ErrIdentityNotFound) {
+		return err
+	}
+
+	linked, err := s.identityRepo.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+	for _, l := range linked {
+		if l.Provider == provider {
+			return utils.ErrIdentityAlreadyLinked
+		}
+	}
+	return s.identityRepo.Create(&models.UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		Login:    identityLogin(identity),
+	})
+}
+
+// issueCode 签发一次性登录码，前端用它换取令牌，令牌不会出现在跳转地址中
+func (s *OAuthService) issueCode(login oauthLogin) (string, error) {
+	code, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(login)
+	if err != nil {
+		return "", err
+	}
+	if err := s.cache.Set(fmt.Sprintf(oauthCodeKey, hashToken(code)), raw, oauthCodeTTL); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// oauthErrorCode 回调错误对应的前端错误码
+func oauthErrorCode(err error) string {
+	switch {
+	case errors.Is(err, errOAuthDenied):
+		return OAuthErrorDenied
+	case errors.Is(err, utils.ErrOAuthStateInvalid):
+		return OAuthErrorInvalidState
+	case errors.Is(err, utils.ErrOAuthEmailRequired):
+		return OAuthErrorEmailRequired
+	case errors.Is(err, utils.ErrIdentityInUse):
+		return OAuthErrorIdentityInUse
+	case errors.Is(err, utils.ErrIdentityAlreadyLinked):
+		return OAuthErrorLinked
+	}
+	return OAuthErrorFailed
+}
+
+// identityLogin 第三方账号的显示名称
+func identityLogin(identity *oauth.Identity) string {
+	if identity.Login != "" {
+		return truncateRunes(identity.Login, 255)
+	}
+	return truncateRunes(identity.Email, 255)
+}
+
+// oauthUsername 新用户的用户名：优先使用第三方账号的姓名，其次用户名，最后是邮箱前缀
+func oauthUsername(identity *oauth.Identity) string {
+	if name := strings.TrimSpace(identity.Name); name != "" {
+		return name
+	}
+	if identity.Login != "" {
+		return identity.Login
+	}
+	local, _, _ := strings.Cut(identity.Email, "@")
+	return local
+}
+
+// randomPasswordHash 随机密码的哈希，用于没有设置密码的用户
+func randomPasswordHash() (string, error) {
+	password, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return utils.HashPassword(password)
+}
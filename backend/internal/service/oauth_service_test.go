@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/config"
+	"eight-gu-learning-platform/internal/oauth"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+func TestOAuthUsername(t *testing.T) {
+	tests := []struct {
+		identity oauth.Identity
+		want     string
+	}{
+		{oauth.Identity{Name: " Ada Lovelace ", Login: "ada", Email: "ada@example.com"}, "Ada Lovelace"},
+		{oauth.Identity{Login: "octocat", Email: "octo@example.com"}, "octocat"},
+		{oauth.Identity{Email: "grace@example.com"}, "grace"},
+	}
+	for _, tt := range tests {
+		if got := oauthUsername(&tt.identity); got != tt.want {
+			t.Errorf("oauthUsername(%+v) = %q, want %q", tt.identity, got, tt.want)
+		}
+	}
+}
+
+func TestOAuthErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errOAuthDenied, OAuthErrorDenied},
+		{utils.ErrOAuthStateInvalid, OAuthErrorInvalidState},
+		{utils.ErrOAuthEmailRequired, OAuthErrorEmailRequired},
+		{fmt.Errorf("link: %w", utils.ErrIdentityInUse), OAuthErrorIdentityInUse},
+		{utils.ErrIdentityAlreadyLinked, OAuthErrorLinked},
+		{errors.New("oidc: invalid id_token"), OAuthErrorFailed},
+	}
+	for _, tt := range tests {
+		if got := oauthErrorCode(tt.err); got != tt.want {
+			t.Errorf("oauthErrorCode(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestOAuthLinkRequiresInitiatingBrowser 绑定的授权结果不能在其他浏览器中完成：
+// 攻击者把自己发起的绑定地址发给他人时，回调不带攻击者浏览器的 state Cookie
+func TestOAuthLinkRequiresInitiatingBrowser(t *testing.T) {
+	redis := testRedis(t)
+
+	var tokenRequests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests.Add(1)
+		}
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	providers, err := oauth.New(&config.OAuthConfig{
+		CallbackBaseURL: "http://backend",
+		Providers: map[string]config.OAuthProviderConfig{
+			"mock": {
+				Enabled:  true,
+				Type:     oauth.TypeGitHub,
+				ClientID: "client",
+				AuthURL:  srv.URL + "/authorize",
+				TokenURL: srv.URL + "/token",
+				APIURL:   srv.URL,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewOAuthService(providers, nil, nil, nil, nil, nil, redis, "http://frontend", time.Minute)
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		browserState func(state string) string
+		wantError    string
+		wantExchange bool
+	}{
+		{"no cookie", func(string) string { return "" }, OAuthErrorInvalidState, false},
+		{"other browser", func(string) string { return "attacker-state" }, OAuthErrorInvalidState, false},
+		{"initiating browser", func(state string) string { return state }, OAuthErrorFailed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redirect, err := svc.Begin(ctx, "mock", 42)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := tokenRequests.Load()
+
+			got := svc.Callback(ctx, "mock", &OAuthCallbackRequest{State: redirect.State, Code: "code"}, tt.browserState(redirect.State))
+			if !strings.Contains(got, "error="+tt.wantError) {
+				t.Errorf("Callback() = %q, want error=%s", got, tt.wantError)
+			}
+			if exchanged := tokenRequests.Load() > before; exchanged != tt.wantExchange {
+				t.Errorf("code exchanged = %v, want %v", exchanged, tt.wantExchange)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+const (
+	maxPersonalTokens          = 20          // 每个用户最多保留的令牌数量
+	personalTokenPrefixLen     = 12          // 展示用的令牌开头长度（含前缀）
+	personalTokenTouchInterval = time.Minute // 最近使用时间的更新间隔，避免每次请求都写库
+)
+
+// PersonalTokenService 个人访问令牌服务
+type PersonalTokenService struct {
+	tokenRepo *repository.PersonalTokenRepository
+	userRepo  *repository.UserRepository
+}
+
+// NewPersonalTokenService 创建个人访问令牌服务
+func NewPersonalTokenService(tokenRepo *repository.PersonalTokenRepository, userRepo *repository.UserRepository) *PersonalTokenService {
+	return &PersonalTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreatePersonalTokenRequest 创建令牌请求，不填有效期时令牌不过期
+type CreatePersonalTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,oneof=read write admin"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// CreatedPersonalToken 新建的令牌，Token 明文只返回这一次
+type CreatedPersonalToken struct {
+	*models.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// List 获取用户的令牌
+func (s *PersonalTokenService) List(userID uint) ([]models.PersonalAccessToken, error) {
+	return s.tokenRepo.ListByUser(userID)
+}
+
+// Create 创建令牌；admin 权限只能由编辑与管理员申请
+func (s *PersonalTokenService) Create(userID uint, req *CreatePersonalTokenRequest) (*CreatedPersonalToken, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, utils.NewParamError("名称不能为空")
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if slices.Contains(scopes, models.TokenScopeAdmin) && user.Role != models.RoleAdmin && user.Role != models.RoleEditor {
+		return nil, utils.NewParamError("当前角色不能申请 admin 权限")
+	}
+
+	count, err := s.tokenRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPersonalTokens {
+		return nil, utils.NewParamError("访问令牌数量已达上限，请先吊销不用的令牌")
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	plain := models.PersonalTokenPrefix + secret
+	token := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashToken(plain),
+		Prefix:    plain[:personalTokenPrefixLen],
+		Scopes:    scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	return &CreatedPersonalToken{PersonalAccessToken: token, Token: plain}, nil
+}
+
+// Revoke 吊销令牌
+func (s *PersonalTokenService) Revoke(userID, id uint) error {
+	return s.tokenRepo.Delete(userID, id)
+}
+
+// RevokeAll 吊销用户的全部令牌
+func (s *PersonalTokenService) RevokeAll(userID uint) error {
+	return s.tokenRepo.DeleteByUser(userID)
+}
+
+// VerifyPersonalToken 校验令牌，返回令牌所属用户的身份（角色按当前用户信息）与权限范围
+func (s *PersonalTokenService) VerifyPersonalToken(plain string) (*utils.Claims, []string, error) {
+	if !strings.HasPrefix(plain, models.PersonalTokenPrefix) {
+		return nil, nil, utils.ErrPersonalTokenInvalid
+	}
+	token, err := s.tokenRepo.GetByHash(hashToken(plain))
+	if errors.Is(err, utils.ErrPersonalTokenNotFound) {
+		return nil, nil, utils.ErrPersonalTokenInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if token.Expired(now) {
+		return nil, nil, utils.ErrPersonalTokenInvalid
+	}
+	user, err := s.userRepo.GetByID(token.UserID)
+	if errors.Is(err, utils.ErrUserNotFound) {
+		return nil, nil, utils.ErrPersonalTokenInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 记录失败不影响本次请求
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalTokenTouchInterval {
+		if err := s.tokenRepo.Touch(token.ID, now); err != nil {
+			log.Printf("Failed to touch personal access token %d: %v", token.ID, err)
+		}
+	}
+
+	return &utils.Claims{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Username: user.Username,
+		Role:     user.Role,
+	}, token.Scopes, nil
+}
@@ -45,14 +45,13 @@ func (s *ProgressService) Update(userID uint, req *UpdateProgressRequest) (*mode
 	progress, err := s.progressRepo.GetByUserAndKnowledge(userID, req.KnowledgePointID)
 	if err == utils.ErrProgressNotFound {
 		// 创建新进度
-		now := time.Now()
 		progress = &models.LearningProgress{
 			UserID:           userID,
 			KnowledgePointID: req.KnowledgePointID,
 			Status:           req.Status,
 			MasteryLevel:     req.MasteryLevel,
-			LastReviewedAt:   &now,
 		}
+		s.schedule(progress, req.Status, req.MasteryLevel)
 		if err := s.progressRepo.Create(progress); err != nil {
 			return nil, err
 		}
@@ -66,8 +65,52 @@ func (s *ProgressService) Update(userID uint, req *UpdateProgressRequest) (*mode
 	// 更新现有进度
 	progress.Status = req.Status
 	progress.MasteryLevel = req.MasteryLevel
+	s.schedule(progress, req.Status, req.MasteryLevel)
+
+	if err := s.progressRepo.Update(progress); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// schedule 根据自评掌握度更新复习计划，未开始的知识点不进入复习队列
+func (s *ProgressService) schedule(progress *models.LearningProgress, status string, mastery int) {
 	now := time.Now()
-	progress.LastReviewedAt = &now
+	if status == "not_started" {
+		progress.LastReviewedAt = &now
+		progress.DueAt = nil
+		return
+	}
+	applyReview(progress, QualityFromMastery(mastery), now)
+}
+
+// RecordReview 记录一次复习结果（如答题），并更新复习计划
+func (s *ProgressService) RecordReview(userID, knowledgePointID uint, quality int) (*models.LearningProgress, error) {
+	now := time.Now()
+
+	progress, err := s.progressRepo.GetByUserAndKnowledge(userID, knowledgePointID)
+	if err == utils.ErrProgressNotFound {
+		progress = &models.LearningProgress{
+			UserID:           userID,
+			KnowledgePointID: knowledgePointID,
+			Status:           "in_progress",
+		}
+		applyReview(progress, quality, now)
+		if err := s.progressRepo.Create(progress); err != nil {
+			return nil, err
+		}
+		return progress, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if progress.Status == "not_started" {
+		progress.Status = "in_progress"
+	}
+	applyReview(progress, quality, now)
 
 	if err := s.progressRepo.Update(progress); err != nil {
 		return nil, err
@@ -76,7 +119,19 @@ func (s *ProgressService) Update(userID uint, req *UpdateProgressRequest) (*mode
 	return progress, nil
 }
 
+// DueReviewRequest 待复习队列请求
+type DueReviewRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// GetDueReviews 获取当前到期的复习队列
+func (s *ProgressService) GetDueReviews(userID uint, req *DueReviewRequest) ([]models.LearningProgress, int64, error) {
+	offset := (req.Page - 1) * req.PageSize
+	return s.progressRepo.GetDue(userID, time.Now(), offset, req.PageSize)
+}
+
 // GetStats 获取学习统计
-func (s *ProgressService) GetStats(userID uint) (map[string]interface{}, error) {
+func (s *ProgressService) GetStats(userID uint) (*repository.LearningStats, error) {
 	return s.progressRepo.GetStats(userID)
 }
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+)
+
+// 复习评分（SM-2 质量分 0-5）
+const (
+	QualityBlackout  = 0 // 完全不记得
+	QualityIncorrect = 1 // 回答错误
+	QualityHard      = 3 // 勉强答对
+	QualityCorrect   = 4 // 答对
+	QualityPerfect   = 5 // 轻松答对
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// ReviewState 间隔复习状态
+type ReviewState struct {
+	EaseFactor   float64
+	IntervalDays int
+	Repetitions  int
+	Lapses       int
+}
+
+// NextReview 按 SM-2 算法计算下一次复习状态与到期时间
+func NextReview(state ReviewState, quality int, now time.Time) (ReviewState, time.Time) {
+	if quality < QualityBlackout {
+		quality = QualityBlackout
+	}
+	if quality > QualityPerfect {
+		quality = QualityPerfect
+	}
+	if state.EaseFactor < minEaseFactor {
+		state.EaseFactor = defaultEaseFactor
+	}
+
+	next := state
+	if quality >= QualityHard {
+		switch state.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = int(math.Round(float64(state.IntervalDays) * state.EaseFactor))
+		}
+		next.Repetitions = state.Repetitions + 1
+	} else {
+		// 遗忘：重新开始
+		next.Repetitions = 0
+		next.IntervalDays = 1
+		next.Lapses = state.Lapses + 1
+	}
+
+	// 调整难度系数
+	diff := float64(QualityPerfect - quality)
+	next.EaseFactor = state.EaseFactor + (0.1 - diff*(0.08+diff*0.02))
+	if next.EaseFactor < minEaseFactor {
+		next.EaseFactor = minEaseFactor
+	}
+
+	return next, now.AddDate(0, 0, next.IntervalDays)
+}
+
+// QualityFromMastery 将掌握度（0-100）换算为复习质量分
+func QualityFromMastery(mastery int) int {
+	return int(math.Round(float64(mastery) * QualityPerfect / 100))
+}
+
+// applyReview 将一次复习结果写入学习进度
+func applyReview(progress *models.LearningProgress, quality int, now time.Time) {
+	state := ReviewState{
+		EaseFactor:   progress.EaseFactor,
+		IntervalDays: progress.IntervalDays,
+		Repetitions:  progress.Repetitions,
+		Lapses:       progress.Lapses,
+	}
+
+	next, dueAt := NextReview(state, quality, now)
+	progress.EaseFactor = next.EaseFactor
+	progress.IntervalDays = next.IntervalDays
+	progress.Repetitions = next.Repetitions
+	progress.Lapses = next.Lapses
+	progress.DueAt = &dueAt
+	progress.LastReviewedAt = &now
+}
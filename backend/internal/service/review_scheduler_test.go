@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextReviewSuccessfulRecalls(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	state := ReviewState{}
+
+	// 连续答对：1 天、6 天、之后按难度系数放大
+	expected := []int{1, 6, 15}
+	for i, days := range expected {
+		var dueAt time.Time
+		state, dueAt = NextReview(state, QualityCorrect, now)
+		if state.IntervalDays != days {
+			t.Fatalf("round %d: expected interval %d, got %d", i+1, days, state.IntervalDays)
+		}
+		if !dueAt.Equal(now.AddDate(0, 0, days)) {
+			t.Errorf("round %d: unexpected due date %v", i+1, dueAt)
+		}
+	}
+
+	if state.Repetitions != 3 {
+		t.Errorf("Expected 3 repetitions, got %d", state.Repetitions)
+	}
+	if state.EaseFactor != defaultEaseFactor {
+		t.Errorf("Expected ease factor %.2f, got %.2f", defaultEaseFactor, state.EaseFactor)
+	}
+}
+
+func TestNextReviewLapse(t *testing.T) {
+	now := time.Now()
+	state := ReviewState{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3}
+
+	next, dueAt := NextReview(state, QualityIncorrect, now)
+	if next.Repetitions != 0 || next.IntervalDays != 1 {
+		t.Errorf("Expected reset to 1 day, got interval %d repetitions %d", next.IntervalDays, next.Repetitions)
+	}
+	if next.Lapses != 1 {
+		t.Errorf("Expected 1 lapse, got %d", next.Lapses)
+	}
+	if next.EaseFactor >= state.EaseFactor {
+		t.Errorf("Expected ease factor to drop, got %.2f", next.EaseFactor)
+	}
+	if !dueAt.Equal(now.AddDate(0, 0, 1)) {
+		t.Errorf("Expected due tomorrow, got %v", dueAt)
+	}
+}
+
+func TestNextReviewEaseFactorFloor(t *testing.T) {
+	state := ReviewState{EaseFactor: minEaseFactor}
+	for i := 0; i < 5; i++ {
+		state, _ = NextReview(state, QualityBlackout, time.Now())
+	}
+	if state.EaseFactor != minEaseFactor {
+		t.Errorf("Expected ease factor floor %.2f, got %.2f", minEaseFactor, state.EaseFactor)
+	}
+}
+
+func TestQualityFromMastery(t *testing.T) {
+	cases := map[int]int{0: 0, 20: 1, 50: 3, 79: 4, 100: 5}
+	for mastery, quality := range cases {
+		if got := QualityFromMastery(mastery); got != quality {
+			t.Errorf("QualityFromMastery(%d) = %d, expected %d", mastery, got, quality)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strings"
+
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// SearchService 全文检索服务
+type SearchService struct {
+	searchRepo *repository.SearchRepository
+}
+
+// NewSearchService 创建全文检索服务
+func NewSearchService(searchRepo *repository.SearchRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo}
+}
+
+// SearchRequest 检索请求
+type SearchRequest struct {
+	Query      string `form:"q" binding:"required,max=100"`
+	Type       string `form:"type" binding:"omitempty,oneof=knowledge exercise"`
+	CategoryID uint   `form:"category_id"`
+	Difficulty string `form:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+	Frequency  string `form:"frequency" binding:"omitempty,oneof=high medium low"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=50"`
+}
+
+// SearchResponse 检索结果
+type SearchResponse struct {
+	Items    []repository.SearchHit   `json:"items"`
+	Total    int64                    `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+	Facets   *repository.SearchFacets `json:"facets"`
+}
+
+// Search 检索知识点与练习题，按相关度排序并返回高亮摘要和分面统计
+func (s *SearchService) Search(req *SearchRequest) (*SearchResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, utils.NewParamError("搜索关键词不能为空")
+	}
+
+	hits, total, facets, err := s.searchRepo.Search(repository.SearchParams{
+		Query:      query,
+		Type:       req.Type,
+		CategoryID: req.CategoryID,
+		Difficulty: req.Difficulty,
+		Frequency:  req.Frequency,
+		Offset:     (req.Page - 1) * req.PageSize,
+		Limit:      req.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResponse{
+		Items:    hits,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Facets:   facets,
+	}, nil
+}
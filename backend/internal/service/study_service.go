@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// 未配置时的默认值
+const (
+	defaultStudyIdleTimeout = 5 * time.Minute
+	defaultStudySweep       = time.Minute
+)
+
+// StudyService 学习时长记录服务
+type StudyService struct {
+	studyRepo     *repository.StudyRepository
+	knowledgeRepo *repository.KnowledgeRepository
+	idleTimeout   time.Duration
+}
+
+// NewStudyService 创建学习时长记录服务，idleTimeout 为会话没有心跳时自动结束的时间
+func NewStudyService(
+	studyRepo *repository.StudyRepository,
+	knowledgeRepo *repository.KnowledgeRepository,
+	idleTimeout time.Duration,
+) *StudyService {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultStudyIdleTimeout
+	}
+	return &StudyService{
+		studyRepo:     studyRepo,
+		knowledgeRepo: knowledgeRepo,
+		idleTimeout:   idleTimeout,
+	}
+}
+
+// StartStudyRequest 开始学习请求
+type StartStudyRequest struct {
+	KnowledgePointID uint `json:"knowledge_point_id" binding:"required"`
+}
+
+// StudySessionView 学习会话及心跳约定
+type StudySessionView struct {
+	models.StudySession
+	IdleTimeoutSeconds int `json:"idle_timeout_seconds"` // 超过该时间没有心跳会自动结束
+}
+
+// Start 开始学习知识点，用户其它进行中的会话会被结束
+func (s *StudyService) Start(userID uint, req *StartStudyRequest) (*StudySessionView, error) {
+	if _, err := s.knowledgeRepo.GetByID(req.KnowledgePointID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.StudySession{
+		UserID:           userID,
+		KnowledgePointID: req.KnowledgePointID,
+		Status:           models.StudySessionActive,
+		StartedAt:        now,
+		LastHeartbeatAt:  now,
+	}
+	if err := s.studyRepo.Start(session, now.Add(-s.idleTimeout)); err != nil {
+		return nil, err
+	}
+	return s.view(session), nil
+}
+
+// Heartbeat 记录心跳；会话已结束或已空闲超时时返回 ErrStudySessionEnded，客户端应重新开始会话
+func (s *StudyService) Heartbeat(userID, sessionID uint) (*StudySessionView, error) {
+	session, err := s.load(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.StudySessionActive {
+		return nil, utils.ErrStudySessionEnded
+	}
+
+	now := time.Now()
+	session.LastHeartbeatAt = now
+	session.DurationSeconds = studySeconds(session.StartedAt, now)
+	if err := s.studyRepo.Touch(session); err != nil {
+		return nil, err
+	}
+	return s.view(session), nil
+}
+
+// End 结束学习会话；已结束的会话直接返回
+func (s *StudyService) End(userID, sessionID uint) (*StudySessionView, error) {
+	session, err := s.load(userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.StudySessionActive {
+		return s.view(session), nil
+	}
+
+	now := time.Now()
+	session.Status = models.StudySessionEnded
+	session.LastHeartbeatAt = now
+	session.EndedAt = &now
+	session.DurationSeconds = studySeconds(session.StartedAt, now)
+	if err := s.studyRepo.Finish(session); err != nil {
+		if err != utils.ErrStudySessionEnded {
+			return nil, err
+		}
+		// 已被并发请求结束，返回最新状态
+		if session, err = s.studyRepo.GetByID(sessionID); err != nil {
+			return nil, err
+		}
+	}
+	return s.view(session), nil
+}
+
+// ExpireIdle 结束所有空闲超时的会话
+func (s *StudyService) ExpireIdle() (int64, error) {
+	return s.studyRepo.ExpireIdle(time.Now().Add(-s.idleTimeout))
+}
+
+// RunExpirer 每隔 interval 结束空闲超时的会话，直到 ctx 取消
+func (s *StudyService) RunExpirer(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultStudySweep
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExpireIdle(); err != nil {
+				log.Printf("Failed to expire idle study sessions: %v", err)
+			}
+		}
+	}
+}
+
+// load 加载会话并校验归属；空闲超时但尚未被后台结束的会话在此结束，时长计到最后一次心跳
+func (s *StudyService) load(userID, sessionID uint) (*models.StudySession, error) {
+	session, err := s.studyRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, utils.ErrStudySessionNotFound
+	}
+
+	if idleBefore := time.Now().Add(-s.idleTimeout); session.Status == models.StudySessionActive && session.LastHeartbeatAt.Before(idleBefore) {
+		if err := s.studyRepo.Expire(session.ID, idleBefore); err != nil {
+			return nil, err
+		}
+		return s.studyRepo.GetByID(sessionID)
+	}
+	return session, nil
+}
+
+// view 附带心跳约定的会话
+func (s *StudyService) view(session *models.StudySession) *StudySessionView {
+	return &StudySessionView{
+		StudySession:       *session,
+		IdleTimeoutSeconds: int(s.idleTimeout.Seconds()),
+	}
+}
+
+// studySeconds 学习时长（秒）
+func studySeconds(startedAt, until time.Time) int {
+	return max(int(until.Sub(startedAt).Seconds()), 0)
+}
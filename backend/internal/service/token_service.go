@@ -0,0 +1,224 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"eight-gu-learning-platform/internal/cache"
+	"eight-gu-learning-platform/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Redis 键前缀
+const (
+	refreshTokenKey  = "auth:refresh:%s"           // 有效的刷新令牌
+	refreshUsedKey   = "auth:refresh_used:%s"      // 已轮换的刷新令牌（用于重放检测）
+	familyRevokedKey = "auth:family_revoked:%s"    // 已注销的令牌族
+	userFamiliesKey  = "auth:user_families:%d"     // 用户的令牌族集合
+	deniedTokenKey   = "auth:deny:%s"              // 访问令牌黑名单（jti）
+	userRevokedKey   = "auth:revoked_before_ms:%d" // 早于该时间（毫秒）签发的访问令牌失效
+)
+
+// refreshSession 刷新令牌会话
+type refreshSession struct {
+	UserID uint   `json:"user_id"`
+	Family string `json:"family"`
+}
+
+// TokenService 令牌服务，管理刷新令牌轮换与访问令牌吊销
+type TokenService struct {
+	cache      *cache.Cache
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenService 创建令牌服务
+func NewTokenService(c *cache.Cache, accessTTL, refreshTTL time.Duration) *TokenService {
+	return &TokenService{
+		cache:      c,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// IssueRefreshToken 为用户签发新的刷新令牌（新的令牌族）
+func (s *TokenService) IssueRefreshToken(userID uint) (string, error) {
+	family := uuid.New().String()
+	if err := s.cache.SetAdd(fmt.Sprintf(userFamiliesKey, userID), family, s.refreshTTL); err != nil {
+		return "", err
+	}
+	return s.issue(refreshSession{UserID: userID, Family: family})
+}
+
+// Rotate 使用刷新令牌换取新的刷新令牌，旧令牌立即失效；
+// 已轮换的令牌被再次使用时视为泄露，注销整个令牌族
+func (s *TokenService) Rotate(refreshToken string) (uint, string, error) {
+	hash := hashToken(refreshToken)
+
+	raw, err := s.cache.GetDel(fmt.Sprintf(refreshTokenKey, hash))
+	if cache.IsNil(err) {
+		family, usedErr := s.cache.Get(fmt.Sprintf(refreshUsedKey, hash))
+		if usedErr == nil {
+			if err := s.revokeFamily(family); err != nil {
+				return 0, "", err
+			}
+			return 0, "", utils.ErrRefreshTokenReused
+		}
+		if !cache.IsNil(usedErr) {
+			return 0, "", usedErr
+		}
+		return 0, "", utils.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	var session refreshSession
+	if err := json.Unmarshal([]byte(raw), &session); err != nil {
+		return 0, "", utils.ErrRefreshTokenInvalid
+	}
+
+	revoked, err := s.cache.Exists(fmt.Sprintf(familyRevokedKey, session.Family))
+	if err != nil {
+		return 0, "", err
+	}
+	if revoked {
+		return 0, "", utils.ErrRefreshTokenInvalid
+	}
+
+	// 记录已使用的令牌，用于重放检测
+	if err := s.cache.Set(fmt.Sprintf(refreshUsedKey, hash), session.Family, s.refreshTTL); err != nil {
+		return 0, "", err
+	}
+	// 令牌族随轮换续期，用户的令牌族集合也要同步续期，否则 RevokeUser 找不到仍然有效的令牌族
+	if err := s.cache.SetAdd(fmt.Sprintf(userFamiliesKey, session.UserID), session.Family, s.refreshTTL); err != nil {
+		return 0, "", err
+	}
+
+	newToken, err := s.issue(session)
+	if err != nil {
+		return 0, "", err
+	}
+	return session.UserID, newToken, nil
+}
+
+// RevokeRefreshToken 注销刷新令牌所在的令牌族
+func (s *TokenService) RevokeRefreshToken(refreshToken string) error {
+	key := fmt.Sprintf(refreshTokenKey, hashToken(refreshToken))
+	raw, err := s.cache.GetDel(key)
+	if cache.IsNil(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var session refreshSession
+	if err := json.Unmarshal([]byte(raw), &session); err != nil {
+		return nil
+	}
+	return s.revokeFamily(session.Family)
+}
+
+// DenyAccessToken 将访问令牌加入黑名单直至其过期
+func (s *TokenService) DenyAccessToken(claims *utils.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.cache.Set(fmt.Sprintf(deniedTokenKey, claims.ID), 1, ttl)
+}
+
+// RevokeUser 注销用户的全部会话（登出所有设备、修改密码、变更角色时使用）
+func (s *TokenService) RevokeUser(userID uint) error {
+	// 精确到毫秒：同一秒内吊销之前签发的令牌也要失效，而吊销之后立即签发的新令牌（如修改密码）仍然有效
+	now := time.Now().UnixMilli()
+	if err := s.cache.Set(fmt.Sprintf(userRevokedKey, userID), now, s.accessTTL); err != nil {
+		return err
+	}
+
+	families, err := s.cache.SetMembers(fmt.Sprintf(userFamiliesKey, userID))
+	if err != nil {
+		return err
+	}
+	for _, family := range families {
+		if err := s.revokeFamily(family); err != nil {
+			return err
+		}
+	}
+	return s.cache.Delete(fmt.Sprintf(userFamiliesKey, userID))
+}
+
+// IsRevoked 检查访问令牌是否已被吊销
+func (s *TokenService) IsRevoked(claims *utils.Claims) (bool, error) {
+	if claims.ID != "" {
+		denied, err := s.cache.Exists(fmt.Sprintf(deniedTokenKey, claims.ID))
+		if err != nil {
+			return false, err
+		}
+		if denied {
+			return true, nil
+		}
+	}
+
+	raw, err := s.cache.Get(fmt.Sprintf(userRevokedKey, claims.UserID))
+	if cache.IsNil(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	revokedBefore, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	return claims.IssuedAt == nil || claims.IssuedAt.UnixMilli() < revokedBefore, nil
+}
+
+// issue 生成刷新令牌并保存会话
+func (s *TokenService) issue(session refreshSession) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	if err := s.cache.Set(fmt.Sprintf(refreshTokenKey, hashToken(token)), payload, s.refreshTTL); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// revokeFamily 注销令牌族，该族下所有刷新令牌均不可再使用
+func (s *TokenService) revokeFamily(family string) error {
+	return s.cache.Set(fmt.Sprintf(familyRevokedKey, family), 1, s.refreshTTL)
+}
+
+// randomToken 生成 256 位随机令牌（URL 安全）
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken 计算令牌哈希，Redis 中不保存令牌明文
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
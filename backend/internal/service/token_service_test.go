@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// 通过轮换保持登录超过 refreshTTL 后，注销全部会话仍要使当前的刷新令牌失效
+func TestRevokeUserAfterRotatingPastRefreshTTL(t *testing.T) {
+	tokenService := NewTokenService(testRedis(t), time.Minute, time.Second)
+	userID := uint(time.Now().UnixNano() % 1_000_000_000)
+
+	token, err := tokenService.IssueRefreshToken(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		time.Sleep(600 * time.Millisecond)
+		if _, token, err = tokenService.Rotate(token); err != nil {
+			t.Fatalf("Rotate %d: %v", i, err)
+		}
+	}
+
+	if err := tokenService.RevokeUser(userID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := tokenService.Rotate(token); !errors.Is(err, utils.ErrRefreshTokenInvalid) {
+		t.Errorf("Expected revoked refresh token to be rejected, got %v", err)
+	}
+}
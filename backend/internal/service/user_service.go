@@ -3,17 +3,20 @@ package service
 import (
 	"eight-gu-learning-platform/internal/models"
 	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
 )
 
 // UserService 用户服务
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo     *repository.UserRepository
+	tokenService *TokenService
 }
 
 // NewUserService 创建用户服务
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo *repository.UserRepository, tokenService *TokenService) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
 	}
 }
 
@@ -23,6 +26,52 @@ type UpdateUserRequest struct {
 	Avatar   string `json:"avatar"`
 }
 
+// UserListRequest 用户列表请求
+type UserListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Role     string `form:"role" binding:"omitempty,oneof=learner editor admin"`
+	Search   string `form:"search"`
+}
+
+// UpdateRoleRequest 更新角色请求
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=learner editor admin"`
+}
+
+// List 获取用户列表
+func (s *UserService) List(req *UserListRequest) ([]models.User, int64, error) {
+	offset := (req.Page - 1) * req.PageSize
+	return s.userRepo.List(offset, req.PageSize, req.Role, req.Search)
+}
+
+// UpdateRole 更新用户角色（管理员不能修改自己的角色）
+func (s *UserService) UpdateRole(operatorID, id uint, role string) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, utils.NewParamError("角色不合法")
+	}
+	if operatorID == id {
+		return nil, utils.NewForbiddenError("不能修改自己的角色")
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateRole(id, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+
+	// 角色写在访问令牌中，注销旧会话使新角色立即生效
+	if err := s.tokenService.RevokeUser(id); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 // GetByID 根据 ID 获取用户
 func (s *UserService) GetByID(id uint) (*models.User, error) {
 	return s.userRepo.GetByID(id)
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"time"
+
+	"eight-gu-learning-platform/internal/models"
+	"eight-gu-learning-platform/internal/repository"
+	"eight-gu-learning-platform/internal/utils"
+)
+
+// defaultPracticeCount 错题再练习默认题数
+const defaultPracticeCount = 10
+
+// WrongListRequest 错题列表请求
+type WrongListRequest struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Status      string `form:"status" binding:"omitempty,oneof=outstanding mastered dismissed all"` // 默认 outstanding
+	KnowledgeID uint   `form:"knowledge_id"`
+}
+
+// MarkWrongRequest 标记错题请求，outstanding 表示取消标记
+type MarkWrongRequest struct {
+	Status string `json:"status" binding:"required,oneof=mastered dismissed outstanding"`
+}
+
+// WrongPracticeRequest 错题再练习请求
+type WrongPracticeRequest struct {
+	Count       int  `json:"count" binding:"omitempty,min=1,max=50"`
+	KnowledgeID uint `json:"knowledge_id"`
+}
+
+// WrongExercise 错题（按练习题汇总）
+type WrongExercise struct {
+	ID               uint       `json:"id"` // 练习题 ID
+	KnowledgePointID uint       `json:"knowledge_point_id"`
+	Type             string     `json:"type"`
+	Difficulty       string     `json:"difficulty"`
+	Question         string     `json:"question"`
+	UserAnswer       string     `json:"user_answer"` // 最近一次错误答案
+	CorrectAnswer    string     `json:"correct_answer"`
+	Explanation      string     `json:"explanation"`
+	WrongCount       int        `json:"wrong_count"`
+	AttemptCount     int        `json:"attempt_count"`
+	LastAttemptAt    time.Time  `json:"last_attempt_at"`
+	LastWrongAt      time.Time  `json:"last_wrong_at"`
+	LastCorrectAt    *time.Time `json:"last_correct_at"`
+	Corrected        bool       `json:"corrected"` // 最近一次答错之后是否答对过
+	Status           string     `json:"status"`    // outstanding / mastered / dismissed
+}
+
+// WrongPracticeSet 错题再练习题组
+type WrongPracticeSet struct {
+	Items []PublicExercise `json:"items"`
+	Total int              `json:"total"`
+}
+
+// GetWrongList 获取错题本：按练习题汇总答错次数、最近作答时间以及之后是否答对
+func (s *ExerciseService) GetWrongList(userID uint, req *WrongListRequest) ([]WrongExercise, int64, error) {
+	filter := repository.WrongStatsFilter{Status: req.Status, KnowledgeID: req.KnowledgeID}
+	switch filter.Status {
+	case "":
+		filter.Status = models.WrongStatusOutstanding
+	case "all":
+		filter.Status = ""
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	stats, total, err := s.recordRepo.GetWrongStats(userID, filter, offset, req.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	wrongExercises := make([]WrongExercise, 0, len(stats))
+	for _, st := range stats {
+		wrongExercises = append(wrongExercises, WrongExercise{
+			ID:               st.ExerciseID,
+			KnowledgePointID: st.KnowledgePointID,
+			Type:             st.Exercise.Type,
+			Difficulty:       st.Exercise.Difficulty,
+			Question:         st.Exercise.Question,
+			UserAnswer:       st.LastWrongAnswer,
+			CorrectAnswer:    st.Exercise.Answer,
+			Explanation:      st.Exercise.Explanation,
+			WrongCount:       st.WrongCount,
+			AttemptCount:     st.AttemptCount,
+			LastAttemptAt:    st.LastAttemptAt,
+			LastWrongAt:      st.LastWrongAt,
+			LastCorrectAt:    st.LastCorrectAt,
+			Corrected:        st.Corrected,
+			Status:           st.Status,
+		})
+	}
+
+	return wrongExercises, total, nil
+}
+
+// MarkWrong 标记错题为已掌握或移出错题本；再次答错后会重新计为待巩固
+func (s *ExerciseService) MarkWrong(userID, exerciseID uint, req *MarkWrongRequest) error {
+	wrong, err := s.recordRepo.HasWrongAnswer(userID, exerciseID)
+	if err != nil {
+		return err
+	}
+	if !wrong {
+		return utils.NewNotFoundError("错题不存在")
+	}
+
+	if req.Status == models.WrongStatusOutstanding {
+		return s.recordRepo.DeleteWrongMark(userID, exerciseID)
+	}
+	return s.recordRepo.SaveWrongMark(&models.WrongExerciseMark{
+		UserID:     userID,
+		ExerciseID: exerciseID,
+		Status:     req.Status,
+		MarkedAt:   time.Now(),
+	})
+}
+
+// PracticeWrong 从待巩固的错题中生成再练习题组，作答仍通过提交答案接口
+func (s *ExerciseService) PracticeWrong(userID uint, req *WrongPracticeRequest) (*WrongPracticeSet, error) {
+	count := req.Count
+	if count == 0 {
+		count = defaultPracticeCount
+	}
+
+	stats, err := s.recordRepo.GetPracticeCandidates(userID, req.KnowledgeID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := make([]PublicExercise, 0, len(stats))
+	for i := range stats {
+		exercises = append(exercises, toPublicExercise(&stats[i].Exercise))
+	}
+
+	return &WrongPracticeSet{Items: exercises, Total: len(exercises)}, nil
+}
@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量（取后 6 位）
+func TestCodeAtRFC6238(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := CodeAt(secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("CodeAt(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	current, _ := CodeAt(secret, Step(now))
+	previous, _ := CodeAt(secret, Step(now)-1)
+	stale, _ := CodeAt(secret, Step(now)-2)
+
+	if step, ok := Validate(secret, current, now, 1); !ok || step != Step(now) {
+		t.Errorf("Expected current code to match step %d, got %d %v", Step(now), step, ok)
+	}
+	if step, ok := Validate(secret, " "+previous+" ", now, 1); !ok || step != Step(now)-1 {
+		t.Errorf("Expected previous code within skew to match, got %d %v", step, ok)
+	}
+	if _, ok := Validate(secret, stale, now, 1); ok {
+		t.Error("Expected code outside skew to be rejected")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Error("Expected short code to be rejected")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("八股学习平台", "ada@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/%E5%85%AB%E8%82%A1%E5%AD%A6%E4%B9%A0%E5%B9%B3%E5%8F%B0:ada@example.com?") {
+		t.Errorf("Unexpected label in %s", uri)
+	}
+	for _, part := range []string{"secret=JBSWY3DPEHPK3PXP", "digits=6", "period=30", "algorithm=SHA1"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("Expected %q in %s", part, uri)
+		}
+	}
+}
@@ -4,13 +4,39 @@ import "errors"
 
 var (
 	// 用户相关错误
-	ErrUserNotFound      = errors.New("用户不存在")
-	ErrEmailAlreadyUsed  = errors.New("邮箱已被使用")
-	ErrPasswordIncorrect = errors.New("密码错误")
+	ErrUserNotFound       = errors.New("用户不存在")
+	ErrEmailAlreadyUsed   = errors.New("邮箱已被使用")
+	ErrInvalidCredentials = errors.New("邮箱或密码错误")
+	ErrEmailNotVerified   = errors.New("邮箱尚未验证，请先完成邮箱验证")
+	ErrEmailTokenInvalid  = errors.New("链接无效或已过期")
+
+	// 第三方登录相关错误
+	ErrOAuthProviderNotFound = errors.New("不支持的登录方式")
+	ErrOAuthStateInvalid     = errors.New("登录请求无效或已过期，请重新登录")
+	ErrOAuthEmailRequired    = errors.New("第三方账号没有已验证的邮箱")
+	ErrIdentityInUse         = errors.New("该第三方账号已绑定其他用户")
+	ErrIdentityAlreadyLinked = errors.New("已绑定该登录方式的其他账号，请先解绑")
+	ErrIdentityNotFound      = errors.New("未绑定该第三方账号")
+
+	// 两步验证相关错误
+	ErrMFANotEnabled       = errors.New("未启用两步验证")
+	ErrMFAAlreadyEnabled   = errors.New("已启用两步验证")
+	ErrMFACodeInvalid      = errors.New("验证码错误")
+	ErrMFAChallengeInvalid = errors.New("两步验证已过期，请重新登录")
+	ErrMFARequired         = errors.New("当前角色必须启用两步验证")
+
+	// 令牌相关错误
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用，相关会话已全部注销")
+
+	// 个人访问令牌相关错误
+	ErrPersonalTokenNotFound = errors.New("访问令牌不存在")
+	ErrPersonalTokenInvalid  = errors.New("访问令牌无效或已过期")
 
 	// 知识点相关错误
 	ErrKnowledgeNotFound = errors.New("知识点不存在")
 	ErrCategoryNotFound  = errors.New("分类不存在")
+	ErrRelationNotFound  = errors.New("知识关联不存在")
 
 	// 学习进度相关错误
 	ErrProgressNotFound = errors.New("学习进度不存在")
@@ -18,6 +44,15 @@ var (
 	// 练习题相关错误
 	ErrExerciseNotFound = errors.New("练习题不存在")
 	ErrAnswerIncorrect  = errors.New("答案错误")
+	ErrSandboxDisabled  = errors.New("代码题判分未启用")
+
+	// 考试相关错误
+	ErrExamNotFound = errors.New("考试不存在")
+	ErrExamFinished = errors.New("考试已结束")
+
+	// 学习时长相关错误
+	ErrStudySessionNotFound = errors.New("学习会话不存在")
+	ErrStudySessionEnded    = errors.New("学习会话已结束")
 )
 
 // AppError 应用错误
@@ -62,6 +97,11 @@ func NewConflictError(message string) *AppError {
 	return NewAppError(CodeErrorConflict, message, nil)
 }
 
+// NewForbiddenError 创建禁止访问错误
+func NewForbiddenError(message string) *AppError {
+	return NewAppError(CodeErrorForbidden, message, nil)
+}
+
 // NewUnauthorizedError 创建未授权错误
 func NewUnauthorizedError() *AppError {
 	return NewAppError(CodeErrorUnauthorized, "", nil)
@@ -5,8 +5,14 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// iat 等时间声明精确到毫秒，吊销检查按毫秒比较签发时间
+func init() {
+	jwt.TimePrecision = time.Millisecond
+}
+
 // JWTManager JWT 管理器
 type JWTManager struct {
 	secret     []byte
@@ -19,6 +25,7 @@ type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Email    string `json:"email"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -32,13 +39,15 @@ func NewJWTManager(secret string, expireTime time.Duration, issuer string) *JWTM
 }
 
 // GenerateToken 生成 Token
-func (j *JWTManager) GenerateToken(userID uint, email, username string) (string, error) {
+func (j *JWTManager) GenerateToken(userID uint, email, username, role string) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		UserID:   userID,
 		Email:    email,
 		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			Issuer:    j.issuer,
 			Subject:   email,
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.expireTime)),
@@ -76,17 +85,7 @@ func (j *JWTManager) ValidateToken(tokenString string) bool {
 	return err == nil
 }
 
-// RefreshToken 刷新 Token
-func (j *JWTManager) RefreshToken(tokenString string) (string, error) {
-	claims, err := j.ParseToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-
-	// 检查是否即将过期（1天内）
-	if time.Until(claims.ExpiresAt.Time) > 24*time.Hour {
-		return "", errors.New("token is still valid")
-	}
-
-	return j.GenerateToken(claims.UserID, claims.Email, claims.Username)
+// ExpireTime 获取 Token 有效期
+func (j *JWTManager) ExpireTime() time.Duration {
+	return j.expireTime
 }
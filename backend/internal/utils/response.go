@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +30,7 @@ const (
 	CodeErrorForbidden       = 1003
 	CodeErrorNotFound        = 1004
 	CodeErrorConflict        = 1005
+	CodeErrorTooManyRequests = 1006
 	CodeErrorDatabase        = 2001
 	CodeErrorCache           = 2002
 	CodeErrorInternal        = 3001
@@ -42,6 +44,7 @@ var errorMessages = map[int]string{
 	CodeErrorForbidden:       "禁止访问",
 	CodeErrorNotFound:        "资源不存在",
 	CodeErrorConflict:        "资源已存在",
+	CodeErrorTooManyRequests: "请求过于频繁，请稍后再试",
 	CodeErrorDatabase:        "数据库错误",
 	CodeErrorCache:           "缓存错误",
 	CodeErrorInternal:        "服务器内部错误",
@@ -130,6 +133,11 @@ func ConflictError(c *gin.Context, message string) {
 	Error(c, CodeErrorConflict, message)
 }
 
+// TooManyRequestsError 请求过于频繁
+func TooManyRequestsError(c *gin.Context, message string) {
+	Error(c, CodeErrorTooManyRequests, message)
+}
+
 // DatabaseError 数据库错误
 func DatabaseError(c *gin.Context, message string) {
 	if message == "" {
@@ -146,6 +154,52 @@ func InternalError(c *gin.Context, message string) {
 	Error(c, CodeErrorInternal, message)
 }
 
+// HandleError 根据错误类型返回对应的错误响应
+func HandleError(c *gin.Context, err error) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		Error(c, appErr.Code, appErr.Message)
+		return
+	}
+
+	switch {
+	case errors.Is(err, ErrUserNotFound),
+		errors.Is(err, ErrKnowledgeNotFound),
+		errors.Is(err, ErrCategoryNotFound),
+		errors.Is(err, ErrRelationNotFound),
+		errors.Is(err, ErrProgressNotFound),
+		errors.Is(err, ErrExerciseNotFound),
+		errors.Is(err, ErrExamNotFound),
+		errors.Is(err, ErrStudySessionNotFound),
+		errors.Is(err, ErrOAuthProviderNotFound),
+		errors.Is(err, ErrIdentityNotFound),
+		errors.Is(err, ErrPersonalTokenNotFound):
+		NotFoundError(c, err.Error())
+	case errors.Is(err, ErrExamFinished),
+		errors.Is(err, ErrStudySessionEnded):
+		ConflictError(c, err.Error())
+	case errors.Is(err, ErrEmailAlreadyUsed),
+		errors.Is(err, ErrIdentityInUse),
+		errors.Is(err, ErrIdentityAlreadyLinked),
+		errors.Is(err, ErrMFAAlreadyEnabled):
+		ConflictError(c, err.Error())
+	case errors.Is(err, ErrEmailTokenInvalid),
+		errors.Is(err, ErrOAuthStateInvalid),
+		errors.Is(err, ErrOAuthEmailRequired),
+		errors.Is(err, ErrMFANotEnabled),
+		errors.Is(err, ErrMFACodeInvalid):
+		ParamError(c, err.Error())
+	case errors.Is(err, ErrEmailNotVerified),
+		errors.Is(err, ErrMFARequired):
+		Error(c, CodeErrorForbidden, err.Error())
+	case errors.Is(err, ErrMFAChallengeInvalid),
+		errors.Is(err, ErrPersonalTokenInvalid):
+		Error(c, CodeErrorUnauthorized, err.Error())
+	default:
+		InternalError(c, err.Error())
+	}
+}
+
 // getHTTPStatus 根据错误码获取 HTTP 状态码
 func getHTTPStatus(code int) int {
 	switch code {
@@ -159,6 +213,8 @@ func getHTTPStatus(code int) int {
 		return http.StatusForbidden
 	case CodeErrorNotFound:
 		return http.StatusNotFound
+	case CodeErrorTooManyRequests:
+		return http.StatusTooManyRequests
 	case CodeErrorDatabase, CodeErrorCache:
 		return http.StatusInternalServerError
 	default:
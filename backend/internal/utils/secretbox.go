@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// SecretBox 使用 AES-256-GCM 加密保存到数据库的敏感数据（如 TOTP 密钥）
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox 创建加密器，密钥为任意字符串，经 SHA-256 派生为 256 位密钥
+func NewSecretBox(key string) (*SecretBox, error) {
+	if key == "" {
+		return nil, errors.New("encryption key is required")
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal 加密，返回 Base64 编码的 nonce + 密文
+func (b *SecretBox) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open 解密 Seal 的结果
+func (b *SecretBox) Open(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < b.aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, data := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, data, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,32 @@
+package version
+
+import "runtime"
+
+// 构建信息，通过 -ldflags 注入：
+//
+//	go build -ldflags "-X eight-gu-learning-platform/internal/version.Version=v1.0.0 \
+//	  -X eight-gu-learning-platform/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X eight-gu-learning-platform/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Info 获取构建信息
+func Info() BuildInfo {
+	return BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
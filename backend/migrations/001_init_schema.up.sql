@@ -43,7 +43,7 @@ CREATE TABLE IF NOT EXISTS knowledge_points (
     difficulty VARCHAR(20) CHECK (difficulty IN ('easy','medium','hard')),
     frequency VARCHAR(20) CHECK (frequency IN ('high','medium','low')),
     code_example TEXT,
-    references TEXT,
+    "references" TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP
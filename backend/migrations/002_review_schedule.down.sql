@@ -0,0 +1,11 @@
+-- 002_review_schedule.down.sql
+-- 回滚间隔复习调度字段
+
+DROP INDEX IF EXISTS idx_learning_progress_user_due;
+DROP INDEX IF EXISTS idx_learning_progress_due_at;
+
+ALTER TABLE learning_progress DROP COLUMN IF EXISTS due_at;
+ALTER TABLE learning_progress DROP COLUMN IF EXISTS lapses;
+ALTER TABLE learning_progress DROP COLUMN IF EXISTS repetitions;
+ALTER TABLE learning_progress DROP COLUMN IF EXISTS interval_days;
+ALTER TABLE learning_progress DROP COLUMN IF EXISTS ease_factor;
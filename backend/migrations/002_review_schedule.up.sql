@@ -0,0 +1,12 @@
+-- 002_review_schedule.up.sql
+-- 学习进度增加间隔复习（SM-2）调度字段
+
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS ease_factor DOUBLE PRECISION DEFAULT 2.5;
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS interval_days INTEGER DEFAULT 0;
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS repetitions INTEGER DEFAULT 0;
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS lapses INTEGER DEFAULT 0;
+ALTER TABLE learning_progress ADD COLUMN IF NOT EXISTS due_at TIMESTAMP;
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_learning_progress_due_at ON learning_progress(due_at);
+CREATE INDEX IF NOT EXISTS idx_learning_progress_user_due ON learning_progress(user_id, due_at);
@@ -0,0 +1,6 @@
+-- 003_user_roles.down.sql
+-- 回滚用户角色字段
+
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,10 @@
+-- 003_user_roles.up.sql
+-- 用户表增加角色字段
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'learner';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('learner','editor','admin'));
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
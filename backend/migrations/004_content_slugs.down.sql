@@ -0,0 +1,10 @@
+-- 004_content_slugs.down.sql
+-- 回滚内容 slug 字段
+
+DROP INDEX IF EXISTS idx_exercises_slug;
+DROP INDEX IF EXISTS idx_knowledge_points_slug;
+DROP INDEX IF EXISTS idx_categories_slug;
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS slug;
+ALTER TABLE knowledge_points DROP COLUMN IF EXISTS slug;
+ALTER TABLE categories DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,16 @@
+-- 004_content_slugs.up.sql
+-- 分类、知识点、练习题增加 slug，作为批量导入导出的稳定标识
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS slug VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE knowledge_points ADD COLUMN IF NOT EXISTS slug VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS slug VARCHAR(100) NOT NULL DEFAULT '';
+
+-- 为已有数据生成 slug
+UPDATE categories SET slug = 'category-' || id WHERE slug = '';
+UPDATE knowledge_points SET slug = 'knowledge-' || id WHERE slug = '';
+UPDATE exercises SET slug = 'exercise-' || id WHERE slug = '';
+
+-- 创建索引（包含已软删除的记录，导入时按 slug 恢复）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories(slug) WHERE slug <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_knowledge_points_slug ON knowledge_points(slug) WHERE slug <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_slug ON exercises(slug) WHERE slug <> '';
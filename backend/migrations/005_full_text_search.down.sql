@@ -0,0 +1,13 @@
+-- 005_full_text_search.down.sql
+-- 回滚全文检索
+
+DROP INDEX IF EXISTS idx_exercises_question_trgm;
+DROP INDEX IF EXISTS idx_knowledge_points_content_trgm;
+DROP INDEX IF EXISTS idx_knowledge_points_title_trgm;
+DROP INDEX IF EXISTS idx_exercises_search;
+DROP INDEX IF EXISTS idx_knowledge_points_search;
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE knowledge_points DROP COLUMN IF EXISTS search_vector;
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS eightgu_zh;
@@ -0,0 +1,39 @@
+-- 005_full_text_search.up.sql
+-- 知识点与练习题全文检索：tsvector 生成列 + GIN 索引，pg_trgm 兜底中文子串匹配
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 中文分词配置：数据库安装了 zhparser 时使用 zhparser 分词，
+-- 否则退化为 simple 配置（英文及代码标识符仍可检索，中文依赖 pg_trgm 子串匹配）
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'eightgu_zh') THEN
+        BEGIN
+            CREATE EXTENSION IF NOT EXISTS zhparser;
+            CREATE TEXT SEARCH CONFIGURATION eightgu_zh (PARSER = zhparser);
+            ALTER TEXT SEARCH CONFIGURATION eightgu_zh ADD MAPPING FOR n, v, a, i, e, l, j, x WITH simple;
+        EXCEPTION WHEN OTHERS THEN
+            CREATE TEXT SEARCH CONFIGURATION eightgu_zh (COPY = simple);
+        END;
+    END IF;
+END
+$$;
+
+ALTER TABLE knowledge_points ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('eightgu_zh'::regconfig, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('eightgu_zh'::regconfig, coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('eightgu_zh'::regconfig, coalesce(content, '')), 'C') ||
+    setweight(to_tsvector('eightgu_zh'::regconfig, coalesce(code_example, '')), 'D')
+) STORED;
+
+-- 练习题只检索题干：按解析匹配会让检索结果泄露答案
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('eightgu_zh'::regconfig, coalesce(question, '')), 'A')
+) STORED;
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_knowledge_points_search ON knowledge_points USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_exercises_search ON exercises USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_knowledge_points_title_trgm ON knowledge_points USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_knowledge_points_content_trgm ON knowledge_points USING GIN (content gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_exercises_question_trgm ON exercises USING GIN (question gin_trgm_ops);
@@ -0,0 +1,5 @@
+-- 006_exam_sessions.down.sql
+-- 回滚模拟面试考试
+
+DROP TABLE IF EXISTS exam_answers CASCADE;
+DROP TABLE IF EXISTS exam_sessions CASCADE;
@@ -0,0 +1,38 @@
+-- 006_exam_sessions.up.sql
+-- 模拟面试考试与答题记录
+
+CREATE TABLE IF NOT EXISTS exam_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    category_id INTEGER REFERENCES categories(id),
+    difficulty VARCHAR(20),
+    question_count INTEGER NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress','submitted','expired')),
+    started_at TIMESTAMP NOT NULL,
+    deadline TIMESTAMP NOT NULL,
+    submitted_at TIMESTAMP,
+    correct_count INTEGER DEFAULT 0,
+    score DOUBLE PRECISION DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS exam_answers (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES exam_sessions(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id),
+    position INTEGER NOT NULL,
+    user_answer JSONB DEFAULT '[]',
+    is_correct BOOLEAN DEFAULT FALSE,
+    answered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_exam_sessions_user_id ON exam_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_exam_sessions_status ON exam_sessions(status);
+CREATE INDEX IF NOT EXISTS idx_exam_sessions_deleted_at ON exam_sessions(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exam_answers_session_exercise ON exam_answers(session_id, exercise_id);
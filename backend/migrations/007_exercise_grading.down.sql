@@ -0,0 +1,10 @@
+-- 007_exercise_grading.down.sql
+-- 回滚练习题类型与得分字段
+
+ALTER TABLE exam_answers DROP COLUMN IF EXISTS score;
+ALTER TABLE exercise_records DROP COLUMN IF EXISTS score;
+
+-- 已有的新类型题目不做删除，约束只对新数据生效
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_type;
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_type
+    CHECK (type IN ('single_choice','multiple_choice')) NOT VALID;
@@ -0,0 +1,14 @@
+-- 007_exercise_grading.up.sql
+-- 练习题支持判断题、填空题、排序题；练习记录与考试答题增加得分（0~1，支持部分得分）
+
+-- 001 中的内联约束名为 exercises_type_check，AutoMigrate 创建的约束名为 chk_exercises_type
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS exercises_type_check;
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_type;
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_type
+    CHECK (type IN ('single_choice','multiple_choice','true_false','fill_blank','ordering'));
+
+ALTER TABLE exercise_records ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0;
+UPDATE exercise_records SET score = 1 WHERE is_correct;
+
+ALTER TABLE exam_answers ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0;
+UPDATE exam_answers SET score = 1 WHERE is_correct;
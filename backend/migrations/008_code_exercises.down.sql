@@ -0,0 +1,12 @@
+-- 008_code_exercises.down.sql
+-- 回滚代码题
+
+ALTER TABLE exercise_records DROP COLUMN IF EXISTS test_results;
+
+-- 已有的代码题不做删除，约束只对新数据生效
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_type;
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_type
+    CHECK (type IN ('single_choice','multiple_choice','true_false','fill_blank','ordering')) NOT VALID;
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS test_code;
+ALTER TABLE exercises DROP COLUMN IF EXISTS starter_code;
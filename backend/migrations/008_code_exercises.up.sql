@@ -0,0 +1,11 @@
+-- 008_code_exercises.up.sql
+-- 代码题：练习题增加初始代码与隐藏测试，练习记录保存每个测试的结果
+
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS starter_code TEXT;
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS test_code TEXT;
+
+ALTER TABLE exercises DROP CONSTRAINT IF EXISTS chk_exercises_type;
+ALTER TABLE exercises ADD CONSTRAINT chk_exercises_type
+    CHECK (type IN ('single_choice','multiple_choice','true_false','fill_blank','ordering','code'));
+
+ALTER TABLE exercise_records ADD COLUMN IF NOT EXISTS test_results JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,5 @@
+-- 009_wrong_exercise_marks.down.sql
+-- 回滚错题标记
+
+DROP INDEX IF EXISTS idx_exercise_records_user_exercise;
+DROP TABLE IF EXISTS wrong_exercise_marks CASCADE;
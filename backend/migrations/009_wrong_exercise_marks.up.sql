@@ -0,0 +1,16 @@
+-- 009_wrong_exercise_marks.up.sql
+-- 错题本：用户对错题的标记（已掌握 / 已移出）
+
+CREATE TABLE IF NOT EXISTS wrong_exercise_marks (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('mastered','dismissed')),
+    marked_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wrong_exercise_marks_user_exercise ON wrong_exercise_marks(user_id, exercise_id);
+CREATE INDEX IF NOT EXISTS idx_exercise_records_user_exercise ON exercise_records(user_id, exercise_id);
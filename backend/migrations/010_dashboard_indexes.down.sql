@@ -0,0 +1,5 @@
+-- 010_dashboard_indexes.down.sql
+-- 回滚学习看板索引
+
+DROP INDEX IF EXISTS idx_learning_progress_user_updated;
+DROP INDEX IF EXISTS idx_exercise_records_user_created;
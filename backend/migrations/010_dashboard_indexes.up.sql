@@ -0,0 +1,6 @@
+-- 010_dashboard_indexes.up.sql
+-- 学习看板：按用户和时间聚合练习记录与学习进度
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_exercise_records_user_created ON exercise_records(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_learning_progress_user_updated ON learning_progress(user_id, updated_at);
@@ -0,0 +1,4 @@
+-- 011_study_sessions.down.sql
+-- 回滚学习时长记录
+
+DROP TABLE IF EXISTS study_sessions;
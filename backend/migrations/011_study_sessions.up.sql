@@ -0,0 +1,23 @@
+-- 011_study_sessions.up.sql
+-- 学习时长记录：按知识点的学习会话，心跳超时自动结束
+
+CREATE TABLE IF NOT EXISTS study_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    knowledge_point_id INTEGER NOT NULL REFERENCES knowledge_points(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active','ended','expired')),
+    started_at TIMESTAMP NOT NULL,
+    last_heartbeat_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_study_sessions_user_started ON study_sessions(user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_study_sessions_knowledge_point_id ON study_sessions(knowledge_point_id);
+CREATE INDEX IF NOT EXISTS idx_study_sessions_deleted_at ON study_sessions(deleted_at);
+-- 后台只扫描进行中的会话
+CREATE INDEX IF NOT EXISTS idx_study_sessions_active ON study_sessions(last_heartbeat_at) WHERE status = 'active';
@@ -0,0 +1,4 @@
+-- 012_email_verification.down.sql
+-- 回滚邮箱验证
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,5 @@
+-- 012_email_verification.up.sql
+-- 邮箱验证：记录验证时间，已有用户视为已验证
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
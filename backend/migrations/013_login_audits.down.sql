@@ -0,0 +1,4 @@
+-- 013_login_audits.down.sql
+-- 回滚登录审计
+
+DROP TABLE IF EXISTS login_audits;
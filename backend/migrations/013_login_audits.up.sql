@@ -0,0 +1,17 @@
+-- 013_login_audits.up.sql
+-- 登录审计：记录成功与失败的登录
+
+CREATE TABLE IF NOT EXISTS login_audits (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(500),
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('success','invalid_credentials','locked','email_not_verified')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_login_audits_user_created ON login_audits(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_audits_ip_created ON login_audits(ip, created_at);
@@ -0,0 +1,5 @@
+-- 014_user_identities.down.sql
+-- 回滚第三方登录
+
+ALTER TABLE login_audits DROP COLUMN IF EXISTS method;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,20 @@
+-- 014_user_identities.up.sql
+-- 第三方登录：绑定到用户的外部账号，登录审计记录登录方式
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    login VARCHAR(255),
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 同一提供方的账号只能绑定一个用户，用户在同一提供方下只能绑定一个账号
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_provider_subject ON user_identities(provider, subject);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_user_provider ON user_identities(user_id, provider);
+
+ALTER TABLE login_audits ADD COLUMN IF NOT EXISTS method VARCHAR(60) NOT NULL DEFAULT 'password';
@@ -0,0 +1,10 @@
+-- 015_mfa.down.sql
+-- 回滚两步验证
+
+DELETE FROM login_audits WHERE reason IN ('mfa_required','mfa_failed');
+ALTER TABLE login_audits DROP CONSTRAINT IF EXISTS login_audits_reason_check;
+ALTER TABLE login_audits ADD CONSTRAINT login_audits_reason_check
+    CHECK (reason IN ('success','invalid_credentials','locked','email_not_verified'));
+
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
@@ -0,0 +1,26 @@
+-- 015_mfa.up.sql
+-- 两步验证：TOTP 密钥与恢复码，登录审计增加两步验证结果
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(255) NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 创建索引
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+ALTER TABLE login_audits DROP CONSTRAINT IF EXISTS login_audits_reason_check;
+ALTER TABLE login_audits ADD CONSTRAINT login_audits_reason_check
+    CHECK (reason IN ('success','invalid_credentials','locked','email_not_verified','mfa_required','mfa_failed'));
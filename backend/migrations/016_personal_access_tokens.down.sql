@@ -0,0 +1,4 @@
+-- 016_personal_access_tokens.down.sql
+-- 回滚个人访问令牌
+
+DROP TABLE IF EXISTS personal_access_tokens;
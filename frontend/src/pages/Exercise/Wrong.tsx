@@ -42,10 +42,14 @@ const WrongExercises = () => {
   };
 
   const handleDeleteFromWrongBook = async (id: number) => {
-    // TODO: 实现从错题本删除功能
-    message.success('已从错题本删除');
-    // 刷新列表
-    fetchWrongExercises();
+    try {
+      await exerciseService.markWrong(id, 'dismissed');
+      message.success('已从错题本删除');
+      // 刷新列表
+      fetchWrongExercises();
+    } catch (error: any) {
+      message.error('删除失败');
+    }
   };
 
   const getDifficultyColor = (difficulty: string) => {
@@ -0,0 +1,95 @@
+import { useEffect, useState } from 'react';
+import { Alert, Button, Form, Input, QRCode, Space, Typography, message } from 'antd';
+import { authService } from '../../services/auth';
+import type { AuthResponse, MFAEnrollment } from '../../types';
+
+interface MFAStepProps {
+  challenge: AuthResponse; // 第一步返回的 mfa_token 与类型
+  onSuccess: (result: AuthResponse) => void;
+  onCancel: () => void;
+}
+
+// 登录第二步：输入验证码；角色要求两步验证但未绑定时先扫码绑定，完成后展示恢复码
+const MFAStep = ({ challenge, onSuccess, onCancel }: MFAStepProps) => {
+  const [loading, setLoading] = useState(false);
+  const [enrollment, setEnrollment] = useState<MFAEnrollment>();
+  const [result, setResult] = useState<AuthResponse>();
+  const enroll = !!challenge.mfa_enrollment_required;
+  const mfaToken = challenge.mfa_token || '';
+
+  useEffect(() => {
+    if (!enroll) return;
+    authService.beginLoginEnrollment(mfaToken)
+      .then((res) => setEnrollment(res.data))
+      .catch((err: any) => message.error(err.message || '获取绑定密钥失败'));
+  }, [enroll, mfaToken]);
+
+  const onFinish = async ({ code }: { code: string }) => {
+    setLoading(true);
+    try {
+      const res = enroll
+        ? await authService.enableLoginMFA(mfaToken, code)
+        : await authService.loginMFA(mfaToken, code);
+      if (res.code === 0 && res.data?.token) {
+        // 新生成的恢复码只展示一次，确认保存后再进入系统
+        if (res.data.recovery_codes?.length) {
+          setResult(res.data);
+        } else {
+          onSuccess(res.data);
+        }
+      } else {
+        message.error(res.message || '验证失败');
+      }
+    } catch (error: any) {
+      message.error(error.message || '验证失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (result) {
+    return (
+      <Space direction="vertical" style={{ width: '100%' }}>
+        <Alert type="warning" showIcon message="请妥善保存恢复码" description="手机丢失时可以用恢复码代替验证码登录，每个只能使用一次，之后不会再显示。" />
+        <Typography.Paragraph copyable={{ text: result.recovery_codes!.join('\n') }}>
+          <pre style={{ margin: 0 }}>{result.recovery_codes!.join('\n')}</pre>
+        </Typography.Paragraph>
+        <Button type="primary" block onClick={() => onSuccess(result)}>我已保存，继续</Button>
+      </Space>
+    );
+  }
+
+  return (
+    <Form name="mfa" onFinish={onFinish} autoComplete="off" layout="vertical">
+      {enroll ? (
+        <>
+          <Alert type="info" showIcon style={{ marginBottom: 16 }} message="你的账号需要启用两步验证" description="使用验证器应用扫描二维码，然后输入应用中显示的 6 位验证码。" />
+          {enrollment && (
+            <Space direction="vertical" align="center" style={{ width: '100%', marginBottom: 16 }}>
+              <QRCode value={enrollment.provisioning_uri} />
+              <Typography.Text copyable code>{enrollment.secret}</Typography.Text>
+            </Space>
+          )}
+        </>
+      ) : (
+        <Alert type="info" showIcon style={{ marginBottom: 16 }} message="请输入验证器应用中的 6 位验证码，或使用恢复码" />
+      )}
+
+      <Form.Item name="code" rules={[{ required: true, message: '请输入验证码' }]}>
+        <Input placeholder={enroll ? '6 位验证码' : '验证码或恢复码'} autoFocus />
+      </Form.Item>
+
+      <Form.Item>
+        <Button type="primary" htmlType="submit" loading={loading} block disabled={enroll && !enrollment}>
+          {enroll ? '启用并登录' : '验证'}
+        </Button>
+      </Form.Item>
+
+      <div style={{ textAlign: 'center' }}>
+        <Button type="link" onClick={onCancel}>返回登录</Button>
+      </div>
+    </Form>
+  );
+};
+
+export default MFAStep;
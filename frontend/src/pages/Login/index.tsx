@@ -1,23 +1,43 @@
-import { useState } from 'react';
-import { Form, Input, Button, message, Card } from 'antd';
-import { useNavigate, Link } from 'react-router-dom';
+import { useEffect, useState } from 'react';
+import { Form, Input, Button, message, Card, Divider, Space } from 'antd';
+import { useNavigate, useLocation, Link } from 'react-router-dom';
 import { authService } from '../../services/auth';
 import { useAuth } from '../../hooks/useAuth';
 import type { LoginRequest } from '../../services/auth';
+import type { AuthResponse, OAuthProvider } from '../../types';
+import MFAStep from './MFAStep';
 
 const Login = () => {
   const [loading, setLoading] = useState(false);
+  const [providers, setProviders] = useState<OAuthProvider[]>([]);
   const navigate = useNavigate();
+  const location = useLocation();
   const { login } = useAuth();
+  // 需要两步验证时的挑战，第三方登录回调也会带过来
+  const [challenge, setChallenge] = useState<AuthResponse | undefined>(
+    (location.state as { mfa?: AuthResponse } | null)?.mfa,
+  );
+
+  useEffect(() => {
+    authService.getOAuthProviders()
+      .then((res) => setProviders(res.data || []))
+      .catch(() => setProviders([]));
+  }, []);
+
+  const finishLogin = (result: AuthResponse) => {
+    login(result.user, result.token!);
+    message.success('登录成功');
+    navigate('/');
+  };
 
   const onFinish = async (values: LoginRequest) => {
     setLoading(true);
     try {
       const res = await authService.login(values);
-      if (res.code === 0 && res.data) {
-        login(res.data.user, res.data.token);
-        message.success('登录成功');
-        navigate('/');
+      if (res.code === 0 && res.data?.token) {
+        finishLogin(res.data);
+      } else if (res.code === 0 && res.data?.mfa_token) {
+        setChallenge(res.data);
       } else {
         message.error(res.message || '登录失败');
       }
@@ -30,45 +50,69 @@ const Login = () => {
 
   return (
     <div style={{ display: 'flex', justifyContent: 'center', alignItems: 'center', minHeight: '100vh', background: '#f0f2f5' }}>
-      <Card title="登录" style={{ width: 400 }}>
-        <Form
-          name="login"
-          onFinish={onFinish}
-          autoComplete="off"
-          layout="vertical"
-        >
-          <Form.Item
-            label="邮箱"
-            name="email"
-            rules={[
-              { required: true, message: '请输入邮箱' },
-              { type: 'email', message: '请输入有效的邮箱' },
-            ]}
+      <Card title={challenge ? '两步验证' : '登录'} style={{ width: 400 }}>
+        {challenge ? (
+          <MFAStep
+            challenge={challenge}
+            onSuccess={finishLogin}
+            onCancel={() => {
+              setChallenge(undefined);
+              navigate('/login', { replace: true, state: null });
+            }}
+          />
+        ) : (
+          <Form
+            name="login"
+            onFinish={onFinish}
+            autoComplete="off"
+            layout="vertical"
           >
-            <Input placeholder="请输入邮箱" />
-          </Form.Item>
+            <Form.Item
+              label="邮箱"
+              name="email"
+              rules={[
+                { required: true, message: '请输入邮箱' },
+                { type: 'email', message: '请输入有效的邮箱' },
+              ]}
+            >
+              <Input placeholder="请输入邮箱" />
+            </Form.Item>
 
-          <Form.Item
-            label="密码"
-            name="password"
-            rules={[
-              { required: true, message: '请输入密码' },
-              { min: 6, message: '密码至少6位' },
-            ]}
-          >
-            <Input.Password placeholder="请输入密码" />
-          </Form.Item>
+            <Form.Item
+              label="密码"
+              name="password"
+              rules={[
+                { required: true, message: '请输入密码' },
+                { min: 6, message: '密码至少6位' },
+              ]}
+            >
+              <Input.Password placeholder="请输入密码" />
+            </Form.Item>
+
+            <Form.Item>
+              <Button type="primary" htmlType="submit" loading={loading} block>
+                登录
+              </Button>
+            </Form.Item>
 
-          <Form.Item>
-            <Button type="primary" htmlType="submit" loading={loading} block>
-              登录
-            </Button>
-          </Form.Item>
+            {providers.length > 0 && (
+              <>
+                <Divider plain>其他登录方式</Divider>
+                <Space direction="vertical" style={{ width: '100%', marginBottom: 16 }}>
+                  {providers.map((p) => (
+                    <Button key={p.name} block href={authService.oauthLoginUrl(p.name)}>
+                      使用 {p.display_name} 登录
+                    </Button>
+                  ))}
+                </Space>
+              </>
+            )}
 
-          <div style={{ textAlign: 'center' }}>
-            还没有账号？<Link to="/register">立即注册</Link>
-          </div>
-        </Form>
+            <div style={{ textAlign: 'center' }}>
+              还没有账号？<Link to="/register">立即注册</Link>
+            </div>
+          </Form>
+        )}
       </Card>
     </div>
   );
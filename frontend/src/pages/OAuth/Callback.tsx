@@ -0,0 +1,83 @@
+import { useEffect, useRef, useState } from 'react';
+import { Button, Card, Result, Spin, message } from 'antd';
+import { useNavigate, useSearchParams } from 'react-router-dom';
+import { authService } from '../../services/auth';
+import { useAuth } from '../../hooks/useAuth';
+
+// 回调错误码对应的提示
+const errorMessages: Record<string, string> = {
+  access_denied: '你取消了授权',
+  invalid_state: '登录请求无效或已过期，请重新登录',
+  email_required: '第三方账号没有已验证的邮箱，请先在对应平台验证邮箱',
+  identity_in_use: '该第三方账号已绑定其他用户',
+  already_linked: '已绑定该登录方式的其他账号，请先解绑',
+  failed: '第三方登录失败，请稍后重试',
+};
+
+const OAuthCallback = () => {
+  const [searchParams] = useSearchParams();
+  const [error, setError] = useState<string>();
+  const navigate = useNavigate();
+  const { login } = useAuth();
+  // 登录码只能使用一次，避免开发模式下 effect 重复执行
+  const handled = useRef(false);
+
+  useEffect(() => {
+    if (handled.current) return;
+    handled.current = true;
+
+    const errorCode = searchParams.get('error');
+    const linked = searchParams.get('linked');
+    const code = searchParams.get('code');
+
+    if (errorCode) {
+      setError(errorMessages[errorCode] || errorMessages.failed);
+      return;
+    }
+    if (linked) {
+      message.success('绑定成功');
+      navigate('/profile', { replace: true });
+      return;
+    }
+    if (!code) {
+      setError(errorMessages.invalid_state);
+      return;
+    }
+
+    authService.exchangeOAuthCode(code)
+      .then((res) => {
+        if (res.code === 0 && res.data?.token) {
+          login(res.data.user, res.data.token);
+          message.success('登录成功');
+          navigate('/', { replace: true });
+        } else if (res.code === 0 && res.data?.mfa_token) {
+          // 需要两步验证，交给登录页完成第二步
+          navigate('/login', { replace: true, state: { mfa: res.data } });
+        } else {
+          setError(res.message || errorMessages.failed);
+        }
+      })
+      .catch((err: any) => setError(err.message || errorMessages.failed));
+  }, [searchParams, navigate, login]);
+
+  return (
+    <div style={{ display: 'flex', justifyContent: 'center', alignItems: 'center', minHeight: '100vh', background: '#f0f2f5' }}>
+      <Card style={{ width: 400 }}>
+        {error ? (
+          <Result
+            status="error"
+            title="登录失败"
+            subTitle={error}
+            extra={<Button type="primary" onClick={() => navigate('/login', { replace: true })}>返回登录</Button>}
+          />
+        ) : (
+          <div style={{ textAlign: 'center', padding: 24 }}>
+            <Spin tip="正在登录..." />
+          </div>
+        )}
+      </Card>
+    </div>
+  );
+};
+
+export default OAuthCallback;
@@ -14,7 +14,10 @@ const Register = () => {
     setLoading(true);
     try {
       const res = await authService.register(values);
-      if (res.code === 0 && res.data) {
+      if (res.code === 0 && res.data?.verification_required) {
+        message.success('注册成功，请查收验证邮件后登录');
+        navigate('/login');
+      } else if (res.code === 0 && res.data?.token) {
         login(res.data.user, res.data.token);
         message.success('注册成功');
         navigate('/');
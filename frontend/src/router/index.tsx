@@ -4,6 +4,7 @@ import AppLayout from '../components/layout/AppLayout';
 import Home from '../pages/Home/index';
 import Login from '../pages/Login/index';
 import Register from '../pages/Register/index';
+import OAuthCallback from '../pages/OAuth/Callback';
 import KnowledgeList from '../pages/Knowledge/List';
 import KnowledgeDetail from '../pages/Knowledge/Detail';
 import LearningDashboard from '../pages/Learning/Dashboard';
@@ -36,6 +37,10 @@ const router = createBrowserRouter([
     path: '/register',
     element: <Register />,
   },
+  {
+    path: '/oauth/callback',
+    element: <OAuthCallback />,
+  },
   {
     path: '/knowledge',
     element: <AppLayout><KnowledgeList /></AppLayout>,
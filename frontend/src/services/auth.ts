@@ -1,5 +1,8 @@
-import api from './api';
-import { ApiResponse, AuthResponse, User } from '../types';
+import api, { API_BASE_URL } from './api';
+import {
+  ApiResponse, AuthResponse, CreatedPersonalToken, LoginAudit, MFAEnrollment, MFAStatus, OAuthProvider, PageResponse,
+  PersonalAccessToken, TokenScope, User, UserIdentity,
+} from '../types';
 
 export interface RegisterRequest {
   email: string;
@@ -12,6 +15,12 @@ export interface LoginRequest {
   password: string;
 }
 
+export interface CreatePersonalTokenRequest {
+  name: string;
+  scopes: TokenScope[];
+  expires_in_days?: number; // 不填则不过期
+}
+
 export const authService = {
   // 用户注册
   register(data: RegisterRequest): Promise<ApiResponse<AuthResponse>> {
@@ -23,6 +32,121 @@ export const authService = {
     return api.post('/api/v1/auth/login', data);
   },
 
+  // 登录第二步：验证码或恢复码
+  loginMFA(mfaToken: string, code: string): Promise<ApiResponse<AuthResponse>> {
+    return api.post('/api/v1/auth/login/mfa', { mfa_token: mfaToken, code });
+  },
+
+  // 登录时绑定两步验证：获取密钥与二维码地址
+  beginLoginEnrollment(mfaToken: string): Promise<ApiResponse<MFAEnrollment>> {
+    return api.post('/api/v1/auth/login/mfa/enroll', { mfa_token: mfaToken });
+  },
+
+  // 登录时确认绑定，返回令牌与恢复码
+  enableLoginMFA(mfaToken: string, code: string): Promise<ApiResponse<AuthResponse>> {
+    return api.post('/api/v1/auth/login/mfa/enable', { mfa_token: mfaToken, code });
+  },
+
+  // 验证邮箱
+  verifyEmail(token: string): Promise<ApiResponse<User>> {
+    return api.post('/api/v1/auth/email/verify', { token });
+  },
+
+  // 重发验证邮件
+  resendVerification(email: string): Promise<ApiResponse<null>> {
+    return api.post('/api/v1/auth/email/resend', { email });
+  },
+
+  // 发送重置密码邮件
+  forgotPassword(email: string): Promise<ApiResponse<null>> {
+    return api.post('/api/v1/auth/password/forgot', { email });
+  },
+
+  // 使用邮件中的令牌重置密码
+  resetPassword(token: string, password: string): Promise<ApiResponse<null>> {
+    return api.post('/api/v1/auth/password/reset', { token, password });
+  },
+
+  // 修改密码
+  changePassword(oldPassword: string, newPassword: string): Promise<ApiResponse<AuthResponse>> {
+    return api.put('/api/v1/users/me/password', { old_password: oldPassword, new_password: newPassword });
+  },
+
+  // 获取登录记录
+  getLogins(params?: { page?: number; page_size?: number }): Promise<ApiResponse<PageResponse<LoginAudit>>> {
+    return api.get('/api/v1/users/me/logins', { params });
+  },
+
+  // 获取已启用的第三方登录方式
+  getOAuthProviders(): Promise<ApiResponse<OAuthProvider[]>> {
+    return api.get('/api/v1/auth/oauth/providers');
+  },
+
+  // 第三方登录地址（浏览器直接跳转）
+  oauthLoginUrl(provider: string): string {
+    return `${API_BASE_URL}/api/v1/auth/oauth/${encodeURIComponent(provider)}`;
+  },
+
+  // 使用回调中的一次性登录码换取令牌
+  exchangeOAuthCode(code: string): Promise<ApiResponse<AuthResponse>> {
+    return api.post('/api/v1/auth/oauth/exchange', { code });
+  },
+
+  // 获取已绑定的第三方账号
+  getIdentities(): Promise<ApiResponse<UserIdentity[]>> {
+    return api.get('/api/v1/users/me/identities');
+  },
+
+  // 发起绑定第三方账号，返回授权地址；响应写入的 state Cookie 在回调时校验，跨域时也需要保存
+  linkIdentity(provider: string): Promise<ApiResponse<{ url: string }>> {
+    return api.post(`/api/v1/users/me/identities/${encodeURIComponent(provider)}`, undefined, { withCredentials: true });
+  },
+
+  // 解绑第三方账号
+  unlinkIdentity(provider: string): Promise<ApiResponse<null>> {
+    return api.delete(`/api/v1/users/me/identities/${encodeURIComponent(provider)}`);
+  },
+
+  // 获取两步验证状态
+  getMFAStatus(): Promise<ApiResponse<MFAStatus>> {
+    return api.get('/api/v1/users/me/mfa');
+  },
+
+  // 开始绑定两步验证
+  enrollMFA(): Promise<ApiResponse<MFAEnrollment>> {
+    return api.post('/api/v1/users/me/mfa/enroll');
+  },
+
+  // 确认绑定并启用两步验证，返回恢复码
+  enableMFA(code: string): Promise<ApiResponse<{ recovery_codes: string[] }>> {
+    return api.post('/api/v1/users/me/mfa/enable', { code });
+  },
+
+  // 关闭两步验证
+  disableMFA(password: string, code: string): Promise<ApiResponse<null>> {
+    return api.post('/api/v1/users/me/mfa/disable', { password, code });
+  },
+
+  // 重新生成恢复码
+  regenerateRecoveryCodes(code: string): Promise<ApiResponse<{ recovery_codes: string[] }>> {
+    return api.post('/api/v1/users/me/mfa/recovery-codes', { code });
+  },
+
+  // 获取个人访问令牌
+  getTokens(): Promise<ApiResponse<PersonalAccessToken[]>> {
+    return api.get('/api/v1/users/me/tokens');
+  },
+
+  // 创建个人访问令牌
+  createToken(data: CreatePersonalTokenRequest): Promise<ApiResponse<CreatedPersonalToken>> {
+    return api.post('/api/v1/users/me/tokens', data);
+  },
+
+  // 吊销个人访问令牌
+  revokeToken(id: number): Promise<ApiResponse<null>> {
+    return api.delete(`/api/v1/users/me/tokens/${id}`);
+  },
+
   // 获取当前用户信息
   getMe(): Promise<ApiResponse<User>> {
     return api.get('/api/v1/auth/me');
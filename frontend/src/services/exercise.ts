@@ -1,5 +1,5 @@
 import api from './api';
-import { ApiResponse, Exercise, PageResponse, WrongExercise } from '../types';
+import { ApiResponse, Exercise, ExerciseDetail, PageResponse, WrongExercise } from '../types';
 
 export const exerciseService = {
   // 获取练习题列表
@@ -13,7 +13,7 @@ export const exerciseService = {
   },
 
   // 获取练习题详情
-  getById(id: number): Promise<ApiResponse<Exercise>> {
+  getById(id: number): Promise<ApiResponse<ExerciseDetail>> {
     return api.get(`/api/v1/exercises/${id}`);
   },
 
@@ -31,7 +31,22 @@ export const exerciseService = {
   getWrongList(params?: {
     page?: number;
     page_size?: number;
+    status?: 'outstanding' | 'mastered' | 'dismissed' | 'all';
+    knowledge_id?: number;
   }): Promise<ApiResponse<PageResponse<WrongExercise>>> {
     return api.get('/api/v1/exercises/wrong', { params });
   },
+
+  // 标记错题
+  markWrong(id: number, status: 'mastered' | 'dismissed' | 'outstanding'): Promise<ApiResponse<null>> {
+    return api.put(`/api/v1/exercises/wrong/${id}`, { status });
+  },
+
+  // 错题再练习
+  practiceWrong(params?: {
+    count?: number;
+    knowledge_id?: number;
+  }): Promise<ApiResponse<{ items: Exercise[]; total: number }>> {
+    return api.post('/api/v1/exercises/wrong/practice', params ?? {});
+  },
 };
@@ -1,5 +1,5 @@
 import api from './api';
-import { ApiResponse, KnowledgePoint, GraphData, PageResponse, Category } from '../types';
+import { ApiResponse, KnowledgePoint, GraphData, GraphPath, PageResponse, Category, CategoryNode } from '../types';
 
 export const knowledgeService = {
   // 获取分类列表
@@ -7,6 +7,11 @@ export const knowledgeService = {
     return api.get('/api/v1/categories');
   },
 
+  // 获取分类树
+  getCategoryTree(params?: { root?: number; progress?: boolean }): Promise<ApiResponse<CategoryNode[]>> {
+    return api.get('/api/v1/categories/tree', { params });
+  },
+
   // 获取知识点列表
   getList(params: {
     page?: number;
@@ -29,4 +34,14 @@ export const knowledgeService = {
     const params = categoryId ? { category_id: categoryId } : {};
     return api.get('/api/v1/knowledge/graph', { params });
   },
+
+  // 获取知识点邻域子图
+  getNeighbors(id: number, depth = 1, type?: string): Promise<ApiResponse<GraphData>> {
+    return api.get(`/api/v1/knowledge/${id}/neighbors`, { params: { depth, type } });
+  },
+
+  // 获取两个知识点之间的最短路径
+  getShortestPath(from: number, to: number, directed = false): Promise<ApiResponse<GraphPath>> {
+    return api.get('/api/v1/knowledge/path', { params: { from, to, directed } });
+  },
 };
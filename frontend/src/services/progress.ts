@@ -1,5 +1,5 @@
 import api from './api';
-import { ApiResponse, LearningProgress, LearningStats } from '../types';
+import { ApiResponse, LearningDashboard, LearningPath, LearningProgress, LearningStats, StudySession, TopicRecommendation } from '../types';
 
 export interface UpdateProgressRequest {
   knowledge_point_id: number;
@@ -25,4 +25,34 @@ export const progressService = {
   getStats(): Promise<ApiResponse<LearningStats>> {
     return api.get('/api/v1/learning/stats');
   },
+
+  // 获取学习看板
+  getDashboard(days?: number): Promise<ApiResponse<LearningDashboard>> {
+    return api.get('/api/v1/learning/dashboard', { params: { days } });
+  },
+
+  // 获取到达目标知识点的学习路径
+  getPath(target: number): Promise<ApiResponse<LearningPath>> {
+    return api.get('/api/v1/learning/path', { params: { target } });
+  },
+
+  // 获取推荐学习的知识点
+  getRecommendations(limit?: number): Promise<ApiResponse<TopicRecommendation[]>> {
+    return api.get('/api/v1/learning/recommendations', { params: { limit } });
+  },
+
+  // 开始学习会话
+  startSession(knowledgePointId: number): Promise<ApiResponse<StudySession>> {
+    return api.post('/api/v1/learning/sessions', { knowledge_point_id: knowledgePointId });
+  },
+
+  // 学习会话心跳
+  heartbeat(sessionId: number): Promise<ApiResponse<StudySession>> {
+    return api.post(`/api/v1/learning/sessions/${sessionId}/heartbeat`);
+  },
+
+  // 结束学习会话
+  endSession(sessionId: number): Promise<ApiResponse<StudySession>> {
+    return api.post(`/api/v1/learning/sessions/${sessionId}/end`);
+  },
 };
@@ -19,6 +19,7 @@ export interface User {
   email: string;
   username: string;
   avatar: string;
+  email_verified_at: string | null;
   created_at: string;
   updated_at: string;
 }
@@ -33,6 +34,22 @@ export interface Category {
   created_at: string;
 }
 
+// Category Tree
+export interface CategoryNode {
+  id: number;
+  name: string;
+  slug: string;
+  description: string;
+  parent_id: number | null;
+  sort_order: number;
+  depth: number;
+  knowledge_count: number;
+  direct_knowledge_count: number;
+  completed_count?: number;
+  completion_rate?: number;
+  children: CategoryNode[];
+}
+
 // Knowledge Point
 export interface KnowledgePoint {
   id: number;
@@ -56,7 +73,14 @@ export interface GraphNode {
   data: {
     id: number;
     title: string;
+    slug: string;
+    category_id: number;
     difficulty: string;
+    frequency: string;
+    status: 'not_started' | 'in_progress' | 'completed';
+    mastery_level: number;
+    mastered: boolean;
+    depth?: number; // 邻域查询中与中心的距离
   };
 }
 
@@ -75,6 +99,13 @@ export interface GraphData {
   edges: GraphEdge[];
 }
 
+// Graph Path
+export interface GraphPath extends GraphData {
+  found: boolean;
+  length: number;
+  path: number[];
+}
+
 // Learning Progress
 export interface LearningProgress {
   id: number;
@@ -88,6 +119,31 @@ export interface LearningProgress {
   updated_at: string;
 }
 
+// Learning Path
+export interface PathStep {
+  knowledge_point_id: number;
+  title: string;
+  slug: string;
+  category_id: number;
+  difficulty: 'easy' | 'medium' | 'hard';
+  frequency: 'high' | 'medium' | 'low';
+  status: 'not_started' | 'in_progress' | 'completed';
+  mastery_level: number;
+  weight: number;
+  prerequisites: number[];
+}
+
+export interface LearningPath {
+  target_id: number;
+  mastered: boolean;
+  steps: PathStep[];
+  total: number;
+}
+
+export interface TopicRecommendation extends PathStep {
+  unlocks: number;
+}
+
 // Learning Stats
 export interface LearningStats {
   total: number;
@@ -95,6 +151,78 @@ export interface LearningStats {
   in_progress: number;
   not_started: number;
   mastery_avg: number;
+  study_seconds: number;
+}
+
+// Study Session
+export interface StudySession {
+  id: number;
+  user_id: number;
+  knowledge_point_id: number;
+  status: 'active' | 'ended' | 'expired';
+  started_at: string;
+  last_heartbeat_at: string;
+  ended_at: string | null;
+  duration_seconds: number;
+  idle_timeout_seconds: number;
+}
+
+// Learning Dashboard
+export interface DailyActivity {
+  date: string;
+  exercises: number;
+  correct: number;
+  score: number;
+  reviewed: number;
+  study_seconds: number;
+}
+
+export interface AccuracyStat {
+  key: string;
+  label: string;
+  attempts: number;
+  correct: number;
+  avg_score: number;
+}
+
+export interface WeakPoint {
+  knowledge_point_id: number;
+  title: string;
+  category_id: number;
+  frequency: string;
+  mastery_level: number;
+  lapses: number;
+  attempts: number;
+  accuracy: number | null;
+}
+
+export interface FrequencyCoverage {
+  frequency: string;
+  total: number;
+  started: number;
+  mastered: number;
+}
+
+export interface LearningDashboard {
+  from: string;
+  to: string;
+  summary: LearningStats & {
+    exercises: number;
+    accuracy: number;
+    active_days: number;
+    period_study_seconds: number;
+  };
+  activity: DailyActivity[];
+  streak: {
+    current: number;
+    longest: number;
+    last_active?: string;
+  };
+  accuracy_by_category: AccuracyStat[];
+  accuracy_by_difficulty: AccuracyStat[];
+  weakest_points: WeakPoint[];
+  coverage: FrequencyCoverage[];
+  next_topics: TopicRecommendation[];
 }
 
 // Exercise
@@ -103,12 +231,20 @@ export interface Exercise {
   knowledge_point_id: number;
   question: string;
   options: string[];
-  type: 'single_choice' | 'multiple_choice';
-  explanation: string;
+  type: 'single_choice' | 'multiple_choice' | 'true_false' | 'fill_blank' | 'ordering' | 'code';
+  starter_code?: string;
   difficulty: 'easy' | 'medium' | 'hard';
   created_at: string;
 }
 
+// Exercise Detail（答案和解析只在达到公开条件时返回）
+export interface ExerciseDetail extends Exercise {
+  attempts: number;
+  revealed: boolean;
+  answer?: string;
+  explanation?: string;
+}
+
 // Exercise Record
 export interface ExerciseRecord {
   id: number;
@@ -117,21 +253,95 @@ export interface ExerciseRecord {
   exercise?: Exercise;
   user_answer: string[];
   is_correct: boolean;
+  score: number;
   created_at: string;
 }
 
 // Wrong Exercise
 export interface WrongExercise {
-  id: number;
+  id: number; // 练习题 ID
+  knowledge_point_id: number;
+  type: Exercise['type'];
+  difficulty: 'easy' | 'medium' | 'hard';
   question: string;
   user_answer: string;
   correct_answer: string;
   explanation: string;
   wrong_count: number;
+  attempt_count: number;
+  last_attempt_at: string;
+  last_wrong_at: string;
+  last_correct_at: string | null;
+  corrected: boolean;
+  status: 'outstanding' | 'mastered' | 'dismissed';
 }
 
 // Auth Response
 export interface AuthResponse {
-  user: User;
+  user?: User; // 需要两步验证时不返回
+  token?: string;
+  refresh_token?: string;
+  expires_in?: number;
+  verification_required?: boolean;
+  mfa_required?: boolean; // 需要输入验证码
+  mfa_enrollment_required?: boolean; // 角色要求两步验证，需要先完成绑定
+  mfa_token?: string;
+  recovery_codes?: string[]; // 登录时完成绑定生成的恢复码
+}
+
+export interface MFAStatus {
+  enabled: boolean;
+  enabled_at?: string;
+  required: boolean;
+  recovery_codes_remaining: number;
+}
+
+export interface MFAEnrollment {
+  secret: string;
+  provisioning_uri: string;
+}
+
+export type TokenScope = 'read' | 'write' | 'admin';
+
+export interface PersonalAccessToken {
+  id: number;
+  name: string;
+  prefix: string; // 令牌开头几位，便于辨认
+  scopes: TokenScope[];
+  last_used_at?: string;
+  expires_at?: string; // 为空表示不过期
+  created_at: string;
+}
+
+// 新建的令牌，token 明文只返回这一次
+export interface CreatedPersonalToken extends PersonalAccessToken {
   token: string;
 }
+
+export interface OAuthProvider {
+  name: string;
+  display_name: string;
+  type: 'github' | 'oidc';
+}
+
+export interface UserIdentity {
+  id: number;
+  provider: string;
+  email: string;
+  login: string;
+  last_login_at?: string;
+  created_at: string;
+}
+
+export type LoginReason = 'success' | 'invalid_credentials' | 'locked' | 'email_not_verified' | 'mfa_required' | 'mfa_failed';
+
+export interface LoginAudit {
+  id: number;
+  email: string;
+  ip: string;
+  user_agent: string;
+  success: boolean;
+  reason: LoginReason;
+  method: string; // password 或 oauth:{provider}
+  created_at: string;
+}